@@ -3,20 +3,28 @@ package main
 import (
 	"context"
 	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
 	"strings"
 	"syscall"
+	"text/tabwriter"
 	"time"
 
 	"github.com/goonsorrow/finance-tracker-api/configs"
 	"github.com/goonsorrow/finance-tracker-api/internal/app"
 	"github.com/goonsorrow/finance-tracker-api/internal/cache"
+	"github.com/goonsorrow/finance-tracker-api/internal/currency"
 	"github.com/goonsorrow/finance-tracker-api/internal/handler"
 	"github.com/goonsorrow/finance-tracker-api/internal/logger"
+	"github.com/goonsorrow/finance-tracker-api/internal/models"
 	"github.com/goonsorrow/finance-tracker-api/internal/repository"
 	"github.com/goonsorrow/finance-tracker-api/internal/service"
+	"github.com/goonsorrow/finance-tracker-api/internal/storage"
+	"github.com/goonsorrow/finance-tracker-api/internal/worker"
 	"github.com/spf13/viper"
 )
 
@@ -64,6 +72,15 @@ func main() {
 		os.Exit(1)
 	}
 
+	repo := repository.NewRepository(db)
+
+	// Административные команды работают только с базой и завершают процесс
+	if len(os.Args) > 1 {
+		code := runCommand(ctx, os.Args[1:], repo, cfg, slogger)
+		_ = db.Close()
+		os.Exit(code)
+	}
+
 	rdb := cache.NewRedis(cache.Config{
 		Host:     cfg.Redis.Host,
 		Port:     cfg.Redis.Port,
@@ -77,14 +94,32 @@ func main() {
 		return
 	}
 
-	repo := repository.NewRepository(db)
+	blobs, err := newBlobStore(cfg.Storage)
+	if err != nil {
+		slogger.Error("error occured while initialising attachment storage:", "err", err)
+		os.Exit(1)
+	}
+
 	cache := cache.NewCache(rdb)
-	service := service.NewService(repo, cache, slogger, cfg)
+	service := service.NewService(repo, cache, blobs, slogger, cfg)
 	handler := handler.NewHandler(service, slogger)
+	routes, err := handler.InitRoutes(cfg.Server.TrustedProxies)
+	if err != nil {
+		slogger.Error("error occured while initialising routes:", "err", err)
+		os.Exit(1)
+	}
 	srv := new(app.Server)
 
+	if provider := newRateProvider(cfg.Rates); provider != nil {
+		refresher := currency.NewRefresher(provider, repo.Rates, slogger)
+		go worker.Run(ctx, slogger, "rates-refresh", parseInterval(slogger, cfg.Rates.RefreshInterval, 12*time.Hour), refresher.Refresh)
+	}
+	go worker.Run(ctx, slogger, "recurring-movements", parseInterval(slogger, cfg.Recurring.Interval, 5*time.Minute), service.Recurring.MaterializeDue)
+	go worker.Run(ctx, slogger, "trash-purge", parseInterval(slogger, cfg.Trash.PurgeInterval, time.Hour), service.Trash.Purge)
+	go worker.Run(ctx, slogger, "balance-verify", parseInterval(slogger, cfg.Integrity.VerifyInterval, 6*time.Hour), service.Integrity.Verify)
+
 	go func() {
-		if err := srv.Run(cfg.Server.Port, handler.InitRoutes()); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		if err := srv.Run(cfg.Server.Port, routes); err != nil && !errors.Is(err, http.ErrServerClosed) {
 			slogger.Error("Error occured while running http server", "error", err)
 			stop()
 		}
@@ -106,6 +141,138 @@ func main() {
 
 }
 
+// runCommand выполняет административную команду вместо запуска сервера и возвращает код выхода
+func runCommand(ctx context.Context, args []string, repo *repository.Repository, cfg configs.Config, logger *slog.Logger) int {
+	switch args[0] {
+	case "balances":
+		return runBalances(ctx, args[1:], repo, logger)
+	case "rates-backfill":
+		return runRatesBackfill(ctx, args[1:], repo, cfg.Rates, logger)
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q, available: balances, rates-backfill\n", args[0])
+		return 2
+	}
+}
+
+// runRatesBackfill загружает из провайдера курсы за прошедший период: обновление по расписанию
+// приносит только курсы на сегодня
+func runRatesBackfill(ctx context.Context, args []string, repo *repository.Repository, cfg configs.RatesConfig, logger *slog.Logger) int {
+	flags := flag.NewFlagSet("rates-backfill", flag.ContinueOnError)
+	fromFlag := flags.String("from", "", "first day to load, YYYY-MM-DD")
+	toFlag := flags.String("to", "", "last day to load, YYYY-MM-DD (default today)")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	from, err := time.Parse(time.DateOnly, *fromFlag)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "-from must be a date in YYYY-MM-DD format")
+		return 2
+	}
+	to := time.Now().UTC()
+	if *toFlag != "" {
+		if to, err = time.Parse(time.DateOnly, *toFlag); err != nil {
+			fmt.Fprintln(os.Stderr, "-to must be a date in YYYY-MM-DD format")
+			return 2
+		}
+	}
+
+	provider := newRateProvider(cfg)
+	if provider == nil {
+		fmt.Fprintf(os.Stderr, "rates provider %q cannot be used for backfill\n", cfg.Provider)
+		return 2
+	}
+	saved, err := currency.NewRefresher(provider, repo.Rates, logger).Backfill(ctx, from, to)
+	if err != nil {
+		logger.Error("error occured while backfilling rates:", "err", err)
+		return 1
+	}
+	fmt.Printf("saved %d rate(s)\n", saved)
+	return 0
+}
+
+// runBalances сверяет балансы кошельков с суммами операций, с -repair исправляет расхождения.
+// Код выхода 1 означает, что расхождения найдены и не исправлены
+func runBalances(ctx context.Context, args []string, repo *repository.Repository, logger *slog.Logger) int {
+	flags := flag.NewFlagSet("balances", flag.ContinueOnError)
+	repair := flags.Bool("repair", false, "rewrite mismatching balances with the ones recomputed from movements")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	integrity := service.NewIntegrityService(repo.Integrity, service.NewLedger(repo.Ledger), service.NewAuditor(repo.Audit), repo.Transactor, logger)
+	var mismatches []models.BalanceMismatch
+	var err error
+	if *repair {
+		mismatches, err = integrity.Repair(ctx)
+	} else {
+		mismatches, err = integrity.Check(ctx)
+	}
+	if err != nil {
+		logger.Error("error occured while checking balances:", "err", err)
+		return 1
+	}
+
+	if len(mismatches) == 0 {
+		fmt.Println("all wallet balances match their movements")
+		return 0
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "WALLET\tUSER\tCURRENCY\tSTORED\tCOMPUTED\tDIFF")
+	for _, m := range mismatches {
+		fmt.Fprintf(w, "%d\t%d\t%s\t%s\t%s\t%s\n", m.WalletID, m.UserID, m.Currency,
+			currency.FormatAmount(m.Stored, m.Currency),
+			currency.FormatAmount(m.Computed, m.Currency),
+			currency.FormatAmount(m.Diff(), m.Currency))
+	}
+	_ = w.Flush()
+
+	if *repair {
+		fmt.Printf("repaired %d wallet(s)\n", len(mismatches))
+		return 0
+	}
+	fmt.Printf("%d wallet(s) out of sync, run with -repair to fix\n", len(mismatches))
+	return 1
+}
+
+func newRateProvider(cfg configs.RatesConfig) currency.RateProvider {
+	switch cfg.Provider {
+	case "file":
+		return currency.NewFileProvider(cfg.FilePath)
+	case "http":
+		return currency.NewHTTPProvider(cfg.HTTPURL, cfg.Base)
+	default:
+		return nil
+	}
+}
+
+func newBlobStore(cfg configs.StorageConfig) (storage.BlobStore, error) {
+	switch cfg.Driver {
+	case "s3":
+		return storage.NewS3Store(storage.S3Config{
+			Endpoint:  cfg.S3.Endpoint,
+			Region:    cfg.S3.Region,
+			Bucket:    cfg.S3.Bucket,
+			AccessKey: cfg.S3.AccessKey,
+			SecretKey: cfg.S3.SecretKey,
+		})
+	case "local", "":
+		return storage.NewLocalStore(cfg.LocalPath), nil
+	default:
+		return nil, fmt.Errorf("unknown storage driver %q", cfg.Driver)
+	}
+}
+
+func parseInterval(logger *slog.Logger, value string, fallback time.Duration) time.Duration {
+	interval, err := time.ParseDuration(value)
+	if err != nil || interval <= 0 {
+		logger.Warn("invalid interval in config, using default", "value", value, "default", fallback)
+		return fallback
+	}
+	return interval
+}
+
 func InitConfig() error {
 	viper.AddConfigPath("configs")
 	viper.SetConfigName("config")
@@ -123,6 +290,7 @@ func InitConfig() error {
 
 	// Server
 	_ = viper.BindEnv("server.port", "SERVER_PORT")
+	_ = viper.BindEnv("server.trusted_proxies", "TRUSTED_PROXIES")
 
 	// DB
 	_ = viper.BindEnv("db.host", "DB_HOST")
@@ -135,8 +303,49 @@ func InitConfig() error {
 	_ = viper.BindEnv("redis.host", "REDIS_HOST")
 	_ = viper.BindEnv("redis.port", "REDIS_PORT")
 	_ = viper.BindEnv("redis.password", "REDIS_PASSWORD")
+	// Rates
+	_ = viper.BindEnv("rates.provider", "RATES_PROVIDER")
+	_ = viper.BindEnv("rates.file_path", "RATES_FILE_PATH")
+	_ = viper.BindEnv("rates.http_url", "RATES_HTTP_URL")
+	_ = viper.BindEnv("rates.base", "RATES_BASE")
+	_ = viper.BindEnv("rates.refresh_interval", "RATES_REFRESH_INTERVAL")
+	// Recurring
+	_ = viper.BindEnv("recurring.interval", "RECURRING_INTERVAL")
+	// Duplicates
+	_ = viper.BindEnv("duplicates.window", "DUPLICATE_WINDOW")
+	// Attachments
+	_ = viper.BindEnv("storage.driver", "STORAGE_DRIVER")
+	_ = viper.BindEnv("storage.local_path", "STORAGE_LOCAL_PATH")
+	_ = viper.BindEnv("storage.max_size", "ATTACHMENT_MAX_SIZE")
+	_ = viper.BindEnv("storage.s3.endpoint", "S3_ENDPOINT")
+	_ = viper.BindEnv("storage.s3.region", "S3_REGION")
+	_ = viper.BindEnv("storage.s3.bucket", "S3_BUCKET")
+	_ = viper.BindEnv("storage.s3.access_key", "S3_ACCESS_KEY")
+	_ = viper.BindEnv("storage.s3.secret_key", "S3_SECRET_KEY")
+	// Trash
+	_ = viper.BindEnv("trash.retention", "TRASH_RETENTION")
+	_ = viper.BindEnv("trash.purge_interval", "TRASH_PURGE_INTERVAL")
+	// Integrity
+	_ = viper.BindEnv("integrity.verify_interval", "BALANCE_VERIFY_INTERVAL")
+	// Idempotency
+	_ = viper.BindEnv("idempotency.window", "IDEMPOTENCY_WINDOW")
 
 	viper.SetDefault("server.port", "8080")
 	viper.SetDefault("db.sslmode", "disable")
+	viper.SetDefault("rates.provider", "file")
+	viper.SetDefault("rates.file_path", "configs/rates.csv")
+	viper.SetDefault("rates.http_url", "http://localhost:8081")
+	viper.SetDefault("rates.base", "USD")
+	viper.SetDefault("rates.refresh_interval", "12h")
+	viper.SetDefault("recurring.interval", "5m")
+	viper.SetDefault("duplicates.window", "72h")
+	viper.SetDefault("storage.driver", "local")
+	viper.SetDefault("storage.local_path", "data/attachments")
+	viper.SetDefault("storage.max_size", 10<<20)
+	viper.SetDefault("storage.s3.region", "us-east-1")
+	viper.SetDefault("trash.retention", "720h")
+	viper.SetDefault("trash.purge_interval", "1h")
+	viper.SetDefault("integrity.verify_interval", "6h")
+	viper.SetDefault("idempotency.window", "24h")
 	return nil
 }
@@ -3,6 +3,8 @@ package configs
 type Config struct {
 	Server struct {
 		Port string `mapstructure:"port"`
+		// адреса прокси, которым можно верить в X-Forwarded-For; пусто — не верить никому
+		TrustedProxies []string `mapstructure:"trusted_proxies"`
 	} `mapstructre:"server"`
 	DB struct {
 		Host     string `mapstructure:"host"`
@@ -17,7 +19,14 @@ type Config struct {
 		Port     int    `mapstructure:"port"`
 		Password string `mapstructure:"password"`
 	} `mapstructure:"redis"`
-	JWT JWTConfig `mapstructure:"jwt"`
+	JWT         JWTConfig         `mapstructure:"jwt"`
+	Rates       RatesConfig       `mapstructure:"rates"`
+	Recurring   RecurringConfig   `mapstructure:"recurring"`
+	Duplicates  DuplicatesConfig  `mapstructure:"duplicates"`
+	Storage     StorageConfig     `mapstructure:"storage"`
+	Trash       TrashConfig       `mapstructure:"trash"`
+	Integrity   IntegrityConfig   `mapstructure:"integrity"`
+	Idempotency IdempotencyConfig `mapstructure:"idempotency"`
 }
 
 type JWTConfig struct {
@@ -25,3 +34,45 @@ type JWTConfig struct {
 	AccessTTL  string `mapstructure:"access_ttl"`
 	RefreshTTL string `mapstructure:"refresh_ttl"`
 }
+
+type RatesConfig struct {
+	Provider        string `mapstructure:"provider"` // "file" | "http" | "none"
+	FilePath        string `mapstructure:"file_path"`
+	HTTPURL         string `mapstructure:"http_url"`
+	Base            string `mapstructure:"base"` // валюта для кросс-курсов
+	RefreshInterval string `mapstructure:"refresh_interval"`
+}
+
+type RecurringConfig struct {
+	Interval string `mapstructure:"interval"` // как часто планировщик создаёт наступившие повторения
+}
+
+type TrashConfig struct {
+	Retention     string `mapstructure:"retention"`      // сколько удалённые записи лежат в корзине
+	PurgeInterval string `mapstructure:"purge_interval"` // как часто корзина очищается от просроченных записей
+}
+
+type IntegrityConfig struct {
+	VerifyInterval string `mapstructure:"verify_interval"` // как часто балансы кошельков сверяются с операциями
+}
+
+type IdempotencyConfig struct {
+	Window string `mapstructure:"window"` // сколько хранится ответ на запрос с Idempotency-Key
+}
+
+type DuplicatesConfig struct {
+	Window string `mapstructure:"window"` // насколько далеко по дате операции ещё считаются дублями
+}
+
+type StorageConfig struct {
+	Driver    string `mapstructure:"driver"` // "local" | "s3"
+	LocalPath string `mapstructure:"local_path"`
+	MaxSize   int64  `mapstructure:"max_size"` // предельный размер вложения в байтах
+	S3        struct {
+		Endpoint  string `mapstructure:"endpoint"`
+		Region    string `mapstructure:"region"`
+		Bucket    string `mapstructure:"bucket"`
+		AccessKey string `mapstructure:"access_key"`
+		SecretKey string `mapstructure:"secret_key"`
+	} `mapstructure:"s3"`
+}
@@ -15,30 +15,58 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/api/categories": {
+        "/api/audit/{entity}/{id}": {
             "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Получить все категории пользователя",
+                "description": "Записи журнала по кошельку, транзакции или категории, новые первыми: кто, когда и с какого\nадреса изменил запись, её состояние до и после. История доступна и для удалённых записей",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "categories"
+                    "audit"
+                ],
+                "summary": "История изменений",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "wallet | movement | category",
+                        "name": "entity",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Entity ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Количество записей (1..200, по умолчанию 50)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Смещение",
+                        "name": "offset",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Список категорий",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handler.getAllCategoriesResponse"
+                            "$ref": "#/definitions/handler.getAuditHistoryResponse"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "400": {
+                        "description": "Invalid entity or paging",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -49,81 +77,78 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/categories/": {
-            "post": {
+        "/api/backup": {
+            "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Добавить новую категорию",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Версионированный JSON-архив всех кошельков, категорий, меток, операций, переводов, бюджетов,\nрегулярных операций и настроек пользователя. Суммы — десятичные строки в валюте кошелька",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "categories"
+                    "backup"
                 ],
-                "summary": "Создать категорию",
-                "parameters": [
-                    {
-                        "description": "Name + Type + Icon",
-                        "name": "input",
-                        "in": "body",
-                        "required": true,
+                "summary": "Резервная копия аккаунта",
+                "responses": {
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.CreateCategoryInput"
+                            "$ref": "#/definitions/models.Backup"
                         }
-                    }
-                ],
-                "responses": {
-                    "201": {
-                        "description": "Created",
+                    },
+                    "500": {
+                        "description": "Internal server error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
-                                "type": "integer"
+                                "type": "string"
                             }
                         }
                     }
                 }
             }
         },
-        "/api/categories/{id}": {
-            "get": {
+        "/api/backup/restore": {
+            "post": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Детали конкретной категории",
+                "description": "Переносит архив из GET /api/backup в аккаунт без своих кошельков, категорий, меток и бюджетов.\nЗаписи получают новые ID, всё восстанавливается в одной транзакции",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "categories"
+                    "backup"
                 ],
-                "summary": "Получить категорию по ID",
+                "summary": "Восстановление из резервной копии",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Category ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Архив",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Backup"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Category"
+                            "$ref": "#/definitions/models.RestoreResult"
                         }
                     },
                     "400": {
-                        "description": "Invalid ID",
+                        "description": "Invalid backup",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -131,49 +156,17 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "404": {
-                        "description": "Not found",
+                    "409": {
+                        "description": "Account is not empty",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
                                 "type": "string"
                             }
                         }
-                    }
-                }
-            },
-            "delete": {
-                "security": [
-                    {
-                        "Bearer": []
-                    }
-                ],
-                "description": "Удалить категорию",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "categories"
-                ],
-                "summary": "Удалить категорию",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Category ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/handler.statusResponse"
-                        }
                     },
-                    "400": {
-                        "description": "Invalid ID",
+                    "413": {
+                        "description": "Backup is too large",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -184,35 +177,25 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/wallets/": {
+        "/api/budgets/": {
             "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Получить все кошельки пользователя",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "wallets"
+                    "budgets"
                 ],
-                "summary": "Список кошельков",
+                "summary": "Список бюджетов",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handler.getAllWalletsResponse"
-                        }
-                    },
-                    "401": {
-                        "description": "Unauthorized",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/handler.getAllBudgetsResponse"
                         }
                     }
                 }
@@ -223,7 +206,7 @@ const docTemplate = `{
                         "Bearer": []
                     }
                 ],
-                "description": "Добавить новый кошелёк",
+                "description": "Месячный лимит расходов по категории. Валюта по умолчанию — базовая валюта пользователя,\nпри rollover неизрасходованный остаток переносится на следующий месяц",
                 "consumes": [
                     "application/json"
                 ],
@@ -231,66 +214,38 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "wallets"
+                    "budgets"
                 ],
-                "summary": "Создать кошелёк",
+                "summary": "Создать бюджет",
                 "parameters": [
                     {
-                        "description": "Name + Currency",
+                        "description": "Категория + Лимит",
                         "name": "input",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.CreateWalletInput"
+                            "$ref": "#/definitions/models.CreateBudgetInput"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Ключ идемпотентности: повтор с тем же ключом вернёт первый ответ",
+                        "name": "Idempotency-Key",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "201": {
-                        "description": "Created",
+                        "description": "Budget ID",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
                                 "type": "integer"
                             }
                         }
-                    }
-                }
-            }
-        },
-        "/api/wallets/{id}": {
-            "get": {
-                "security": [
-                    {
-                        "Bearer": []
-                    }
-                ],
-                "description": "Детали конкретного кошелька",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "wallets"
-                ],
-                "summary": "Получить кошелёк по ID",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Wallet ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/models.Wallet"
-                        }
                     },
                     "400": {
-                        "description": "Invalid ID",
+                        "description": "Invalid input",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -299,7 +254,25 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Not found",
+                        "description": "Category not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Budget for category already exists",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Ключ уже использован с другим запросом",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -308,39 +281,40 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/api/budgets/status": {
+            "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Удалить кошелёк и все транзакции",
+                "description": "Лимит, перенесённый остаток, потрачено, осталось и процент по каждому бюджету.\nРасходы из кошельков в других валютах пересчитываются в валюту бюджета",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "wallets"
+                    "budgets"
                 ],
-                "summary": "Удалить кошелёк",
+                "summary": "Исполнение бюджетов за месяц",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Wallet ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "type": "string",
+                        "description": "Месяц YYYY-MM, по умолчанию текущий",
+                        "name": "month",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handler.statusResponse"
+                            "$ref": "#/definitions/handler.budgetStatusResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid ID",
+                        "description": "Invalid month",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -351,26 +325,25 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/wallets/{wallet_id}/movements/": {
+        "/api/budgets/{id}": {
             "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Получить все операции по кошельку",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "movements"
+                    "budgets"
                 ],
-                "summary": "Список транзакций кошелька",
+                "summary": "Бюджет по ID",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Wallet ID",
-                        "name": "wallet_id",
+                        "description": "Budget ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     }
@@ -379,18 +352,27 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handler.getAllMovementsResponse"
+                            "$ref": "#/definitions/models.Budget"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     }
                 }
             },
-            "post": {
+            "put": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Пополнение (+) или списание (-) с кошелька",
+                "description": "Изменить лимит или перенос остатка. Новый лимит действует и для прошлых месяцев",
                 "consumes": [
                     "application/json"
                 ],
@@ -398,42 +380,46 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "movements"
+                    "budgets"
                 ],
-                "summary": "Создать транзакцию",
+                "summary": "Обновить бюджет",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Wallet ID",
-                        "name": "wallet_id",
+                        "description": "Budget ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Сумма + Тип",
+                        "description": "Поля для обновления",
                         "name": "input",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.CreateMovementInput"
+                            "$ref": "#/definitions/models.UpdateBudgetInput"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Movement ID",
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.statusResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
-                                "type": "integer"
+                                "type": "string"
                             }
                         }
                     }
                 }
-            }
-        },
-        "/api/wallets/{wallet_id}/movements/{trId}": {
-            "get": {
+            },
+            "delete": {
                 "security": [
                     {
                         "Bearer": []
@@ -443,21 +429,14 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "movements"
+                    "budgets"
                 ],
-                "summary": "Транзакция по ID",
+                "summary": "Удалить бюджет",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Wallet ID",
-                        "name": "wallet_id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Movement ID",
-                        "name": "trId",
+                        "description": "Budget ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     }
@@ -466,145 +445,94 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handler.getMovementByIdResponse"
+                            "$ref": "#/definitions/handler.statusResponse"
                         }
-                    }
-                }
-            },
-            "put": {
-                "security": [
-                    {
-                        "Bearer": []
-                    }
-                ],
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "movements"
-                ],
-                "summary": "Обновить транзакцию",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Wallet ID",
-                        "name": "wallet_id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Movement ID",
-                        "name": "trId",
-                        "in": "path",
-                        "required": true
                     },
-                    {
-                        "description": "Changes",
-                        "name": "input",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.UpdateMovementInput"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
+                    "404": {
+                        "description": "Not found",
                         "schema": {
-                            "$ref": "#/definitions/handler.statusResponse"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/api/budgets/{id}/history": {
+            "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
+                "description": "Исполнение бюджета по месяцам от начала действия до указанного месяца",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "movements"
+                    "budgets"
                 ],
-                "summary": "Удалить транзакцию",
+                "summary": "История бюджета",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Wallet ID",
-                        "name": "wallet_id",
+                        "description": "Budget ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "Movement ID",
-                        "name": "trId",
-                        "in": "path",
-                        "required": true
+                        "type": "string",
+                        "description": "Последний месяц YYYY-MM, по умолчанию текущий",
+                        "name": "month",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handler.statusResponse"
+                            "$ref": "#/definitions/handler.budgetStatusResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     }
                 }
             }
         },
-        "/auth/login": {
-            "post": {
-                "description": "Аутентификация и получение JWT токенов",
-                "consumes": [
-                    "application/json"
+        "/api/categories": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
                 ],
+                "description": "Получить все категории пользователя",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
-                ],
-                "summary": "Вход",
-                "parameters": [
-                    {
-                        "description": "Credentials",
-                        "name": "input",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.SignInInput"
-                        }
-                    }
+                    "categories"
                 ],
+                "summary": "Список категорий",
                 "responses": {
                     "200": {
-                        "description": "token",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
-                    },
-                    "400": {
-                        "description": "error",
+                        "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/handler.getAllCategoriesResponse"
                         }
                     },
-                    "500": {
-                        "description": "error",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -615,14 +543,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/auth/logout": {
+        "/api/categories/": {
             "post": {
                 "security": [
                     {
-                        "ApiKeyAuth": []
+                        "Bearer": []
                     }
                 ],
-                "description": "Отозвать конкретную refresh сессию пользователя (logout here)",
+                "description": "Добавить новую категорию",
                 "consumes": [
                     "application/json"
                 ],
@@ -630,41 +558,38 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "categories"
                 ],
-                "summary": "Выйти со конкретного устройства",
+                "summary": "Создать категорию",
                 "parameters": [
                     {
-                        "description": "Refresh token",
+                        "description": "Name + Type + Icon",
                         "name": "input",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.LogoutInput"
+                            "$ref": "#/definitions/models.CreateCategoryInput"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Ключ идемпотентности: повтор с тем же ключом вернёт первый ответ",
+                        "name": "Idempotency-Key",
+                        "in": "header"
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Session deleted",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
-                    },
-                    "401": {
-                        "description": "Not Authorized",
+                    "201": {
+                        "description": "Created",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
-                                "type": "string"
+                                "type": "integer"
                             }
                         }
                     },
-                    "500": {
-                        "description": "Failed deleting session",
+                    "422": {
+                        "description": "Ключ уже использован с другим запросом",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -675,38 +600,45 @@ const docTemplate = `{
                 }
             }
         },
-        "/auth/logout-all": {
-            "post": {
+        "/api/categories/{id}": {
+            "get": {
                 "security": [
                     {
-                        "ApiKeyAuth": []
+                        "Bearer": []
                     }
                 ],
-                "description": "Отозвать все активные refresh сессии пользователя (logout everywhere)",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Детали конкретной категории",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "categories"
                 ],
-                "summary": "Выйти со всех устройств",
+                "summary": "Получить категорию по ID",
                 "parameters": [
                     {
-                        "description": "Refresh token",
-                        "name": "input",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.LogoutInput"
-                        }
+                        "type": "integer",
+                        "description": "Category ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag из GET /api/categories/{id}",
+                        "name": "If-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "All sessions deleted",
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Category"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -714,8 +646,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "401": {
-                        "description": "Not Authorized",
+                    "404": {
+                        "description": "Not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -723,8 +655,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "500": {
-                        "description": "Failed deleting sessions",
+                    "412": {
+                        "description": "Категория изменена другим запросом",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -733,35 +665,45 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/auth/refresh": {
-            "post": {
-                "description": "Refresh access по refresh токену",
-                "consumes": [
-                    "application/json"
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
                 ],
+                "description": "Переносит категорию в корзину; операции и бюджеты сохраняют ссылку на неё",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "categories"
                 ],
-                "summary": "Обновить токены",
+                "summary": "Удалить категорию",
                 "parameters": [
                     {
-                        "description": "Refresh token",
-                        "name": "input",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.RefreshInput"
-                        }
+                        "type": "integer",
+                        "description": "Category ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag из GET /api/categories/{id}",
+                        "name": "If-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "New tokens",
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.statusResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -769,8 +711,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "401": {
-                        "description": "Invalid token",
+                    "412": {
+                        "description": "Категория изменена другим запросом",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -781,42 +723,387 @@ const docTemplate = `{
                 }
             }
         },
-        "/auth/register": {
-            "post": {
-                "description": "Создать аккаунт",
-                "consumes": [
-                    "application/json"
+        "/api/movements/": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
                 ],
+                "description": "Операции пользователя по всем кошелькам с курсорной (keyset) пагинацией",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "movements"
                 ],
-                "summary": "Регистрация пользователя",
+                "summary": "Лента транзакций по всем кошелькам",
                 "parameters": [
                     {
-                        "description": "Email + Password",
-                        "name": "input",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.RegisterInput"
+                        "type": "integer",
+                        "description": "Wallet ID",
+                        "name": "wallet_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "income | expense | initial | transfer",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Category ID",
+                        "name": "category_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Tag ID",
+                        "name": "tag_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Дата начала (YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Дата конца включительно (YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Минимальная сумма, десятичная строка",
+                        "name": "min_amount",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Максимальная сумма, десятичная строка",
+                        "name": "max_amount",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Валюта сумм в min_amount/max_amount; без wallet_id обязательна и оставляет только кошельки в этой валюте",
+                        "name": "currency",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "desc (по умолчанию) | asc",
+                        "name": "sort_order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor из предыдущего ответа",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Размер страницы (1-100, по умолчанию 50)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.MovementCursorPage"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid filter or cursor",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     }
+                }
+            }
+        },
+        "/api/movements/export": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Операции по всем кошелькам или по одному (wallet_id) с теми же фильтрами, что и лента,\nв CSV, NDJSON (JSON Lines) или XLSX. Файл отдаётся потоком по мере чтения из базы, по возрастанию даты",
+                "produces": [
+                    "text/csv",
+                    "application/x-ndjson",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+                ],
+                "tags": [
+                    "movements"
+                ],
+                "summary": "Выгрузка операций",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "csv (по умолчанию) | ndjson | xlsx",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Wallet ID",
+                        "name": "wallet_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "income | expense | initial | transfer",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Category ID",
+                        "name": "category_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Tag ID",
+                        "name": "tag_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Дата начала (YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Дата конца включительно (YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Минимальная сумма, десятичная строка",
+                        "name": "min_amount",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Максимальная сумма, десятичная строка",
+                        "name": "max_amount",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Валюта сумм в min_amount/max_amount; без wallet_id обязательна и оставляет только кошельки в этой валюте",
+                        "name": "currency",
+                        "in": "query"
+                    }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "User ID",
+                    "200": {
+                        "description": "Файл выгрузки",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid filter",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
-                                "type": "integer"
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Wallet not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/movements/search": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Полнотекстовый поиск по всем кошелькам: каждое слово запроса должно совпасть с началом слова\nв описании, регистр не важен. Фильтры те же, что у ленты. В snippet совпадения выделены \u003cb\u003e\u003c/b\u003e,\nостальной текст HTML-экранирован",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "movements"
+                ],
+                "summary": "Поиск по описаниям операций",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Поисковый запрос",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Wallet ID",
+                        "name": "wallet_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "income | expense | initial | transfer",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Category ID",
+                        "name": "category_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Tag ID",
+                        "name": "tag_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Дата начала (YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Дата конца включительно (YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Минимальная сумма, десятичная строка",
+                        "name": "min_amount",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Максимальная сумма, десятичная строка",
+                        "name": "max_amount",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Валюта сумм в min_amount/max_amount; без wallet_id обязательна и оставляет только кошельки в этой валюте",
+                        "name": "currency",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Размер страницы (1-100, по умолчанию 50)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Смещение",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.MovementSearchPage"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid query or filter",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Wallet not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
                             }
                         }
+                    }
+                }
+            }
+        },
+        "/api/rates": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Курс, действующий на дату: последний известный не позже неё. Ищется напрямую, через обратную пару или кросс-курсом",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "rates"
+                ],
+                "summary": "Курс валют на дату",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "USD",
+                        "description": "Базовая валюта",
+                        "name": "base",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "RUB",
+                        "description": "Котируемая валюта",
+                        "name": "quote",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Дата (YYYY-MM-DD), по умолчанию сегодня",
+                        "name": "date",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/currency.Rate"
+                        }
                     },
                     "400": {
-                        "description": "Invalid input",
+                        "description": "Invalid pair",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -824,8 +1111,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "500": {
-                        "description": "Server error",
+                    "404": {
+                        "description": "Rate not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -835,10 +1122,4683 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "handler.getAllCategoriesResponse": {
+        },
+        "/api/recurring/": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recurring"
+                ],
+                "summary": "Список повторяющихся операций",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.getAllRecurringResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Шаблон операции по расписанию: daily/weekly/monthly/yearly с шагом interval.\nНаступившие повторения создаются фоновым планировщиком, в том числе прошедшие с start_date",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recurring"
+                ],
+                "summary": "Создать повторяющуюся операцию",
+                "parameters": [
+                    {
+                        "description": "Кошелёк + Операция + Расписание",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateRecurringInput"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Ключ идемпотентности: повтор с тем же ключом вернёт первый ответ",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Recurring ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "integer"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Wallet not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Ключ уже использован с другим запросом",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/recurring/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recurring"
+                ],
+                "summary": "Повторяющаяся операция по ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Recurring ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.RecurringMovement"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Уже созданные операции остаются",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recurring"
+                ],
+                "summary": "Удалить повторяющуюся операцию",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Recurring ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.statusResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/recurring/{id}/pause": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recurring"
+                ],
+                "summary": "Поставить на паузу",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Recurring ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.statusResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/recurring/{id}/preview": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recurring"
+                ],
+                "summary": "Ближайшие повторения",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Recurring ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Сколько повторений (1-100, по умолчанию 10)",
+                        "name": "count",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.recurringPreviewResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/recurring/{id}/resume": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Повторения, выпавшие на время паузы, не создаются",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recurring"
+                ],
+                "summary": "Снять с паузы",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Recurring ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.statusResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/recurring/{id}/skip": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Без даты пропускается ближайшее повторение",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recurring"
+                ],
+                "summary": "Пропустить повторение",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Recurring ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Дата повторения",
+                        "name": "input",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/models.SkipOccurrenceInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.skipOccurrenceResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Date is not an occurrence",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Occurrence already created or skipped",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/reports/categories": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Суммы по категориям за период в базовой валюте и доля каждой категории",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reports"
+                ],
+                "summary": "Отчёт по категориям",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "expense (по умолчанию) | income",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Дата начала (YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Дата конца включительно (YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "integer"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "ID кошельков, можно несколько раз",
+                        "name": "wallet_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.CategoryReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid filter",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Wallet not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/reports/fx": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Сколько отправлено и получено по каждой паре валют, средний фактический курс и итог по каждой валюте.\nfx_gain — выигрыш или потеря на обменах против справочного курса на дату перевода, в базовой валюте",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reports"
+                ],
+                "summary": "Курсовые разницы по переводам",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Дата начала (YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Дата конца включительно (YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.FXReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid period",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/reports/monthly": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Доходы, расходы и изменение по месяцам в базовой валюте, по курсу на конец каждого месяца",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reports"
+                ],
+                "summary": "Помесячный отчёт",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Дата начала (YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Дата конца включительно (YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "integer"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "ID кошельков, можно несколько раз",
+                        "name": "wallet_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.MonthlyReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid filter",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Wallet not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/reports/summary": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Балансы, доходы и расходы за период по выбранным кошелькам, пересчитанные в базовую валюту.\nПереводы и начальные остатки не считаются доходами и расходами",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reports"
+                ],
+                "summary": "Сводный отчёт",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Дата начала (YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Дата конца включительно (YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "integer"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "ID кошельков, можно несколько раз",
+                        "name": "wallet_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SummaryReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid filter",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Wallet not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/reports/tags": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Суммы по меткам за период в базовой валюте. Операция с несколькими метками входит в каждую",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reports"
+                ],
+                "summary": "Отчёт по меткам",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "expense (по умолчанию) | income",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Дата начала (YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Дата конца включительно (YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "integer"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "ID кошельков, можно несколько раз",
+                        "name": "wallet_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.TagReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid filter",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Wallet not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/tags/": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Все метки пользователя с числом операций",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tags"
+                ],
+                "summary": "Список меток",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.getAllTagsResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Метки пользователя свободнее категорий: на операцию можно повесить несколько",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tags"
+                ],
+                "summary": "Создать метку",
+                "parameters": [
+                    {
+                        "description": "Name + Color",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateTagInput"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Ключ идемпотентности: повтор с тем же ключом вернёт первый ответ",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "integer"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Tag already exists",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Ключ уже использован с другим запросом",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/tags/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tags"
+                ],
+                "summary": "Получить метку по ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Tag ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Tag"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tags"
+                ],
+                "summary": "Обновить метку",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Tag ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Changes",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateTagInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.statusResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Tag already exists",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Метка снимается со всех операций, сами операции остаются",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tags"
+                ],
+                "summary": "Удалить метку",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Tag ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.statusResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/transfers/": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transfers"
+                ],
+                "summary": "Список переводов",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.getAllTransfersResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Списать сумму с одного кошелька и зачислить на другой. Пишется двумя связанными операциями.\nМежду кошельками в разных валютах нужен to_amount (сумма зачисления) или rate (курс)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transfers"
+                ],
+                "summary": "Перевод между кошельками",
+                "parameters": [
+                    {
+                        "description": "Кошельки + Сумма",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateTransferInput"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Ключ идемпотентности: повтор с тем же ключом вернёт первый ответ",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Transfer ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "integer"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Wallet not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Ключ уже использован с другим запросом",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/transfers/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Перевод вместе с обеими операциями",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transfers"
+                ],
+                "summary": "Перевод по ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Transfer ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Transfer"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Меняет обе операции перевода и балансы обоих кошельков",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transfers"
+                ],
+                "summary": "Обновить перевод",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Transfer ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Changes",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateTransferInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.statusResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid amounts",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Удаляет обе операции перевода и возвращает балансы кошельков",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transfers"
+                ],
+                "summary": "Удалить перевод",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Transfer ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.statusResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/trash": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Удалённые кошельки, транзакции и категории. Записи хранятся ограниченное время,\nпосле чего удаляются окончательно вместе с вложениями",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "trash"
+                ],
+                "summary": "Корзина",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Trash"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/trash/categories/{id}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "trash"
+                ],
+                "summary": "Восстановить категорию",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Category ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.statusResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Category is not in the trash",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Category with this name already exists",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/trash/movements/{id}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Возвращает транзакцию и снова учитывает её сумму в балансе кошелька",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "trash"
+                ],
+                "summary": "Восстановить транзакцию",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Movement ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.statusResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Movement is not in the trash",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Wallet is in the trash",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/trash/wallets/{id}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Возвращает кошелёк с его транзакциями и переводами; суммы переводов снова учитываются в других кошельках",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "trash"
+                ],
+                "summary": "Восстановить кошелёк",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Wallet ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.statusResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Wallet is not in the trash",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/wallets/": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Получить все кошельки пользователя",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "wallets"
+                ],
+                "summary": "Список кошельков",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.getAllWalletsResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Добавить новый кошелёк",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "wallets"
+                ],
+                "summary": "Создать кошелёк",
+                "parameters": [
+                    {
+                        "description": "Name + Currency",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateWalletInput"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Ключ идемпотентности: повтор с тем же ключом вернёт первый ответ",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "integer"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Ключ уже использован с другим запросом",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/wallets/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Детали конкретного кошелька",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "wallets"
+                ],
+                "summary": "Получить кошелёк по ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Wallet ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag из GET /api/wallets/{id}",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Wallet"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Валюту кошелька с операциями менять нельзя",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "412": {
+                        "description": "Кошелёк изменён другим запросом",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Переносит кошелёк со всеми транзакциями в корзину; восстановление — POST /api/trash/wallets/{id}/restore",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "wallets"
+                ],
+                "summary": "Удалить кошелёк",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Wallet ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag из GET /api/wallets/{id}",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.statusResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "412": {
+                        "description": "Кошелёк изменён другим запросом",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/wallets/{id}/import": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Файл выписки передаётся в multipart/form-data, формат по умолчанию определяется по расширению.\nДля CSV нужно сопоставление колонок (JSON models.CSVMapping). В OFX/QIF операции с уже\nимпортированным идентификатором (FITID) пропускаются. При dry_run=true возвращаются разобранные\nстроки, ошибки и похожие уже записанные операции без записи. Иначе все операции создаются в одной\nтранзакции; если хотя бы одна строка с ошибкой (422) или похожа на существующую операцию без\nallow_duplicates=true (409), не создаётся ничего",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "import"
+                ],
+                "summary": "Импорт выписки (CSV, OFX/QFX, QIF)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Wallet ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Файл выписки",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "csv | ofx | qfx | qif",
+                        "name": "format",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Сопоставление колонок CSV, JSON models.CSVMapping",
+                        "name": "mapping",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Формат дат QIF (Go), по умолчанию 1/2/2006",
+                        "name": "date_format",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Категория для операций без категории (OFX/QIF)",
+                        "name": "default_category_id",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Только разобрать файл",
+                        "name": "dry_run",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Записать строки, похожие на существующие операции",
+                        "name": "allow_duplicates",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Ключ идемпотентности: повтор с тем же ключом вернёт первый ответ",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Dry run",
+                        "schema": {
+                            "$ref": "#/definitions/models.ImportResult"
+                        }
+                    },
+                    "201": {
+                        "description": "Imported",
+                        "schema": {
+                            "$ref": "#/definitions/models.ImportResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid file or mapping",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Wallet not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Rows look like existing movements",
+                        "schema": {
+                            "$ref": "#/definitions/models.ImportResult"
+                        }
+                    },
+                    "422": {
+                        "description": "Ключ уже использован с другим запросом",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/wallets/{id}/movements/{trId}/attachments": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "attachments"
+                ],
+                "summary": "Вложения операции",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Wallet ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Movement ID",
+                        "name": "trId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.getAllAttachmentsResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Movement not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Чек или документ в multipart/form-data. Допускаются JPEG, PNG, GIF, WebP и PDF,\nтип определяется по содержимому файла",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "attachments"
+                ],
+                "summary": "Прикрепить файл к операции",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Wallet ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Movement ID",
+                        "name": "trId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Файл",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Ключ идемпотентности: повтор с тем же ключом вернёт первый ответ",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Attachment"
+                        }
+                    },
+                    "400": {
+                        "description": "File is required",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Movement not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "413": {
+                        "description": "File is too large",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported file type",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Ключ уже использован с другим запросом",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/wallets/{id}/movements/{trId}/attachments/{attId}": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "produces": [
+                    "application/octet-stream"
+                ],
+                "tags": [
+                    "attachments"
+                ],
+                "summary": "Скачать вложение",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Wallet ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Movement ID",
+                        "name": "trId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Attachment ID",
+                        "name": "attId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "404": {
+                        "description": "Attachment not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "attachments"
+                ],
+                "summary": "Удалить вложение",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Wallet ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Movement ID",
+                        "name": "trId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Attachment ID",
+                        "name": "attId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.statusResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Attachment not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/wallets/{id}/stats": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Баланс, доходы, расходы и число операций за период в валюте кошелька",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "wallets"
+                ],
+                "summary": "Статистика кошелька",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Wallet ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Дата начала (YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Дата конца включительно (YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.WalletStats"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid period",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Wallet not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/wallets/{wallet_id}/movements/": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Получить операции по кошельку с фильтрацией, сортировкой и пагинацией",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "movements"
+                ],
+                "summary": "Список транзакций кошелька",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Wallet ID",
+                        "name": "wallet_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "income | expense | initial | transfer",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Category ID",
+                        "name": "category_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Tag ID",
+                        "name": "tag_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Дата начала (YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Дата конца включительно (YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Минимальная сумма в валюте кошелька",
+                        "name": "min_amount",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Максимальная сумма в валюте кошелька",
+                        "name": "max_amount",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "date | amount | created_at",
+                        "name": "sort_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "asc | desc",
+                        "name": "sort_order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Размер страницы (1-100, по умолчанию 50)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Смещение",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.getAllMovementsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid filter",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Пополнение (+) или списание (-) с кошелька\nЕсли в кошельке есть операция того же типа и суммы рядом по дате с похожим описанием,\nвозвращается 409 со списком похожих; allow_duplicate=true создаёт операцию всё равно\nsplits разбивает сумму по категориям: не меньше двух строк, их сумма равна amount",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "movements"
+                ],
+                "summary": "Создать транзакцию",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Wallet ID",
+                        "name": "wallet_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Сумма + Тип",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateMovementInput"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Ключ идемпотентности: повтор с тем же ключом вернёт первый ответ",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Movement ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "integer"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Похожая операция уже есть",
+                        "schema": {
+                            "$ref": "#/definitions/handler.duplicateMovementResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Ключ уже использован с другим запросом",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/wallets/{wallet_id}/movements/{trId}": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "movements"
+                ],
+                "summary": "Транзакция по ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Wallet ID",
+                        "name": "wallet_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Movement ID",
+                        "name": "trId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.getMovementByIdResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Версия транзакции для If-Match"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "movements"
+                ],
+                "summary": "Обновить транзакцию",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Wallet ID",
+                        "name": "wallet_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Movement ID",
+                        "name": "trId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Changes",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateMovementInput"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag из GET транзакции",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.statusResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid amount or splits",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Movement belongs to a transfer",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "412": {
+                        "description": "Транзакция изменена другим запросом",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Переносит транзакцию в корзину, её сумма снимается с баланса кошелька",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "movements"
+                ],
+                "summary": "Удалить транзакцию",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Wallet ID",
+                        "name": "wallet_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Movement ID",
+                        "name": "trId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag из GET транзакции",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.statusResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Wallet or movement not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Movement belongs to a transfer",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "412": {
+                        "description": "Транзакция изменена другим запросом",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/login": {
+            "post": {
+                "description": "Аутентификация и получение JWT токенов",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Вход",
+                "parameters": [
+                    {
+                        "description": "Credentials",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.SignInInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "token",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/logout": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Отозвать конкретную refresh сессию пользователя (logout here)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Выйти со конкретного устройства",
+                "parameters": [
+                    {
+                        "description": "Refresh token",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.LogoutInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Session deleted",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Not Authorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Failed deleting session",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/logout-all": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Отозвать все активные refresh сессии пользователя (logout everywhere)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Выйти со всех устройств",
+                "parameters": [
+                    {
+                        "description": "Refresh token",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.LogoutInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "All sessions deleted",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Not Authorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Failed deleting sessions",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/me": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Пользователь, суммы кошельков по валютам и общий итог в базовой валюте.\nrate_date — дата курса, по которому сделан пересчёт",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Профиль пользователя",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Profile"
+                        }
+                    },
+                    "401": {
+                        "description": "Not Authorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/refresh": {
+            "post": {
+                "description": "Refresh access по refresh токену",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Обновить токены",
+                "parameters": [
+                    {
+                        "description": "Refresh token",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.RefreshInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "New tokens",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid token",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/register": {
+            "post": {
+                "description": "Создать аккаунт",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Регистрация пользователя",
+                "parameters": [
+                    {
+                        "description": "Email + Password",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.RegisterInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "User ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "integer"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "currency.Rate": {
+            "type": "object",
+            "properties": {
+                "base": {
+                    "type": "string"
+                },
+                "date": {
+                    "type": "string"
+                },
+                "quote": {
+                    "type": "string"
+                },
+                "rate": {
+                    "type": "number"
+                },
+                "source": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.budgetStatusResponse": {
+            "type": "object",
+            "properties": {
+                "budgets": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.BudgetStatus"
+                    }
+                }
+            }
+        },
+        "handler.duplicateMovementResponse": {
+            "type": "object",
+            "properties": {
+                "candidates": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Movement"
+                    }
+                },
+                "error": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.getAllAttachmentsResponse": {
+            "type": "object",
+            "properties": {
+                "attachments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Attachment"
+                    }
+                }
+            }
+        },
+        "handler.getAllBudgetsResponse": {
+            "type": "object",
+            "properties": {
+                "budgets": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Budget"
+                    }
+                }
+            }
+        },
+        "handler.getAllCategoriesResponse": {
+            "type": "object",
+            "properties": {
+                "categories": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Category"
+                    }
+                }
+            }
+        },
+        "handler.getAllMovementsResponse": {
+            "type": "object",
+            "properties": {
+                "limit": {
+                    "type": "integer"
+                },
+                "movements": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Movement"
+                    }
+                },
+                "offset": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                },
+                "wallet": {
+                    "$ref": "#/definitions/models.Wallet"
+                }
+            }
+        },
+        "handler.getAllRecurringResponse": {
+            "type": "object",
+            "properties": {
+                "recurring": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.RecurringMovement"
+                    }
+                }
+            }
+        },
+        "handler.getAllTagsResponse": {
+            "type": "object",
+            "properties": {
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Tag"
+                    }
+                }
+            }
+        },
+        "handler.getAllTransfersResponse": {
+            "type": "object",
+            "properties": {
+                "transfers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Transfer"
+                    }
+                }
+            }
+        },
+        "handler.getAllWalletsResponse": {
+            "type": "object",
+            "properties": {
+                "wallets": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Wallet"
+                    }
+                }
+            }
+        },
+        "handler.getAuditHistoryResponse": {
+            "type": "object",
+            "properties": {
+                "history": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.AuditEntry"
+                    }
+                }
+            }
+        },
+        "handler.getMovementByIdResponse": {
+            "type": "object",
+            "properties": {
+                "movement": {
+                    "$ref": "#/definitions/models.Movement"
+                },
+                "wallet": {
+                    "$ref": "#/definitions/models.Wallet"
+                }
+            }
+        },
+        "handler.recurringPreviewResponse": {
+            "type": "object",
+            "properties": {
+                "occurrences": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.RecurringOccurrence"
+                    }
+                }
+            }
+        },
+        "handler.skipOccurrenceResponse": {
+            "type": "object",
+            "properties": {
+                "skipped": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.statusResponse": {
+            "type": "object",
+            "properties": {
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Attachment": {
+            "type": "object",
+            "properties": {
+                "content_type": {
+                    "type": "string",
+                    "example": "image/jpeg"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "filename": {
+                    "type": "string",
+                    "example": "receipt.jpg"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "movement_id": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "size": {
+                    "type": "integer",
+                    "example": 183422
+                },
+                "user_id": {
+                    "type": "integer",
+                    "example": 10
+                }
+            }
+        },
+        "models.AuditEntry": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string",
+                    "example": "update"
+                },
+                "after": {
+                    "description": "JSON состояния после изменения",
+                    "type": "object"
+                },
+                "before": {
+                    "description": "JSON состояния до изменения",
+                    "type": "object"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "entity": {
+                    "type": "string",
+                    "example": "movement"
+                },
+                "entity_id": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "ip": {
+                    "type": "string",
+                    "example": "203.0.113.7"
+                },
+                "request_id": {
+                    "type": "string",
+                    "example": "3f2a9c1e0b7d4e65"
+                },
+                "user_id": {
+                    "description": "кто изменил",
+                    "type": "integer",
+                    "example": 10
+                }
+            }
+        },
+        "models.Backup": {
+            "type": "object",
+            "properties": {
+                "budgets": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.BackupBudget"
+                    }
+                },
+                "categories": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.BackupCategory"
+                    }
+                },
+                "exported_at": {
+                    "type": "string"
+                },
+                "movements": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.BackupMovement"
+                    }
+                },
+                "recurring": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.BackupRecurring"
+                    }
+                },
+                "settings": {
+                    "$ref": "#/definitions/models.BackupSettings"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.BackupTag"
+                    }
+                },
+                "transfers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.BackupTransfer"
+                    }
+                },
+                "version": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "wallets": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.BackupWallet"
+                    }
+                }
+            }
+        },
+        "models.BackupBudget": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "string",
+                    "example": "30000.00"
+                },
+                "category_id": {
+                    "type": "integer",
+                    "example": 14
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "rollover": {
+                    "type": "boolean"
+                },
+                "start_month": {
+                    "type": "string",
+                    "example": "2026-01"
+                }
+            }
+        },
+        "models.BackupCategory": {
+            "type": "object",
+            "properties": {
+                "builtin": {
+                    "description": "общая категория, при восстановлении ищется по названию и типу",
+                    "type": "boolean",
+                    "example": true
+                },
+                "icon": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 14
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Продукты"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "expense"
+                }
+            }
+        },
+        "models.BackupMovement": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "string",
+                    "example": "150.50"
+                },
+                "category_id": {
+                    "type": "integer",
+                    "example": 14
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "date": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "external_id": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "splits": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.BackupSplit"
+                    }
+                },
+                "tags": {
+                    "description": "ID меток из архива",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "transfer_id": {
+                    "type": "integer"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "expense"
+                },
+                "wallet_id": {
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "models.BackupRecurring": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "string",
+                    "example": "45000.00"
+                },
+                "category_id": {
+                    "type": "integer"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "end_date": {
+                    "type": "string"
+                },
+                "frequency": {
+                    "type": "string",
+                    "example": "monthly"
+                },
+                "interval": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "next_date": {
+                    "type": "string"
+                },
+                "paused": {
+                    "type": "boolean"
+                },
+                "start_date": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "expense"
+                },
+                "wallet_id": {
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "models.BackupSettings": {
+            "type": "object",
+            "properties": {
+                "base_currency": {
+                    "type": "string",
+                    "example": "RUB"
+                }
+            }
+        },
+        "models.BackupSplit": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "string",
+                    "example": "100.00"
+                },
+                "category_id": {
+                    "type": "integer",
+                    "example": 14
+                },
+                "note": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.BackupTag": {
+            "type": "object",
+            "properties": {
+                "color": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "name": {
+                    "type": "string",
+                    "example": "отпуск 2026"
+                }
+            }
+        },
+        "models.BackupTransfer": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "date": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "from_amount": {
+                    "type": "string",
+                    "example": "250.00"
+                },
+                "from_wallet_id": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "rate": {
+                    "type": "number",
+                    "example": 90
+                },
+                "to_amount": {
+                    "type": "string",
+                    "example": "22500.00"
+                },
+                "to_wallet_id": {
+                    "type": "integer",
+                    "example": 4
+                }
+            }
+        },
+        "models.BackupWallet": {
+            "type": "object",
+            "properties": {
+                "balance": {
+                    "type": "string",
+                    "example": "15000.00"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Main Wallet"
+                }
+            }
+        },
+        "models.Budget": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "string",
+                    "example": "30000.00"
+                },
+                "category": {
+                    "type": "string",
+                    "example": "Продукты"
+                },
+                "category_id": {
+                    "type": "integer",
+                    "example": 5
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "rollover": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "start_month": {
+                    "type": "string",
+                    "example": "2026-01"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer",
+                    "example": 10
+                }
+            }
+        },
+        "models.BudgetStatus": {
+            "type": "object",
+            "properties": {
+                "available": {
+                    "description": "limit + carried_over",
+                    "type": "string",
+                    "example": "34500.00"
+                },
+                "budget_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "carried_over": {
+                    "description": "перенесено с прошлых месяцев",
+                    "type": "string",
+                    "example": "4500.00"
+                },
+                "category": {
+                    "type": "string",
+                    "example": "Продукты"
+                },
+                "category_id": {
+                    "type": "integer",
+                    "example": 5
+                },
+                "limit": {
+                    "type": "string",
+                    "example": "30000.00"
+                },
+                "missing_rates": {
+                    "description": "расходы в этих валютах не учтены",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "month": {
+                    "type": "string",
+                    "example": "2026-03"
+                },
+                "overspent": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "percent": {
+                    "description": "spent / available",
+                    "type": "number",
+                    "example": 60.9
+                },
+                "remaining": {
+                    "description": "отрицательный при перерасходе",
+                    "type": "string",
+                    "example": "13500.00"
+                },
+                "spent": {
+                    "type": "string",
+                    "example": "21000.00"
+                }
+            }
+        },
+        "models.Category": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "description": "заполнено только у категорий в корзине",
+                    "type": "string"
+                },
+                "icon": {
+                    "type": "string",
+                    "example": "🛒"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Groceries"
+                },
+                "type": {
+                    "description": "\"income\" or \"expense\"",
+                    "type": "string",
+                    "example": "expense"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "usage_count": {
+                    "type": "integer",
+                    "example": 5
+                },
+                "user_id": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "version": {
+                    "description": "растёт при каждом изменении, отдаётся как ETag",
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "models.CategoryReport": {
+            "type": "object",
+            "properties": {
+                "base_currency": {
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CategorySummary"
+                    }
+                },
+                "grand_total": {
+                    "type": "string",
+                    "example": "120000.00"
+                },
+                "missing_rates": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "rate_date": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "expense"
+                }
+            }
+        },
+        "models.CategorySummary": {
+            "type": "object",
+            "properties": {
+                "category": {
+                    "type": "string",
+                    "example": "Продукты"
+                },
+                "category_id": {
+                    "type": "integer",
+                    "example": 5
+                },
+                "count": {
+                    "type": "integer",
+                    "example": 14
+                },
+                "percent": {
+                    "type": "number",
+                    "example": 20.8
+                },
+                "total": {
+                    "type": "string",
+                    "example": "25000.00"
+                }
+            }
+        },
+        "models.CreateBudgetInput": {
+            "type": "object",
+            "required": [
+                "amount",
+                "category_id"
+            ],
+            "properties": {
+                "amount": {
+                    "type": "string",
+                    "example": "30000.00"
+                },
+                "category_id": {
+                    "type": "integer",
+                    "example": 5
+                },
+                "currency": {
+                    "description": "по умолчанию базовая валюта пользователя",
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "rollover": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "start_month": {
+                    "description": "по умолчанию текущий месяц",
+                    "type": "string",
+                    "example": "2026-01"
+                }
+            }
+        },
+        "models.CreateCategoryInput": {
+            "type": "object",
+            "required": [
+                "name",
+                "type"
+            ],
+            "properties": {
+                "icon": {
+                    "type": "string",
+                    "example": "💰"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Salary"
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "income",
+                        "expense"
+                    ],
+                    "example": "income"
+                }
+            }
+        },
+        "models.CreateMovementInput": {
+            "type": "object",
+            "required": [
+                "amount",
+                "date",
+                "type"
+            ],
+            "properties": {
+                "allow_duplicate": {
+                    "description": "создать, даже если похожая операция уже есть",
+                    "type": "boolean",
+                    "example": false
+                },
+                "amount": {
+                    "type": "string",
+                    "example": "150.50"
+                },
+                "category": {
+                    "description": "при разбивке можно не указывать, берётся категория первой строки",
+                    "type": "integer",
+                    "example": 1
+                },
+                "date": {
+                    "type": "string",
+                    "example": "2026-01-27T12:00:00Z"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Grocery shopping"
+                },
+                "splits": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SplitInput"
+                    }
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        1,
+                        2
+                    ]
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "income",
+                        "expense",
+                        "initial"
+                    ],
+                    "example": "expense"
+                }
+            }
+        },
+        "models.CreateRecurringInput": {
+            "type": "object",
+            "required": [
+                "amount",
+                "category_id",
+                "frequency",
+                "start_date",
+                "type",
+                "wallet_id"
+            ],
+            "properties": {
+                "amount": {
+                    "type": "string",
+                    "example": "45000.00"
+                },
+                "category_id": {
+                    "type": "integer",
+                    "example": 4
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Rent"
+                },
+                "end_date": {
+                    "type": "string",
+                    "example": "2026-12-31T00:00:00Z"
+                },
+                "frequency": {
+                    "type": "string",
+                    "enum": [
+                        "daily",
+                        "weekly",
+                        "monthly",
+                        "yearly"
+                    ],
+                    "example": "monthly"
+                },
+                "interval": {
+                    "description": "по умолчанию 1",
+                    "type": "integer",
+                    "maximum": 366,
+                    "minimum": 1,
+                    "example": 1
+                },
+                "start_date": {
+                    "type": "string",
+                    "example": "2026-01-05T09:00:00Z"
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "income",
+                        "expense"
+                    ],
+                    "example": "expense"
+                },
+                "wallet_id": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "models.CreateTagInput": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "color": {
+                    "type": "string",
+                    "example": "#FF8800"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 50,
+                    "example": "отпуск 2026"
+                }
+            }
+        },
+        "models.CreateTransferInput": {
+            "type": "object",
+            "required": [
+                "amount",
+                "date",
+                "from_wallet_id",
+                "to_wallet_id"
+            ],
+            "properties": {
+                "amount": {
+                    "type": "string",
+                    "example": "250.00"
+                },
+                "date": {
+                    "type": "string",
+                    "example": "2026-01-27T12:00:00Z"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Card to savings"
+                },
+                "from_wallet_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "rate": {
+                    "type": "number",
+                    "example": 90
+                },
+                "to_amount": {
+                    "type": "string",
+                    "example": "22500.00"
+                },
+                "to_wallet_id": {
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "models.CreateWalletInput": {
+            "type": "object",
+            "required": [
+                "balance",
+                "currency",
+                "name"
+            ],
+            "properties": {
+                "balance": {
+                    "type": "string",
+                    "example": "1500.00"
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "USD"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Salary Card"
+                }
+            }
+        },
+        "models.CurrencyBalance": {
+            "type": "object",
+            "properties": {
+                "balance": {
+                    "type": "string",
+                    "example": "1500.00"
+                },
+                "converted": {
+                    "type": "string",
+                    "example": "135750.00"
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "USD"
+                },
+                "rate": {
+                    "type": "number",
+                    "example": 90.5
+                },
+                "rate_date": {
+                    "type": "string"
+                },
+                "wallet_count": {
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "models.CurrencySummary": {
+            "type": "object",
+            "properties": {
+                "balance": {
+                    "type": "string",
+                    "example": "1500.00"
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "USD"
+                },
+                "expense": {
+                    "type": "string",
+                    "example": "1200.00"
+                },
+                "income": {
+                    "type": "string",
+                    "example": "3000.00"
+                },
+                "movement_count": {
+                    "type": "integer",
+                    "example": 40
+                },
+                "wallet_count": {
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "models.FXPairSummary": {
+            "type": "object",
+            "properties": {
+                "avg_rate": {
+                    "type": "number"
+                },
+                "count": {
+                    "type": "integer"
+                },
+                "from_currency": {
+                    "type": "string"
+                },
+                "fx_gain": {
+                    "type": "string",
+                    "example": "-150.00"
+                },
+                "received": {
+                    "type": "string",
+                    "example": "90000.00"
+                },
+                "sent": {
+                    "type": "string",
+                    "example": "1000.00"
+                },
+                "to_currency": {
+                    "type": "string"
+                },
+                "unvalued": {
+                    "description": "переводы без справочного курса, в Gain не вошли",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.FXReport": {
+            "type": "object",
+            "properties": {
+                "base_currency": {
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "missing_rates": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "net_by_currency": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "pairs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.FXPairSummary"
+                    }
+                },
+                "total_fx_gain": {
+                    "type": "string",
+                    "example": "-150.00"
+                }
+            }
+        },
+        "models.ImportResult": {
+            "type": "object",
+            "properties": {
+                "balance_delta": {
+                    "type": "string",
+                    "example": "-1250.00"
+                },
+                "created": {
+                    "type": "integer"
+                },
+                "dry_run": {
+                    "type": "boolean"
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ImportRowError"
+                    }
+                },
+                "rows": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ImportRow"
+                    }
+                },
+                "skipped": {
+                    "description": "уже импортированы раньше",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ImportRow"
+                    }
+                }
+            }
+        },
+        "models.ImportRow": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "string",
+                    "example": "150.50"
+                },
+                "category": {
+                    "description": "как в файле",
+                    "type": "string",
+                    "example": "Продукты"
+                },
+                "category_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "date": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "PYATEROCHKA 1234"
+                },
+                "duplicates": {
+                    "description": "похожие операции, уже записанные в кошелёк",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Movement"
+                    }
+                },
+                "external_id": {
+                    "description": "FITID из OFX или хеш записи QIF",
+                    "type": "string",
+                    "example": "202601270001"
+                },
+                "line": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "movement_id": {
+                    "description": "только после записи",
+                    "type": "integer",
+                    "example": 42
+                },
+                "type": {
+                    "type": "string",
+                    "example": "expense"
+                }
+            }
+        },
+        "models.ImportRowError": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string",
+                    "example": "invalid date \"31.02.2026\""
+                },
+                "line": {
+                    "type": "integer",
+                    "example": 7
+                }
+            }
+        },
+        "models.LogoutInput": {
+            "type": "object",
+            "required": [
+                "refresh_token"
+            ],
+            "properties": {
+                "refresh_token": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.MonthlyReport": {
+            "type": "object",
+            "properties": {
+                "base_currency": {
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.MonthlySummary"
+                    }
+                },
+                "missing_rates": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.MonthlySummary": {
+            "type": "object",
+            "properties": {
+                "expense": {
+                    "type": "string",
+                    "example": "40000.00"
+                },
+                "income": {
+                    "type": "string",
+                    "example": "100000.00"
+                },
+                "month": {
+                    "type": "string",
+                    "example": "2026-01"
+                },
+                "net_change": {
+                    "type": "string",
+                    "example": "60000.00"
+                }
+            }
+        },
+        "models.Movement": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "description": "в минимальных единицах валюты кошелька",
+                    "type": "string",
+                    "example": "150.50"
+                },
+                "category_id": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "currency": {
+                    "description": "валюта кошелька",
+                    "type": "string",
+                    "example": "USD"
+                },
+                "date": {
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "description": "заполнено только у операций в корзине",
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "external_id": {
+                    "description": "идентификатор операции в выписке банка",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "splits": {
+                    "description": "разбивка суммы по категориям; если есть, отчёты по категориям считаются по ней",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.MovementSplit"
+                    }
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Tag"
+                    }
+                },
+                "transfer_id": {
+                    "type": "integer"
+                },
+                "type": {
+                    "description": "\"income\" или \"expense\" или \"initial\"(только при создании кошелька с первоначальным балансом), \"transfer_out\"/\"transfer_in\" — ноги перевода",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                },
+                "version": {
+                    "description": "растёт при каждом изменении, отдаётся как ETag",
+                    "type": "integer",
+                    "example": 1
+                },
+                "wallet_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.MovementCursorPage": {
+            "type": "object",
+            "properties": {
+                "has_more": {
+                    "type": "boolean"
+                },
+                "movements": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Movement"
+                    }
+                },
+                "next_cursor": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.MovementSearchHit": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "description": "в минимальных единицах валюты кошелька",
+                    "type": "string",
+                    "example": "150.50"
+                },
+                "category_id": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "currency": {
+                    "description": "валюта кошелька",
+                    "type": "string",
+                    "example": "USD"
+                },
+                "date": {
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "description": "заполнено только у операций в корзине",
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "external_id": {
+                    "description": "идентификатор операции в выписке банка",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "rank": {
+                    "type": "number",
+                    "example": 0.0608
+                },
+                "snippet": {
+                    "type": "string",
+                    "example": "оплата \u003cb\u003eсантехнику\u003c/b\u003e за замену смесителя"
+                },
+                "splits": {
+                    "description": "разбивка суммы по категориям; если есть, отчёты по категориям считаются по ней",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.MovementSplit"
+                    }
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Tag"
+                    }
+                },
+                "transfer_id": {
+                    "type": "integer"
+                },
+                "type": {
+                    "description": "\"income\" или \"expense\" или \"initial\"(только при создании кошелька с первоначальным балансом), \"transfer_out\"/\"transfer_in\" — ноги перевода",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                },
+                "version": {
+                    "description": "растёт при каждом изменении, отдаётся как ETag",
+                    "type": "integer",
+                    "example": 1
+                },
+                "wallet_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.MovementSearchPage": {
+            "type": "object",
+            "properties": {
+                "has_more": {
+                    "type": "boolean"
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "offset": {
+                    "type": "integer"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.MovementSearchHit"
+                    }
+                }
+            }
+        },
+        "models.MovementSplit": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "string",
+                    "example": "100.00"
+                },
+                "category_id": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "movement_id": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "note": {
+                    "type": "string",
+                    "example": "Бытовая химия"
+                }
+            }
+        },
+        "models.Profile": {
+            "type": "object",
+            "properties": {
+                "balances": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CurrencyBalance"
+                    }
+                },
+                "base_currency": {
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "missing_rates": {
+                    "description": "валюты, для которых курс не найден",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "rate_date": {
+                    "description": "самая ранняя дата курса, использованного в пересчёте",
+                    "type": "string"
+                },
+                "total": {
+                    "description": "в базовой валюте, без валют с неизвестным курсом",
+                    "type": "string",
+                    "example": "135750.00"
+                },
+                "user": {
+                    "$ref": "#/definitions/models.User"
+                }
+            }
+        },
+        "models.RecurringMovement": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "string",
+                    "example": "45000.00"
+                },
+                "category_id": {
+                    "type": "integer",
+                    "example": 4
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "currency": {
+                    "description": "валюта кошелька",
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Rent"
+                },
+                "end_date": {
+                    "type": "string"
+                },
+                "frequency": {
+                    "description": "\"daily\", \"weekly\", \"monthly\", \"yearly\"",
+                    "type": "string",
+                    "example": "monthly"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "interval": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "next_date": {
+                    "description": "ближайшее ещё не созданное повторение",
+                    "type": "string",
+                    "example": "2026-02-05T09:00:00Z"
+                },
+                "paused": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "start_date": {
+                    "type": "string",
+                    "example": "2026-01-05T09:00:00Z"
+                },
+                "type": {
+                    "description": "\"income\" или \"expense\"",
+                    "type": "string",
+                    "example": "expense"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "wallet_id": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "models.RecurringOccurrence": {
+            "type": "object",
+            "properties": {
+                "date": {
+                    "type": "string"
+                },
+                "movement_id": {
+                    "type": "integer"
+                },
+                "skipped": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.RefreshInput": {
+            "type": "object",
+            "required": [
+                "refresh_token"
+            ],
+            "properties": {
+                "refresh_token": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.RegisterInput": {
+            "type": "object",
+            "required": [
+                "base_currency",
+                "email",
+                "password"
+            ],
+            "properties": {
+                "base_currency": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string",
+                    "minLength": 6
+                }
+            }
+        },
+        "models.RestoreResult": {
+            "type": "object",
+            "properties": {
+                "budgets": {
+                    "type": "integer"
+                },
+                "categories": {
+                    "type": "integer"
+                },
+                "movements": {
+                    "type": "integer"
+                },
+                "recurring": {
+                    "type": "integer"
+                },
+                "tags": {
+                    "type": "integer"
+                },
+                "transfers": {
+                    "type": "integer"
+                },
+                "wallets": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.SignInInput": {
+            "type": "object",
+            "required": [
+                "email",
+                "password"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.SkipOccurrenceInput": {
+            "type": "object",
+            "properties": {
+                "date": {
+                    "type": "string",
+                    "example": "2026-02-05T09:00:00Z"
+                }
+            }
+        },
+        "models.SplitInput": {
+            "type": "object",
+            "required": [
+                "amount",
+                "category_id"
+            ],
+            "properties": {
+                "amount": {
+                    "type": "string",
+                    "example": "100.00"
+                },
+                "category_id": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "note": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "example": "Бытовая химия"
+                }
+            }
+        },
+        "models.SummaryReport": {
+            "type": "object",
+            "properties": {
+                "base_currency": {
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "by_currency": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CurrencySummary"
+                    }
+                },
+                "missing_rates": {
+                    "description": "эти валюты не вошли в итог",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "movement_count": {
+                    "type": "integer",
+                    "example": 120
+                },
+                "net_balance": {
+                    "description": "income - expense",
+                    "type": "string",
+                    "example": "180000.00"
+                },
+                "rate_date": {
+                    "type": "string"
+                },
+                "total_balance": {
+                    "type": "string",
+                    "example": "150000.00"
+                },
+                "total_expense": {
+                    "type": "string",
+                    "example": "120000.00"
+                },
+                "total_income": {
+                    "type": "string",
+                    "example": "300000.00"
+                },
+                "wallet_count": {
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "models.Tag": {
+            "type": "object",
+            "properties": {
+                "color": {
+                    "type": "string",
+                    "example": "#FF8800"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "movement_count": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "name": {
+                    "type": "string",
+                    "example": "отпуск 2026"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer",
+                    "example": 10
+                }
+            }
+        },
+        "models.TagReport": {
+            "type": "object",
+            "properties": {
+                "base_currency": {
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TagSummary"
+                    }
+                },
+                "missing_rates": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "rate_date": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "expense"
+                }
+            }
+        },
+        "models.TagSummary": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer",
+                    "example": 9
+                },
+                "tag": {
+                    "type": "string",
+                    "example": "отпуск 2026"
+                },
+                "tag_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "total": {
+                    "type": "string",
+                    "example": "85000.00"
+                }
+            }
+        },
+        "models.Transfer": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "date": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "from_amount": {
+                    "type": "string",
+                    "example": "250.00"
+                },
+                "from_currency": {
+                    "type": "string"
+                },
+                "from_wallet_id": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "movements": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Movement"
+                    }
+                },
+                "rate": {
+                    "type": "number"
+                },
+                "to_amount": {
+                    "type": "string",
+                    "example": "22500.00"
+                },
+                "to_currency": {
+                    "type": "string"
+                },
+                "to_wallet_id": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Trash": {
             "type": "object",
             "properties": {
                 "categories": {
@@ -846,26 +5806,13 @@ const docTemplate = `{
                     "items": {
                         "$ref": "#/definitions/models.Category"
                     }
-                }
-            }
-        },
-        "handler.getAllMovementsResponse": {
-            "type": "object",
-            "properties": {
+                },
                 "movements": {
                     "type": "array",
                     "items": {
                         "$ref": "#/definitions/models.Movement"
                     }
                 },
-                "wallet": {
-                    "$ref": "#/definitions/models.Wallet"
-                }
-            }
-        },
-        "handler.getAllWalletsResponse": {
-            "type": "object",
-            "properties": {
                 "wallets": {
                     "type": "array",
                     "items": {
@@ -874,292 +5821,203 @@ const docTemplate = `{
                 }
             }
         },
-        "handler.getMovementByIdResponse": {
+        "models.UpdateBudgetInput": {
             "type": "object",
             "properties": {
-                "movement": {
-                    "$ref": "#/definitions/models.Movement"
+                "amount": {
+                    "type": "string",
+                    "example": "35000.00"
                 },
-                "wallet": {
-                    "$ref": "#/definitions/models.Wallet"
-                }
-            }
-        },
-        "handler.statusResponse": {
-            "type": "object",
-            "properties": {
-                "status": {
-                    "type": "string"
+                "rollover": {
+                    "type": "boolean",
+                    "example": false
                 }
             }
         },
-        "models.Category": {
+        "models.UpdateMovementInput": {
             "type": "object",
-            "required": [
-                "name"
-            ],
             "properties": {
-                "created_at": {
-                    "type": "string"
-                },
-                "icon": {
+                "amount": {
                     "type": "string",
-                    "example": "🛒"
+                    "example": "200.00"
                 },
-                "id": {
+                "category_id": {
                     "type": "integer",
-                    "example": 1
+                    "example": 3
                 },
-                "name": {
+                "date": {
                     "type": "string",
-                    "example": "Groceries"
+                    "example": "2026-01-28T15:00:00Z"
                 },
-                "type": {
-                    "description": "\"income\" or \"expense\"",
+                "description": {
                     "type": "string",
-                    "example": "expense"
+                    "example": "Updated description"
                 },
-                "updated_at": {
-                    "type": "string"
+                "splits": {
+                    "description": "новая разбивка целиком; пустой список убирает разбивку, без поля — остаётся прежней",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SplitInput"
+                    }
                 },
-                "usage_count": {
-                    "type": "integer",
-                    "example": 5
+                "tags": {
+                    "description": "новый набор меток целиком; пустой список снимает все",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        1,
+                        2
+                    ]
                 },
-                "user_id": {
-                    "type": "integer",
-                    "example": 10
+                "type": {
+                    "type": "string",
+                    "example": "income"
                 }
             }
         },
-        "models.CreateCategoryInput": {
+        "models.UpdateTagInput": {
             "type": "object",
-            "required": [
-                "name",
-                "type"
-            ],
             "properties": {
-                "icon": {
+                "color": {
                     "type": "string",
-                    "example": "💰"
+                    "example": "#0088FF"
                 },
                 "name": {
                     "type": "string",
-                    "example": "Salary"
-                },
-                "type": {
-                    "type": "string",
-                    "enum": [
-                        "income",
-                        "expense"
-                    ],
-                    "example": "income"
+                    "maxLength": 50,
+                    "minLength": 1,
+                    "example": "командировка"
                 }
             }
         },
-        "models.CreateMovementInput": {
+        "models.UpdateTransferInput": {
             "type": "object",
-            "required": [
-                "amount",
-                "category",
-                "date",
-                "type"
-            ],
             "properties": {
                 "amount": {
-                    "type": "number",
-                    "example": 150.5
-                },
-                "category": {
-                    "type": "integer",
-                    "example": 1
+                    "type": "string",
+                    "example": "300.00"
                 },
                 "date": {
                     "type": "string",
-                    "example": "2026-01-27T12:00:00Z"
+                    "example": "2026-01-28T15:00:00Z"
                 },
                 "description": {
                     "type": "string",
-                    "example": "Grocery shopping"
+                    "example": "Updated description"
                 },
-                "type": {
-                    "type": "string",
-                    "enum": [
-                        "income",
-                        "expense",
-                        "initial"
-                    ],
-                    "example": "expense"
-                }
-            }
-        },
-        "models.CreateWalletInput": {
-            "type": "object",
-            "required": [
-                "currency",
-                "name"
-            ],
-            "properties": {
-                "balance": {
+                "rate": {
                     "type": "number",
-                    "minimum": 0,
-                    "example": 1000
-                },
-                "currency": {
-                    "type": "string",
-                    "example": "USD"
+                    "example": 90
                 },
-                "name": {
+                "to_amount": {
                     "type": "string",
-                    "example": "Salary Card"
-                }
-            }
-        },
-        "models.LogoutInput": {
-            "type": "object",
-            "required": [
-                "refresh_token"
-            ],
-            "properties": {
-                "refresh_token": {
-                    "type": "string"
+                    "example": "27000.00"
                 }
             }
         },
-        "models.RefreshInput": {
+        "models.User": {
             "type": "object",
             "required": [
-                "refresh_token"
+                "base_currency",
+                "email"
             ],
             "properties": {
-                "refresh_token": {
+                "base_currency": {
                     "type": "string"
-                }
-            }
-        },
-        "models.RegisterInput": {
-            "type": "object",
-            "required": [
-                "email",
-                "password"
-            ],
-            "properties": {
-                "email": {
+                },
+                "created_at": {
                     "type": "string"
                 },
-                "password": {
-                    "type": "string",
-                    "minLength": 6
-                }
-            }
-        },
-        "models.SignInInput": {
-            "type": "object",
-            "required": [
-                "email",
-                "password"
-            ],
-            "properties": {
                 "email": {
                     "type": "string"
                 },
-                "password": {
+                "id": {
+                    "type": "integer"
+                },
+                "updated_at": {
                     "type": "string"
                 }
             }
         },
-        "models.Movement": {
+        "models.Wallet": {
             "type": "object",
             "properties": {
-                "amount": {
-                    "type": "integer"
-                },
-                "category_id": {
-                    "type": "integer"
+                "balance": {
+                    "description": "в минимальных единицах валюты",
+                    "type": "string",
+                    "example": "150.00"
                 },
                 "created_at": {
                     "type": "string"
                 },
-                "date": {
-                    "type": "string"
+                "currency": {
+                    "type": "string",
+                    "example": "USD"
                 },
-                "description": {
+                "deleted_at": {
+                    "description": "заполнено только у кошельков в корзине",
                     "type": "string"
                 },
-                "id": {
+                "display_id": {
                     "type": "integer"
                 },
-                "type": {
-                    "description": "\"income\" или \"expense\" или \"initial\"(только при создании кошелька с первоначальным балансом)",
-                    "type": "string"
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Main Wallet"
                 },
                 "updated_at": {
                     "type": "string"
                 },
                 "user_id": {
-                    "type": "integer"
-                },
-                "wallet_id": {
-                    "type": "integer"
-                }
-            }
-        },
-        "models.UpdateMovementInput": {
-            "type": "object",
-            "properties": {
-                "amount": {
-                    "type": "number",
-                    "example": 200
-                },
-                "category_id": {
                     "type": "integer",
-                    "example": 3
-                },
-                "date": {
-                    "type": "string",
-                    "example": "2026-01-28T15:00:00Z"
-                },
-                "description": {
-                    "type": "string",
-                    "example": "Updated description"
+                    "example": 10
                 },
-                "type": {
-                    "type": "string",
-                    "example": "income"
+                "version": {
+                    "description": "растёт при каждом изменении, отдаётся как ETag",
+                    "type": "integer",
+                    "example": 1
                 }
             }
         },
-        "models.Wallet": {
+        "models.WalletStats": {
             "type": "object",
             "properties": {
                 "balance": {
-                    "type": "integer",
-                    "example": 15000
-                },
-                "created_at": {
-                    "type": "string"
+                    "type": "string",
+                    "example": "1500.00"
                 },
                 "currency": {
                     "type": "string",
                     "example": "USD"
                 },
-                "display_id": {
-                    "type": "integer"
+                "last_movement": {
+                    "type": "string"
                 },
-                "id": {
+                "movement_count": {
                     "type": "integer",
-                    "example": 1
+                    "example": 25
                 },
-                "name": {
+                "total_expense": {
                     "type": "string",
-                    "example": "Main Wallet"
+                    "example": "1500.00"
                 },
-                "updated_at": {
-                    "type": "string"
+                "total_income": {
+                    "type": "string",
+                    "example": "3000.00"
                 },
-                "user_id": {
+                "wallet_id": {
                     "type": "integer",
-                    "example": 10
+                    "example": 1
+                },
+                "wallet_name": {
+                    "type": "string",
+                    "example": "Main Wallet"
                 }
             }
         }
@@ -0,0 +1,21 @@
+package audit
+
+import "context"
+
+// Meta — сведения о запросе, который привёл к изменению
+type Meta struct {
+	RequestID string
+	IP        string
+}
+
+type metaKey struct{}
+
+func WithMeta(ctx context.Context, meta Meta) context.Context {
+	return context.WithValue(ctx, metaKey{}, meta)
+}
+
+// MetaFrom возвращает пустые сведения для фоновых задач, запущенных не из запроса
+func MetaFrom(ctx context.Context) Meta {
+	meta, _ := ctx.Value(metaKey{}).(Meta)
+	return meta
+}
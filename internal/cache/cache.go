@@ -20,14 +20,23 @@ type Category interface {
 	GetCatgory(ctx context.Context, id int) (*models.Category, error)
 }
 
+type Idempotency interface {
+	Reserve(ctx context.Context, key string, record models.IdempotencyRecord, ttl time.Duration) (bool, error)
+	Get(ctx context.Context, key string) (*models.IdempotencyRecord, error)
+	Save(ctx context.Context, key string, record models.IdempotencyRecord) error
+	Delete(ctx context.Context, key string) error
+}
+
 type Cache struct {
 	Authorization
 	Category
+	Idempotency
 }
 
 func NewCache(rdb *redis.Client) *Cache {
 	return &Cache{
 		Authorization: NewAuthRedis(rdb),
 		Category:      NewCategoryRedis(rdb),
+		Idempotency:   NewIdempotencyRedis(rdb),
 	}
 }
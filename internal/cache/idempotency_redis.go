@@ -0,0 +1,57 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/goonsorrow/finance-tracker-api/internal/models"
+	"github.com/redis/go-redis/v9"
+)
+
+type IdempotencyRedis struct {
+	rdb *redis.Client
+}
+
+func NewIdempotencyRedis(rdb *redis.Client) *IdempotencyRedis {
+	return &IdempotencyRedis{rdb: rdb}
+}
+
+// Reserve занимает ключ, если он свободен; false — запрос с этим ключом уже был
+func (c IdempotencyRedis) Reserve(ctx context.Context, key string, record models.IdempotencyRecord, ttl time.Duration) (bool, error) {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return false, err
+	}
+	return c.rdb.SetNX(ctx, key, data, ttl).Result()
+}
+
+// Get возвращает nil, если ключа нет
+func (c IdempotencyRedis) Get(ctx context.Context, key string) (*models.IdempotencyRecord, error) {
+	data, err := c.rdb.Get(ctx, key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var record models.IdempotencyRecord
+	if err := json.Unmarshal(data, &record); err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+// Save записывает ответ, не продлевая срок, отсчитанный от первого запроса
+func (c IdempotencyRedis) Save(ctx context.Context, key string, record models.IdempotencyRecord) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	return c.rdb.Set(ctx, key, data, redis.KeepTTL).Err()
+}
+
+func (c IdempotencyRedis) Delete(ctx context.Context, key string) error {
+	return c.rdb.Del(ctx, key).Err()
+}
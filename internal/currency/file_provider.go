@@ -0,0 +1,78 @@
+package currency
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FileProvider читает курсы из CSV вида: date,base,quote,rate (дата в формате YYYY-MM-DD).
+// Отдаёт всю историю из файла до запрошенной даты включительно.
+type FileProvider struct {
+	path string
+}
+
+func NewFileProvider(path string) *FileProvider {
+	return &FileProvider{path: path}
+}
+
+func (p *FileProvider) Name() string {
+	return "file"
+}
+
+func (p *FileProvider) FetchRates(ctx context.Context, date time.Time) ([]Rate, error) {
+	f, err := os.Open(p.path)
+	if err != nil {
+		return nil, fmt.Errorf("[FileProvider.FetchRates] open %s: %w", p.path, err)
+	}
+	defer f.Close()
+
+	reader := csv.NewReader(f)
+	reader.TrimLeadingSpace = true
+	reader.Comment = '#'
+
+	var rates []Rate
+	limit := truncateDay(date)
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("[FileProvider.FetchRates] line %d: %w", line, err)
+		}
+		if line == 1 && strings.EqualFold(record[0], "date") {
+			continue
+		}
+		if len(record) != 4 {
+			return nil, fmt.Errorf("[FileProvider.FetchRates] line %d: expected 4 columns, got %d", line, len(record))
+		}
+
+		day, err := time.Parse(time.DateOnly, record[0])
+		if err != nil {
+			return nil, fmt.Errorf("[FileProvider.FetchRates] line %d: invalid date: %w", line, err)
+		}
+		if day.After(limit) {
+			continue
+		}
+		value, err := strconv.ParseFloat(record[3], 64)
+		if err != nil || value <= 0 {
+			return nil, fmt.Errorf("[FileProvider.FetchRates] line %d: invalid rate %q", line, record[3])
+		}
+
+		rates = append(rates, Rate{
+			Base:   strings.ToUpper(record[1]),
+			Quote:  strings.ToUpper(record[2]),
+			Date:   day,
+			Rate:   value,
+			Source: p.Name(),
+		})
+	}
+	return rates, nil
+}
@@ -0,0 +1,91 @@
+package currency
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// HTTPProvider забирает курсы из API в формате frankfurter:
+// GET {baseURL}/{YYYY-MM-DD}?from=USD&to=EUR,RUB -> {"base":"USD","date":"2026-01-02","rates":{"EUR":0.92}}
+// Подходит и для локальной заглушки с тем же контрактом.
+type HTTPProvider struct {
+	baseURL string
+	base    string
+	client  *http.Client
+}
+
+func NewHTTPProvider(baseURL, base string) *HTTPProvider {
+	return &HTTPProvider{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		base:    strings.ToUpper(base),
+		client:  &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+type httpRatesResponse struct {
+	Base  string             `json:"base"`
+	Date  string             `json:"date"`
+	Rates map[string]float64 `json:"rates"`
+}
+
+func (p *HTTPProvider) Name() string {
+	return "http"
+}
+
+func (p *HTTPProvider) FetchRates(ctx context.Context, date time.Time) ([]Rate, error) {
+	var quotes []string
+	for _, c := range SupportedCurrencies {
+		if c != p.base {
+			quotes = append(quotes, c)
+		}
+	}
+
+	query := url.Values{}
+	query.Set("from", p.base)
+	query.Set("to", strings.Join(quotes, ","))
+	endpoint := fmt.Sprintf("%s/%s?%s", p.baseURL, date.Format(time.DateOnly), query.Encode())
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("[HTTPProvider.FetchRates] build request: %w", err)
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("[HTTPProvider.FetchRates] request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("[HTTPProvider.FetchRates] unexpected status %d", resp.StatusCode)
+	}
+
+	var body httpRatesResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("[HTTPProvider.FetchRates] decode response: %w", err)
+	}
+
+	day, err := time.Parse(time.DateOnly, body.Date)
+	if err != nil {
+		return nil, fmt.Errorf("[HTTPProvider.FetchRates] invalid date %q: %w", body.Date, err)
+	}
+
+	rates := make([]Rate, 0, len(body.Rates))
+	for quote, value := range body.Rates {
+		if value <= 0 || !IsSupported(quote) {
+			continue
+		}
+		rates = append(rates, Rate{
+			Base:   strings.ToUpper(body.Base),
+			Quote:  strings.ToUpper(quote),
+			Date:   day,
+			Rate:   value,
+			Source: p.Name(),
+		})
+	}
+	return rates, nil
+}
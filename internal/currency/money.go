@@ -0,0 +1,181 @@
+package currency
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+var ErrInvalidAmount = errors.New("invalid amount")
+
+// Количество знаков после запятой (минимальных единиц) по ISO 4217.
+// Для валют, которых нет в списке, считаем два знака.
+var minorUnits = map[string]int{
+	"USD": 2,
+	"EUR": 2,
+	"RUB": 2,
+	"GBP": 2,
+	"JPY": 0,
+}
+
+const defaultExponent = 2
+
+func Exponent(code string) int {
+	if exp, ok := minorUnits[strings.ToUpper(code)]; ok {
+		return exp
+	}
+	return defaultExponent
+}
+
+// ParseAmount переводит десятичную строку ("1234.5", "-10") в минимальные единицы валюты.
+// Знаков после точки не может быть больше, чем есть у валюты.
+func ParseAmount(s, code string) (int64, error) {
+	exp := Exponent(code)
+
+	negative, intPart, fracPart, err := splitDecimal(s)
+	if err != nil {
+		return 0, err
+	}
+	if len(fracPart) > exp {
+		return 0, fmt.Errorf("%w: %s allows at most %d decimal places", ErrInvalidAmount, strings.ToUpper(code), exp)
+	}
+
+	digits := intPart + fracPart + strings.Repeat("0", exp-len(fracPart))
+	amount, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q is out of range", ErrInvalidAmount, s)
+	}
+	if negative {
+		amount = -amount
+	}
+	return amount, nil
+}
+
+// splitDecimal разбирает строку на знак, целую и дробную части без потери точности
+func splitDecimal(s string) (bool, string, string, error) {
+	s = strings.TrimSpace(s)
+	negative := false
+	if strings.HasPrefix(s, "-") || strings.HasPrefix(s, "+") {
+		negative = s[0] == '-'
+		s = s[1:]
+	}
+
+	intPart, fracPart, _ := strings.Cut(s, ".")
+	if intPart == "" && fracPart == "" {
+		return false, "", "", fmt.Errorf("%w: empty value", ErrInvalidAmount)
+	}
+	for _, part := range []string{intPart, fracPart} {
+		for _, r := range part {
+			if r < '0' || r > '9' {
+				return false, "", "", fmt.Errorf("%w: %q is not a decimal number", ErrInvalidAmount, s)
+			}
+		}
+	}
+	if intPart == "" {
+		intPart = "0"
+	}
+	return negative, intPart, fracPart, nil
+}
+
+// FormatAmount печатает сумму в минимальных единицах как десятичную строку: 15050 USD -> "150.50"
+func FormatAmount(amount int64, code string) string {
+	exp := Exponent(code)
+
+	sign := ""
+	abs := uint64(amount)
+	if amount < 0 {
+		sign = "-"
+		abs = uint64(-amount)
+	}
+	digits := strconv.FormatUint(abs, 10)
+	if exp == 0 {
+		return sign + digits
+	}
+	if len(digits) <= exp {
+		digits = strings.Repeat("0", exp-len(digits)+1) + digits
+	}
+	return sign + digits[:len(digits)-exp] + "." + digits[len(digits)-exp:]
+}
+
+// Convert пересчитывает сумму в минимальных единицах from в минимальные единицы to по курсу
+// "сколько единиц to за одну единицу from" с учётом разного числа знаков у валют.
+func Convert(amount int64, from, to string, rate float64) int64 {
+	scale := math.Pow10(Exponent(to) - Exponent(from))
+	return int64(math.Round(float64(amount) * rate * scale))
+}
+
+// ImpliedRate — курс, который получается из фактических сумм обмена
+func ImpliedRate(fromAmount int64, from string, toAmount int64, to string) float64 {
+	if fromAmount == 0 {
+		return 0
+	}
+	scale := math.Pow10(Exponent(from) - Exponent(to))
+	return float64(toAmount) / float64(fromAmount) * scale
+}
+
+// Money — сумма в минимальных единицах вместе с валютой, в JSON отдаётся десятичной строкой
+type Money struct {
+	Amount   int64
+	Currency string
+}
+
+func NewMoney(amount int64, code string) Money {
+	return Money{Amount: amount, Currency: code}
+}
+
+func (m Money) String() string {
+	return FormatAmount(m.Amount, m.Currency)
+}
+
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(m.String())
+}
+
+// Decimal — сумма так, как её прислал клиент: строкой "150.50" или JSON-числом 150.50.
+// Число не проходит через float64, поэтому точность не теряется.
+type Decimal string
+
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	if len(data) > 0 && data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		*d = Decimal(s)
+		return nil
+	}
+	var n json.Number
+	if err := json.Unmarshal(data, &n); err != nil {
+		return fmt.Errorf("%w: expected decimal string or number", ErrInvalidAmount)
+	}
+	*d = Decimal(n.String())
+	return nil
+}
+
+// Minor переводит значение в минимальные единицы валюты
+func (d Decimal) Minor(code string) (int64, error) {
+	return ParseAmount(string(d), code)
+}
+
+// Sign проверяет запись числа без привязки к валюте и отдаёт его знак: -1, 0 или 1
+func (d Decimal) Sign() (int, error) {
+	negative, intPart, fracPart, err := splitDecimal(string(d))
+	if err != nil {
+		return 0, err
+	}
+	if strings.Trim(intPart+fracPart, "0") == "" {
+		return 0, nil
+	}
+	if negative {
+		return -1, nil
+	}
+	return 1, nil
+}
@@ -0,0 +1,137 @@
+package currency
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestParseAmount(t *testing.T) {
+	tests := []struct {
+		in      string
+		code    string
+		want    int64
+		wantErr bool
+	}{
+		{"150.50", "USD", 15050, false},
+		{"150.5", "USD", 15050, false},
+		{"-10", "EUR", -1000, false},
+		{"+0.01", "RUB", 1, false},
+		{".5", "GBP", 50, false},
+		{"1500", "JPY", 1500, false},
+		{"1500.5", "JPY", 0, true},
+		{"1.234", "USD", 0, true},
+		{"", "USD", 0, true},
+		{"1e3", "USD", 0, true},
+		{"12,50", "USD", 0, true},
+		{"99999999999999999999", "USD", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseAmount(tt.in, tt.code)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseAmount(%q, %s) error = %v, wantErr %v", tt.in, tt.code, err, tt.wantErr)
+			continue
+		}
+		if err != nil && !errors.Is(err, ErrInvalidAmount) {
+			t.Errorf("ParseAmount(%q, %s) error = %v, want %v", tt.in, tt.code, err, ErrInvalidAmount)
+		}
+		if got != tt.want {
+			t.Errorf("ParseAmount(%q, %s) = %d, want %d", tt.in, tt.code, got, tt.want)
+		}
+	}
+}
+
+func TestFormatAmount(t *testing.T) {
+	tests := []struct {
+		amount int64
+		code   string
+		want   string
+	}{
+		{15050, "USD", "150.50"},
+		{5, "USD", "0.05"},
+		{-5, "EUR", "-0.05"},
+		{0, "RUB", "0.00"},
+		{1500, "JPY", "1500"},
+		{-1500, "JPY", "-1500"},
+		{100, "XXX", "1.00"},
+		{math.MinInt64, "USD", "-92233720368547758.08"},
+	}
+	for _, tt := range tests {
+		if got := FormatAmount(tt.amount, tt.code); got != tt.want {
+			t.Errorf("FormatAmount(%d, %s) = %q, want %q", tt.amount, tt.code, got, tt.want)
+		}
+	}
+}
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		amount   int64
+		from, to string
+		rate     float64
+		want     int64
+	}{
+		{10000, "USD", "EUR", 0.92, 9200},
+		{10000, "USD", "JPY", 150, 15000},
+		{15000, "JPY", "USD", 1.0 / 150, 10000},
+		{1, "USD", "RUB", 90.5, 91},
+	}
+	for _, tt := range tests {
+		if got := Convert(tt.amount, tt.from, tt.to, tt.rate); got != tt.want {
+			t.Errorf("Convert(%d %s->%s @ %v) = %d, want %d", tt.amount, tt.from, tt.to, tt.rate, got, tt.want)
+		}
+	}
+
+	if got := ImpliedRate(10000, "USD", 15000, "JPY"); got != 150 {
+		t.Errorf("ImpliedRate(100 USD -> 15000 JPY) = %v, want 150", got)
+	}
+	if got := ImpliedRate(0, "USD", 15000, "JPY"); got != 0 {
+		t.Errorf("ImpliedRate with zero amount = %v, want 0", got)
+	}
+}
+
+func TestMoneyJSON(t *testing.T) {
+	data, err := json.Marshal(struct {
+		Total Money `json:"total"`
+	}{NewMoney(-123456, "USD")})
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if string(data) != `{"total":"-1234.56"}` {
+		t.Errorf("Marshal = %s", data)
+	}
+}
+
+func TestDecimal(t *testing.T) {
+	tests := []struct {
+		json    string
+		want    Decimal
+		wantErr bool
+	}{
+		{`"150.50"`, "150.50", false},
+		{`150.50`, "150.50", false},
+		{`0.1`, "0.1", false},
+		{`null`, "", false},
+		{`true`, "", true},
+	}
+	for _, tt := range tests {
+		var d Decimal
+		err := json.Unmarshal([]byte(tt.json), &d)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Unmarshal(%s) error = %v, wantErr %v", tt.json, err, tt.wantErr)
+			continue
+		}
+		if d != tt.want {
+			t.Errorf("Unmarshal(%s) = %q, want %q", tt.json, d, tt.want)
+		}
+	}
+
+	for in, want := range map[Decimal]int{"-0.5": -1, "0.00": 0, "+3": 1} {
+		if got, err := in.Sign(); err != nil || got != want {
+			t.Errorf("Decimal(%q).Sign() = %d, %v; want %d", in, got, err, want)
+		}
+	}
+	if _, err := Decimal("abc").Sign(); err == nil {
+		t.Error(`Decimal("abc").Sign(): want error`)
+	}
+}
@@ -0,0 +1,96 @@
+package currency
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var ErrRateNotFound = errors.New("exchange rate not found")
+
+// Rate — сколько единиц Quote стоит одна единица Base на дату Date
+type Rate struct {
+	Base   string    `db:"base" json:"base"`
+	Quote  string    `db:"quote" json:"quote"`
+	Date   time.Time `db:"date" json:"date"`
+	Rate   float64   `db:"rate" json:"rate"`
+	Source string    `db:"source" json:"source"`
+}
+
+// RateProvider — внешний источник курсов
+type RateProvider interface {
+	Name() string
+	// FetchRates отдаёт курсы, действующие на дату. Провайдер может вернуть и более раннюю историю.
+	FetchRates(ctx context.Context, date time.Time) ([]Rate, error)
+}
+
+// RateStore хранит дневные курсы по парам
+type RateStore interface {
+	SaveRates(ctx context.Context, rates []Rate) error
+	// GetRate отдаёт последний курс пары с датой не позже date
+	GetRate(ctx context.Context, base, quote string, date time.Time) (Rate, error)
+}
+
+// Converter ищет курс пары напрямую, через обратную пару или кросс-курсом через Pivot
+type Converter struct {
+	store RateStore
+	pivot string
+}
+
+func NewConverter(store RateStore, pivot string) *Converter {
+	return &Converter{store: store, pivot: strings.ToUpper(pivot)}
+}
+
+// Rate отдаёт курс from->to, действующий на дату. Для кросс-курса дата — самая ранняя из двух ног.
+func (c *Converter) Rate(ctx context.Context, from, to string, date time.Time) (Rate, error) {
+	from, to = strings.ToUpper(from), strings.ToUpper(to)
+	if from == to {
+		return Rate{Base: from, Quote: to, Date: truncateDay(date), Rate: 1, Source: "identity"}, nil
+	}
+
+	rate, err := c.pairRate(ctx, from, to, date)
+	if err == nil || !errors.Is(err, ErrRateNotFound) {
+		return rate, err
+	}
+	if c.pivot == "" || from == c.pivot || to == c.pivot {
+		return Rate{}, fmt.Errorf("%w: %s/%s on %s", ErrRateNotFound, from, to, date.Format(time.DateOnly))
+	}
+
+	toPivot, err := c.pairRate(ctx, from, c.pivot, date)
+	if err != nil {
+		return Rate{}, fmt.Errorf("%w: %s/%s on %s", ErrRateNotFound, from, to, date.Format(time.DateOnly))
+	}
+	fromPivot, err := c.pairRate(ctx, c.pivot, to, date)
+	if err != nil {
+		return Rate{}, fmt.Errorf("%w: %s/%s on %s", ErrRateNotFound, from, to, date.Format(time.DateOnly))
+	}
+
+	effective := toPivot.Date
+	if fromPivot.Date.Before(effective) {
+		effective = fromPivot.Date
+	}
+	return Rate{Base: from, Quote: to, Date: effective, Rate: toPivot.Rate * fromPivot.Rate, Source: "cross:" + c.pivot}, nil
+}
+
+func (c *Converter) pairRate(ctx context.Context, from, to string, date time.Time) (Rate, error) {
+	rate, err := c.store.GetRate(ctx, from, to, date)
+	if err == nil {
+		return rate, nil
+	}
+	if !errors.Is(err, ErrRateNotFound) {
+		return Rate{}, err
+	}
+
+	inverse, err := c.store.GetRate(ctx, to, from, date)
+	if err != nil {
+		return Rate{}, err
+	}
+	return Rate{Base: from, Quote: to, Date: inverse.Date, Rate: 1 / inverse.Rate, Source: inverse.Source}, nil
+}
+
+func truncateDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,83 @@
+package currency
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// Refresher подтягивает курсы из провайдера в хранилище
+type Refresher struct {
+	provider RateProvider
+	store    RateStore
+	logger   *slog.Logger
+}
+
+func NewRefresher(provider RateProvider, store RateStore, logger *slog.Logger) *Refresher {
+	return &Refresher{provider: provider, store: store, logger: logger}
+}
+
+func (r *Refresher) Refresh(ctx context.Context) error {
+	rates, err := r.provider.FetchRates(ctx, time.Now().UTC())
+	if err != nil {
+		return fmt.Errorf("[Refresher.Refresh] fetch from %s: %w", r.provider.Name(), err)
+	}
+	if len(rates) == 0 {
+		r.logger.Warn("rate provider returned no rates", slog.String("provider", r.provider.Name()))
+		return nil
+	}
+
+	if err := r.store.SaveRates(ctx, rates); err != nil {
+		return fmt.Errorf("[Refresher.Refresh] save rates: %w", err)
+	}
+	r.logger.Info("exchange rates refreshed",
+		slog.String("provider", r.provider.Name()),
+		slog.Int("count", len(rates)))
+	return nil
+}
+
+// Backfill загружает курсы за каждый день периода, чтобы отчёты за прошлые даты
+// находили курс, действовавший тогда. Курсы, которые провайдер повторяет за соседние
+// дни (выходные, история из файла), сохраняются один раз
+func (r *Refresher) Backfill(ctx context.Context, from, to time.Time) (int, error) {
+	from, to = truncateDay(from), truncateDay(to)
+	if to.Before(from) {
+		return 0, fmt.Errorf("[Refresher.Backfill] end date %s is before start date %s", to.Format(time.DateOnly), from.Format(time.DateOnly))
+	}
+
+	seen := make(map[string]bool)
+	saved := 0
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		if err := ctx.Err(); err != nil {
+			return saved, err
+		}
+		rates, err := r.provider.FetchRates(ctx, day)
+		if err != nil {
+			return saved, fmt.Errorf("[Refresher.Backfill] fetch %s from %s: %w", day.Format(time.DateOnly), r.provider.Name(), err)
+		}
+
+		fresh := rates[:0]
+		for _, rate := range rates {
+			key := rate.Base + rate.Quote + rate.Date.Format(time.DateOnly)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			fresh = append(fresh, rate)
+		}
+		if len(fresh) == 0 {
+			continue
+		}
+		if err := r.store.SaveRates(ctx, fresh); err != nil {
+			return saved, fmt.Errorf("[Refresher.Backfill] save rates for %s: %w", day.Format(time.DateOnly), err)
+		}
+		saved += len(fresh)
+	}
+	r.logger.Info("exchange rates backfilled",
+		slog.String("provider", r.provider.Name()),
+		slog.String("from", from.Format(time.DateOnly)),
+		slog.String("to", to.Format(time.DateOnly)),
+		slog.Int("count", saved))
+	return saved, nil
+}
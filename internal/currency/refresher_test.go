@@ -0,0 +1,74 @@
+package currency
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+// historyProvider, как FileProvider, отдаёт всю историю до запрошенной даты
+type historyProvider struct {
+	rates []Rate
+	calls int
+}
+
+func (p *historyProvider) Name() string { return "history" }
+
+func (p *historyProvider) FetchRates(_ context.Context, date time.Time) ([]Rate, error) {
+	p.calls++
+	var out []Rate
+	for _, r := range p.rates {
+		if !r.Date.After(date) {
+			out = append(out, r)
+		}
+	}
+	return out, nil
+}
+
+type memoryStore struct {
+	saved []Rate
+}
+
+func (s *memoryStore) SaveRates(_ context.Context, rates []Rate) error {
+	s.saved = append(s.saved, rates...)
+	return nil
+}
+
+func (s *memoryStore) GetRate(context.Context, string, string, time.Time) (Rate, error) {
+	return Rate{}, ErrRateNotFound
+}
+
+func day(s string) time.Time {
+	t, _ := time.Parse(time.DateOnly, s)
+	return t
+}
+
+func TestRefresherBackfill(t *testing.T) {
+	provider := &historyProvider{rates: []Rate{
+		{Base: "USD", Quote: "EUR", Date: day("2025-12-31"), Rate: 0.91},
+		{Base: "USD", Quote: "EUR", Date: day("2026-01-02"), Rate: 0.92},
+		{Base: "USD", Quote: "EUR", Date: day("2026-01-05"), Rate: 0.93},
+	}}
+	store := &memoryStore{}
+	refresher := NewRefresher(provider, store, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	saved, err := refresher.Backfill(context.Background(), day("2026-01-01"), day("2026-01-04"))
+	if err != nil {
+		t.Fatalf("Backfill: %v", err)
+	}
+	if provider.calls != 4 {
+		t.Errorf("provider called %d times, want one call per day (4)", provider.calls)
+	}
+	// курс, действующий на начало периода, нужен отчётам за первый день; повторы сохраняются один раз,
+	// курс после конца периода не запрашивается
+	if saved != 2 || len(store.saved) != 2 ||
+		!store.saved[0].Date.Equal(day("2025-12-31")) || !store.saved[1].Date.Equal(day("2026-01-02")) {
+		t.Errorf("saved %d rate(s): %+v, want the 2025-12-31 and 2026-01-02 rates", saved, store.saved)
+	}
+
+	if _, err := refresher.Backfill(context.Background(), day("2026-01-04"), day("2026-01-01")); err == nil {
+		t.Error("Backfill with reversed period: want error")
+	}
+}
@@ -0,0 +1,51 @@
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+
+	"github.com/goonsorrow/finance-tracker-api/internal/models"
+)
+
+type csvWriter struct {
+	w *csv.Writer
+}
+
+func newCSVWriter(w io.Writer) (*csvWriter, error) {
+	// BOM, чтобы Excel открыл файл в UTF-8
+	if _, err := io.WriteString(w, "\ufeff"); err != nil {
+		return nil, err
+	}
+	cw := csv.NewWriter(w)
+	if err := cw.Write(columns); err != nil {
+		return nil, err
+	}
+	return &csvWriter{w: cw}, nil
+}
+
+func (c *csvWriter) Write(movement models.ExportMovement) error {
+	return c.w.Write(record(movement))
+}
+
+func (c *csvWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+type ndjsonWriter struct {
+	enc *json.Encoder
+}
+
+func newNDJSONWriter(w io.Writer) *ndjsonWriter {
+	return &ndjsonWriter{enc: json.NewEncoder(w)}
+}
+
+// Write пишет операцию одной строкой JSON — Encoder добавляет перевод строки сам
+func (n *ndjsonWriter) Write(movement models.ExportMovement) error {
+	return n.enc.Encode(movement)
+}
+
+func (n *ndjsonWriter) Close() error {
+	return nil
+}
@@ -0,0 +1,87 @@
+// Package export пишет операции в CSV, NDJSON и XLSX построчно, не держа выгрузку в памяти
+package export
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/goonsorrow/finance-tracker-api/internal/currency"
+	"github.com/goonsorrow/finance-tracker-api/internal/models"
+)
+
+var ErrUnsupportedFormat = errors.New("unsupported export format")
+
+const (
+	FormatCSV    = "csv"
+	FormatNDJSON = "ndjson"
+	FormatXLSX   = "xlsx"
+)
+
+// Writer принимает операции по одной; Close дописывает конец файла
+type Writer interface {
+	Write(movement models.ExportMovement) error
+	Close() error
+}
+
+func NewWriter(format string, w io.Writer) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVWriter(w)
+	case FormatNDJSON:
+		return newNDJSONWriter(w), nil
+	case FormatXLSX:
+		return newXLSXWriter(w)
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedFormat, format)
+	}
+}
+
+func ContentType(format string) string {
+	switch format {
+	case FormatCSV:
+		return "text/csv; charset=utf-8"
+	case FormatNDJSON:
+		return "application/x-ndjson"
+	case FormatXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	default:
+		return "application/octet-stream"
+	}
+}
+
+// Колонки табличных форматов
+var columns = []string{"id", "date", "wallet_id", "wallet", "type", "amount", "currency", "category_id", "category", "description", "transfer_id", "external_id"}
+
+// record — значения колонок в виде строк; сумма — десятичная строка в валюте кошелька
+func record(m models.ExportMovement) []string {
+	return []string{
+		strconv.Itoa(m.ID),
+		m.Date.UTC().Format("2006-01-02T15:04:05Z"),
+		strconv.Itoa(m.WalletID),
+		m.WalletName,
+		m.Type,
+		currency.FormatAmount(m.Amount, m.Currency),
+		m.Currency,
+		optionalInt(m.CategoryID),
+		optionalString(m.CategoryName),
+		m.Description,
+		optionalInt(m.TransferID),
+		optionalString(m.ExternalID),
+	}
+}
+
+func optionalInt(v *int) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.Itoa(*v)
+}
+
+func optionalString(v *string) string {
+	if v == nil {
+		return ""
+	}
+	return *v
+}
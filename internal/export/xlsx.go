@@ -0,0 +1,150 @@
+package export
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/goonsorrow/finance-tracker-api/internal/models"
+)
+
+// Минимальная книга XLSX из одного листа. Служебные части пишутся сразу, лист — построчно
+// в отдельную запись zip, строки хранятся inline, без общей таблицы строк, поэтому
+// ничего не накапливается в памяти.
+var xlsxParts = []struct{ name, body string }{
+	{"[Content_Types].xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+		`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+		`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>` +
+		`</Types>`},
+	{"_rels/.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+		`</Relationships>`},
+	{"xl/workbook.xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+		`<sheets><sheet name="Movements" sheetId="1" r:id="rId1"/></sheets>` +
+		`</workbook>`},
+	{"xl/_rels/workbook.xml.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+		`<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>` +
+		`</Relationships>`},
+	// стили: 0 — обычная ячейка, 1 — дата и время, 2 — жирный заголовок
+	{"xl/styles.xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+		`<numFmts count="1"><numFmt numFmtId="164" formatCode="yyyy-mm-dd hh:mm"/></numFmts>` +
+		`<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts>` +
+		`<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>` +
+		`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>` +
+		`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>` +
+		`<cellXfs count="3">` +
+		`<xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>` +
+		`<xf numFmtId="164" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>` +
+		`<xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/>` +
+		`</cellXfs>` +
+		`</styleSheet>`},
+}
+
+const (
+	xlsxSheetHeader = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`
+	xlsxSheetFooter = `</sheetData></worksheet>`
+
+	styleDate   = 1
+	styleHeader = 2
+)
+
+// Нулевой день дат Excel
+var excelEpoch = time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+
+type xlsxWriter struct {
+	zw    *zip.Writer
+	sheet io.Writer
+	buf   strings.Builder
+}
+
+func newXLSXWriter(w io.Writer) (*xlsxWriter, error) {
+	zw := zip.NewWriter(w)
+	for _, part := range xlsxParts {
+		f, err := zw.Create(part.name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := io.WriteString(f, part.body); err != nil {
+			return nil, err
+		}
+	}
+
+	sheet, err := zw.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, err
+	}
+	x := &xlsxWriter{zw: zw, sheet: sheet}
+	if _, err := io.WriteString(sheet, xlsxSheetHeader); err != nil {
+		return nil, err
+	}
+
+	x.buf.WriteString("<row>")
+	for _, name := range columns {
+		x.stringCell(name, styleHeader)
+	}
+	x.buf.WriteString("</row>")
+	return x, x.flush()
+}
+
+func (x *xlsxWriter) Write(m models.ExportMovement) error {
+	values := record(m)
+
+	x.buf.WriteString("<row>")
+	for i, name := range columns {
+		switch name {
+		case "id", "wallet_id", "amount", "category_id", "transfer_id":
+			x.numberCell(values[i])
+		case "date":
+			days := m.Date.UTC().Sub(excelEpoch).Hours() / 24
+			x.buf.WriteString(`<c s="` + strconv.Itoa(styleDate) + `"><v>` + strconv.FormatFloat(days, 'f', -1, 64) + `</v></c>`)
+		default:
+			x.stringCell(values[i], 0)
+		}
+	}
+	x.buf.WriteString("</row>")
+	return x.flush()
+}
+
+func (x *xlsxWriter) Close() error {
+	if _, err := io.WriteString(x.sheet, xlsxSheetFooter); err != nil {
+		return err
+	}
+	return x.zw.Close()
+}
+
+func (x *xlsxWriter) numberCell(value string) {
+	if value == "" {
+		x.buf.WriteString("<c/>")
+		return
+	}
+	x.buf.WriteString("<c><v>" + value + "</v></c>")
+}
+
+func (x *xlsxWriter) stringCell(value string, style int) {
+	x.buf.WriteString(`<c t="inlineStr"`)
+	if style != 0 {
+		x.buf.WriteString(` s="` + strconv.Itoa(style) + `"`)
+	}
+	x.buf.WriteString(`><is><t xml:space="preserve">`)
+	xml.EscapeText(&x.buf, []byte(value))
+	x.buf.WriteString("</t></is></c>")
+}
+
+func (x *xlsxWriter) flush() error {
+	_, err := io.WriteString(x.sheet, x.buf.String())
+	x.buf.Reset()
+	return err
+}
@@ -0,0 +1,215 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"mime"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/goonsorrow/finance-tracker-api/internal/models"
+	"github.com/goonsorrow/finance-tracker-api/internal/repository"
+	"github.com/goonsorrow/finance-tracker-api/internal/service"
+)
+
+// Верхняя граница тела запроса; предельный размер самого файла задаётся в конфиге
+const maxAttachmentRequestSize = 100 << 20
+
+type getAllAttachmentsResponse struct {
+	Data []models.Attachment `json:"attachments"`
+}
+
+// attachmentPath разбирает ID кошелька, операции и (если есть) вложения из пути
+func (h *Handler) attachmentPath(c *gin.Context, withAttachment bool) (walletId, movementId, attachmentId int, ok bool) {
+	walletId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		h.newErrorResponse(c, http.StatusBadRequest, err, "invalid wallet id format")
+		return 0, 0, 0, false
+	}
+	movementId, err = strconv.Atoi(c.Param("trId"))
+	if err != nil {
+		h.newErrorResponse(c, http.StatusBadRequest, err, "invalid movement id format")
+		return 0, 0, 0, false
+	}
+	if withAttachment {
+		attachmentId, err = strconv.Atoi(c.Param("attId"))
+		if err != nil {
+			h.newErrorResponse(c, http.StatusBadRequest, err, "invalid attachment id format")
+			return 0, 0, 0, false
+		}
+	}
+	return walletId, movementId, attachmentId, true
+}
+
+// @Summary Прикрепить файл к операции
+// @Description Чек или документ в multipart/form-data. Допускаются JPEG, PNG, GIF, WebP и PDF,
+// @Description тип определяется по содержимому файла
+// @Security Bearer
+// @Tags attachments
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path int true "Wallet ID"
+// @Param trId path int true "Movement ID"
+// @Param file formData file true "Файл"
+// @Param Idempotency-Key header string false "Ключ идемпотентности: повтор с тем же ключом вернёт первый ответ"
+// @Success 201 {object} models.Attachment
+// @Failure 400 {object} map[string]string "File is required"
+// @Failure 404 {object} map[string]string "Movement not found"
+// @Failure 413 {object} map[string]string "File is too large"
+// @Failure 415 {object} map[string]string "Unsupported file type"
+// @Failure 422 {object} map[string]string "Ключ уже использован с другим запросом"
+// @Router /api/wallets/{id}/movements/{trId}/attachments [post]
+func (h *Handler) uploadAttachment(c *gin.Context) {
+	userId, err := h.getUserId(c)
+	if err != nil {
+		return
+	}
+	walletId, movementId, _, ok := h.attachmentPath(c, false)
+	if !ok {
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxAttachmentRequestSize)
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			h.newErrorResponse(c, http.StatusRequestEntityTooLarge, err, "file is too large")
+			return
+		}
+		h.newErrorResponse(c, http.StatusBadRequest, err, "file is required")
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		h.newErrorResponse(c, http.StatusBadRequest, err, "failed to read file")
+		return
+	}
+	defer file.Close()
+
+	// загрузка во внешнее хранилище может занять больше обычных 5 секунд
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 60*time.Second)
+	defer cancel()
+
+	attachment, err := h.services.Attachment.Upload(ctx, userId, walletId, movementId, models.AttachmentUpload{
+		Filename: fileHeader.Filename,
+		Size:     fileHeader.Size,
+		Body:     file,
+	})
+	if err != nil {
+		h.attachmentError(c, err, "error while uploading attachment")
+		return
+	}
+	c.JSON(http.StatusCreated, attachment)
+}
+
+// @Summary Вложения операции
+// @Security Bearer
+// @Tags attachments
+// @Produce json
+// @Param id path int true "Wallet ID"
+// @Param trId path int true "Movement ID"
+// @Success 200 {object} handler.getAllAttachmentsResponse
+// @Failure 404 {object} map[string]string "Movement not found"
+// @Router /api/wallets/{id}/movements/{trId}/attachments [get]
+func (h *Handler) getAllAttachments(c *gin.Context) {
+	userId, err := h.getUserId(c)
+	if err != nil {
+		return
+	}
+	walletId, movementId, _, ok := h.attachmentPath(c, false)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	attachments, err := h.services.Attachment.GetAll(ctx, userId, walletId, movementId)
+	if err != nil {
+		h.attachmentError(c, err, "error while getting attachments")
+		return
+	}
+	c.JSON(http.StatusOK, getAllAttachmentsResponse{Data: attachments})
+}
+
+// @Summary Скачать вложение
+// @Security Bearer
+// @Tags attachments
+// @Produce octet-stream
+// @Param id path int true "Wallet ID"
+// @Param trId path int true "Movement ID"
+// @Param attId path int true "Attachment ID"
+// @Success 200 {file} binary
+// @Failure 404 {object} map[string]string "Attachment not found"
+// @Router /api/wallets/{id}/movements/{trId}/attachments/{attId} [get]
+func (h *Handler) downloadAttachment(c *gin.Context) {
+	userId, err := h.getUserId(c)
+	if err != nil {
+		return
+	}
+	walletId, movementId, attachmentId, ok := h.attachmentPath(c, true)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 60*time.Second)
+	defer cancel()
+
+	attachment, body, err := h.services.Attachment.Open(ctx, userId, walletId, movementId, attachmentId)
+	if err != nil {
+		h.attachmentError(c, err, "error while getting attachment")
+		return
+	}
+	defer body.Close()
+
+	c.DataFromReader(http.StatusOK, attachment.Size, attachment.ContentType, body, map[string]string{
+		"Content-Disposition":    mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Filename}),
+		"X-Content-Type-Options": "nosniff",
+	})
+}
+
+// @Summary Удалить вложение
+// @Security Bearer
+// @Tags attachments
+// @Produce json
+// @Param id path int true "Wallet ID"
+// @Param trId path int true "Movement ID"
+// @Param attId path int true "Attachment ID"
+// @Success 200 {object} handler.statusResponse
+// @Failure 404 {object} map[string]string "Attachment not found"
+// @Router /api/wallets/{id}/movements/{trId}/attachments/{attId} [delete]
+func (h *Handler) deleteAttachment(c *gin.Context) {
+	userId, err := h.getUserId(c)
+	if err != nil {
+		return
+	}
+	walletId, movementId, attachmentId, ok := h.attachmentPath(c, true)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	if err := h.services.Attachment.Delete(ctx, userId, walletId, movementId, attachmentId); err != nil {
+		h.attachmentError(c, err, "error while deleting attachment")
+		return
+	}
+	c.JSON(http.StatusOK, statusResponse{Status: "ok"})
+}
+
+func (h *Handler) attachmentError(c *gin.Context, err error, msg string) {
+	switch {
+	case errors.Is(err, repository.ErrRecordNotFound):
+		h.newErrorResponse(c, http.StatusNotFound, err, "attachment not found")
+	case errors.Is(err, service.ErrAttachmentTooLarge):
+		h.newErrorResponse(c, http.StatusRequestEntityTooLarge, err, err.Error())
+	case errors.Is(err, service.ErrAttachmentType):
+		h.newErrorResponse(c, http.StatusUnsupportedMediaType, err, err.Error())
+	default:
+		h.newErrorResponse(c, http.StatusInternalServerError, err, msg)
+	}
+}
@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/goonsorrow/finance-tracker-api/internal/models"
+	"github.com/goonsorrow/finance-tracker-api/internal/service"
+)
+
+type getAuditHistoryResponse struct {
+	Data []models.AuditEntry `json:"history"`
+}
+
+// @Summary История изменений
+// @Description Записи журнала по кошельку, транзакции или категории, новые первыми: кто, когда и с какого
+// @Description адреса изменил запись, её состояние до и после. История доступна и для удалённых записей
+// @Security Bearer
+// @Tags audit
+// @Produce json
+// @Param entity path string true "wallet | movement | category"
+// @Param id path int true "Entity ID"
+// @Param limit query int false "Количество записей (1..200, по умолчанию 50)"
+// @Param offset query int false "Смещение"
+// @Success 200 {object} handler.getAuditHistoryResponse
+// @Failure 400 {object} map[string]string "Invalid entity or paging"
+// @Router /api/audit/{entity}/{id} [get]
+func (h *Handler) getAuditHistory(c *gin.Context) {
+	userId, err := h.getUserId(c)
+	if err != nil {
+		return
+	}
+
+	entity := c.Param("entity")
+	entityId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		h.newErrorResponse(c, http.StatusBadRequest, err, "invalid "+entity+" id")
+		return
+	}
+
+	var input models.AuditHistoryInput
+	if err := c.ShouldBindQuery(&input); err != nil {
+		h.newErrorResponse(c, http.StatusBadRequest, err, "invalid query parameters")
+		return
+	}
+	if err := input.Validate(); err != nil {
+		h.newErrorResponse(c, http.StatusBadRequest, err, err.Error())
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	history, err := h.services.Audit.History(ctx, userId, entity, entityId, input)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidAuditEntity) {
+			h.newErrorResponse(c, http.StatusBadRequest, err, err.Error())
+			return
+		}
+		h.newErrorResponse(c, http.StatusInternalServerError, err, "error while getting history")
+		return
+	}
+	c.JSON(http.StatusOK, getAuditHistoryResponse{Data: history})
+}
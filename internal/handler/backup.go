@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/goonsorrow/finance-tracker-api/internal/models"
+	"github.com/goonsorrow/finance-tracker-api/internal/repository"
+	"github.com/goonsorrow/finance-tracker-api/internal/service"
+)
+
+// Предельный размер архива при восстановлении
+const maxBackupSize = 100 << 20
+
+// @Summary Резервная копия аккаунта
+// @Description Версионированный JSON-архив всех кошельков, категорий, меток, операций, переводов, бюджетов,
+// @Description регулярных операций и настроек пользователя. Суммы — десятичные строки в валюте кошелька
+// @Security Bearer
+// @Tags backup
+// @Produce json
+// @Success 200 {object} models.Backup
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/backup [get]
+func (h *Handler) exportBackup(c *gin.Context) {
+	userId, err := h.getUserId(c)
+	if err != nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), time.Minute)
+	defer cancel()
+
+	backup, err := h.services.Backup.Export(ctx, userId)
+	if err != nil {
+		h.newErrorResponse(c, http.StatusInternalServerError, err, "error while creating backup")
+		return
+	}
+
+	filename := fmt.Sprintf("backup-%s.json", backup.ExportedAt.Format("20060102"))
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.JSON(http.StatusOK, backup)
+}
+
+// @Summary Восстановление из резервной копии
+// @Description Переносит архив из GET /api/backup в аккаунт без своих кошельков, категорий, меток и бюджетов.
+// @Description Записи получают новые ID, всё восстанавливается в одной транзакции
+// @Security Bearer
+// @Tags backup
+// @Accept json
+// @Produce json
+// @Param input body models.Backup true "Архив"
+// @Success 200 {object} models.RestoreResult
+// @Failure 400 {object} map[string]string "Invalid backup"
+// @Failure 409 {object} map[string]string "Account is not empty"
+// @Failure 413 {object} map[string]string "Backup is too large"
+// @Router /api/backup/restore [post]
+func (h *Handler) restoreBackup(c *gin.Context) {
+	userId, err := h.getUserId(c)
+	if err != nil {
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxBackupSize)
+	var backup models.Backup
+	if err := c.ShouldBindJSON(&backup); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			h.newErrorResponse(c, http.StatusRequestEntityTooLarge, err, "backup is too large")
+			return
+		}
+		h.newErrorResponse(c, http.StatusBadRequest, err, "invalid backup")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Minute)
+	defer cancel()
+
+	result, err := h.services.Backup.Restore(ctx, userId, backup)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrAccountNotEmpty):
+			h.newErrorResponse(c, http.StatusConflict, err, err.Error())
+		case errors.Is(err, service.ErrInvalidBackup):
+			h.newErrorResponse(c, http.StatusBadRequest, err, err.Error())
+		case errors.Is(err, repository.ErrDuplicate):
+			h.newErrorResponse(c, http.StatusBadRequest, err, "backup contains conflicting records")
+		default:
+			h.newErrorResponse(c, http.StatusInternalServerError, err, "error while restoring backup")
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,307 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/goonsorrow/finance-tracker-api/internal/currency"
+	"github.com/goonsorrow/finance-tracker-api/internal/models"
+	"github.com/goonsorrow/finance-tracker-api/internal/repository"
+	"github.com/goonsorrow/finance-tracker-api/internal/service"
+)
+
+type getAllBudgetsResponse struct {
+	Data []models.Budget `json:"budgets"`
+}
+
+type budgetStatusResponse struct {
+	Data []models.BudgetStatus `json:"budgets"`
+}
+
+// @Summary Создать бюджет
+// @Description Месячный лимит расходов по категории. Валюта по умолчанию — базовая валюта пользователя,
+// @Description при rollover неизрасходованный остаток переносится на следующий месяц
+// @Security Bearer
+// @Tags budgets
+// @Accept json
+// @Produce json
+// @Param input body models.CreateBudgetInput true "Категория + Лимит"
+// @Param Idempotency-Key header string false "Ключ идемпотентности: повтор с тем же ключом вернёт первый ответ"
+// @Success 201 {object} map[string]int "Budget ID"
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 404 {object} map[string]string "Category not found"
+// @Failure 409 {object} map[string]string "Budget for category already exists"
+// @Failure 422 {object} map[string]string "Ключ уже использован с другим запросом"
+// @Router /api/budgets/ [post]
+func (h *Handler) createBudget(c *gin.Context) {
+	userId, err := h.getUserId(c)
+	if err != nil {
+		return
+	}
+
+	var input models.CreateBudgetInput
+	if err := c.BindJSON(&input); err != nil {
+		h.newErrorResponse(c, http.StatusBadRequest, err, "invalid input data")
+		return
+	}
+
+	if err := input.Validate(); err != nil {
+		h.newErrorResponse(c, http.StatusBadRequest, err, err.Error())
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	id, err := h.services.Budget.Create(ctx, userId, input)
+	if err != nil {
+		h.budgetError(c, err, "error while creating budget")
+		return
+	}
+
+	c.JSON(http.StatusCreated, map[string]interface{}{
+		"id": id,
+	})
+}
+
+// @Summary Список бюджетов
+// @Security Bearer
+// @Tags budgets
+// @Produce json
+// @Success 200 {object} handler.getAllBudgetsResponse
+// @Router /api/budgets/ [get]
+func (h *Handler) getAllBudgets(c *gin.Context) {
+	userId, err := h.getUserId(c)
+	if err != nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	budgets, err := h.services.Budget.GetAll(ctx, userId)
+	if err != nil {
+		h.newErrorResponse(c, http.StatusInternalServerError, err, "failed to get budgets")
+		return
+	}
+	if budgets == nil {
+		budgets = []models.Budget{}
+	}
+
+	c.JSON(http.StatusOK, getAllBudgetsResponse{
+		Data: budgets,
+	})
+}
+
+// @Summary Бюджет по ID
+// @Security Bearer
+// @Tags budgets
+// @Produce json
+// @Param id path int true "Budget ID"
+// @Success 200 {object} models.Budget
+// @Failure 404 {object} map[string]string "Not found"
+// @Router /api/budgets/{id} [get]
+func (h *Handler) getBudgetByID(c *gin.Context) {
+	userId, err := h.getUserId(c)
+	if err != nil {
+		return
+	}
+
+	budgetId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		h.newErrorResponse(c, http.StatusBadRequest, err, "invalid budget id")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	budget, err := h.services.Budget.GetById(ctx, userId, budgetId)
+	if err != nil {
+		h.budgetError(c, err, "error while getting budget")
+		return
+	}
+
+	c.JSON(http.StatusOK, budget)
+}
+
+// @Summary Обновить бюджет
+// @Description Изменить лимит или перенос остатка. Новый лимит действует и для прошлых месяцев
+// @Security Bearer
+// @Tags budgets
+// @Accept json
+// @Produce json
+// @Param id path int true "Budget ID"
+// @Param input body models.UpdateBudgetInput true "Поля для обновления"
+// @Success 200 {object} handler.statusResponse
+// @Failure 404 {object} map[string]string "Not found"
+// @Router /api/budgets/{id} [put]
+func (h *Handler) updateBudgetByID(c *gin.Context) {
+	userId, err := h.getUserId(c)
+	if err != nil {
+		return
+	}
+
+	budgetId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		h.newErrorResponse(c, http.StatusBadRequest, err, "invalid budget id")
+		return
+	}
+
+	var input models.UpdateBudgetInput
+	if err := c.BindJSON(&input); err != nil {
+		h.newErrorResponse(c, http.StatusBadRequest, err, "invalid input data")
+		return
+	}
+
+	if err := input.Validate(); err != nil {
+		h.newErrorResponse(c, http.StatusBadRequest, err, err.Error())
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	if err := h.services.Budget.Update(ctx, userId, budgetId, input); err != nil {
+		h.budgetError(c, err, "error while updating budget")
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{Status: "ok"})
+}
+
+// @Summary Удалить бюджет
+// @Security Bearer
+// @Tags budgets
+// @Produce json
+// @Param id path int true "Budget ID"
+// @Success 200 {object} handler.statusResponse
+// @Failure 404 {object} map[string]string "Not found"
+// @Router /api/budgets/{id} [delete]
+func (h *Handler) deleteBudgetByID(c *gin.Context) {
+	userId, err := h.getUserId(c)
+	if err != nil {
+		return
+	}
+
+	budgetId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		h.newErrorResponse(c, http.StatusBadRequest, err, "invalid budget id")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	if err := h.services.Budget.Delete(ctx, userId, budgetId); err != nil {
+		h.budgetError(c, err, "error while deleting budget")
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{Status: "ok"})
+}
+
+// @Summary Исполнение бюджетов за месяц
+// @Description Лимит, перенесённый остаток, потрачено, осталось и процент по каждому бюджету.
+// @Description Расходы из кошельков в других валютах пересчитываются в валюту бюджета
+// @Security Bearer
+// @Tags budgets
+// @Produce json
+// @Param month query string false "Месяц YYYY-MM, по умолчанию текущий"
+// @Success 200 {object} handler.budgetStatusResponse
+// @Failure 400 {object} map[string]string "Invalid month"
+// @Router /api/budgets/status [get]
+func (h *Handler) getBudgetStatus(c *gin.Context) {
+	userId, err := h.getUserId(c)
+	if err != nil {
+		return
+	}
+
+	var input models.BudgetPeriodInput
+	if err := c.ShouldBindQuery(&input); err != nil {
+		h.newErrorResponse(c, http.StatusBadRequest, err, "invalid month")
+		return
+	}
+
+	if err := input.Validate(); err != nil {
+		h.newErrorResponse(c, http.StatusBadRequest, err, err.Error())
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	statuses, err := h.services.Budget.Status(ctx, userId, input)
+	if err != nil {
+		h.newErrorResponse(c, http.StatusInternalServerError, err, "failed to get budget status")
+		return
+	}
+
+	c.JSON(http.StatusOK, budgetStatusResponse{
+		Data: statuses,
+	})
+}
+
+// @Summary История бюджета
+// @Description Исполнение бюджета по месяцам от начала действия до указанного месяца
+// @Security Bearer
+// @Tags budgets
+// @Produce json
+// @Param id path int true "Budget ID"
+// @Param month query string false "Последний месяц YYYY-MM, по умолчанию текущий"
+// @Success 200 {object} handler.budgetStatusResponse
+// @Failure 404 {object} map[string]string "Not found"
+// @Router /api/budgets/{id}/history [get]
+func (h *Handler) getBudgetHistory(c *gin.Context) {
+	userId, err := h.getUserId(c)
+	if err != nil {
+		return
+	}
+
+	budgetId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		h.newErrorResponse(c, http.StatusBadRequest, err, "invalid budget id")
+		return
+	}
+
+	var input models.BudgetPeriodInput
+	if err := c.ShouldBindQuery(&input); err != nil {
+		h.newErrorResponse(c, http.StatusBadRequest, err, "invalid month")
+		return
+	}
+
+	if err := input.Validate(); err != nil {
+		h.newErrorResponse(c, http.StatusBadRequest, err, err.Error())
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	history, err := h.services.Budget.History(ctx, userId, budgetId, input)
+	if err != nil {
+		h.budgetError(c, err, "failed to get budget history")
+		return
+	}
+
+	c.JSON(http.StatusOK, budgetStatusResponse{
+		Data: history,
+	})
+}
+
+func (h *Handler) budgetError(c *gin.Context, err error, msg string) {
+	switch {
+	case errors.Is(err, repository.ErrRecordNotFound):
+		h.newErrorResponse(c, http.StatusNotFound, err, "not found")
+	case errors.Is(err, repository.ErrDuplicate):
+		h.newErrorResponse(c, http.StatusConflict, err, "budget for this category already exists")
+	case errors.Is(err, service.ErrInvalidBudget), errors.Is(err, currency.ErrInvalidAmount):
+		h.newErrorResponse(c, http.StatusBadRequest, err, err.Error())
+	default:
+		h.newErrorResponse(c, http.StatusInternalServerError, err, msg)
+	}
+}
@@ -23,7 +23,9 @@ type getAllCategoriesResponse struct {
 // @Accept json
 // @Produce json
 // @Param input body models.CreateCategoryInput true "Name + Type + Icon"
+// @Param Idempotency-Key header string false "Ключ идемпотентности: повтор с тем же ключом вернёт первый ответ"
 // @Success 201 {object} map[string]int
+// @Failure 422 {object} map[string]string "Ключ уже использован с другим запросом"
 // @Router /api/categories/ [post]
 func (h *Handler) createCategory(c *gin.Context) {
 	userId, err := h.getUserId(c)
@@ -94,6 +96,7 @@ func (h *Handler) getAllCategories(c *gin.Context) {
 // @Produce json
 // @Param id path int true "Category ID"
 // @Success 200 {object} models.Category
+// @Header 200 {string} ETag "Версия категории для If-Match"
 // @Failure 400 {object} map[string]string "Invalid ID"
 // @Failure 404 {object} map[string]string "Not found"
 // @Router /api/categories/{id} [get]
@@ -121,6 +124,7 @@ func (h *Handler) getCategoryByID(c *gin.Context) {
 		return
 	}
 
+	setETag(c, category.Version)
 	c.JSON(http.StatusOK, category)
 }
 
@@ -130,9 +134,11 @@ func (h *Handler) getCategoryByID(c *gin.Context) {
 // @Tags categories
 // @Produce json
 // @Param id path int true "Category ID"
+// @Param If-Match header string false "ETag из GET /api/categories/{id}"
 // @Success 200 {object} models.Category
 // @Failure 400 {object} map[string]string "Invalid ID"
 // @Failure 404 {object} map[string]string "Not found"
+// @Failure 412 {object} map[string]string "Категория изменена другим запросом"
 // @Router /api/categories/{id} [get]
 func (h *Handler) updateCategoryByID(c *gin.Context) {
 	userId, err := h.getUserId(c)
@@ -146,6 +152,12 @@ func (h *Handler) updateCategoryByID(c *gin.Context) {
 		return
 	}
 
+	version, err := ifMatchVersion(c)
+	if err != nil {
+		h.newErrorResponse(c, ifMatchStatus(err), err, err.Error())
+		return
+	}
+
 	var input models.UpdateCategoryInput
 	if err := c.BindJSON(&input); err != nil {
 		h.newErrorResponse(c, http.StatusBadRequest, err, "error while reading input")
@@ -155,9 +167,13 @@ func (h *Handler) updateCategoryByID(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
 	defer cancel()
 
-	err = h.services.Category.Update(ctx, userId, categoryId, input)
+	err = h.services.Category.Update(ctx, userId, categoryId, input, version)
 
 	if err != nil {
+		if errors.Is(err, repository.ErrVersionConflict) {
+			h.newErrorResponse(c, http.StatusPreconditionFailed, err, "category was changed, fetch it again")
+			return
+		}
 		if errors.Is(err, repository.ErrRecordNotFound) {
 			h.newErrorResponse(c, http.StatusNotFound, err, "category not found")
 			return
@@ -170,13 +186,15 @@ func (h *Handler) updateCategoryByID(c *gin.Context) {
 }
 
 // @Summary Удалить категорию
-// @Description Удалить категорию
+// @Description Переносит категорию в корзину; операции и бюджеты сохраняют ссылку на неё
 // @Security Bearer
 // @Tags categories
 // @Produce json
 // @Param id path int true "Category ID"
+// @Param If-Match header string false "ETag из GET /api/categories/{id}"
 // @Success 200 {object} handler.statusResponse
 // @Failure 400 {object} map[string]string "Invalid ID"
+// @Failure 412 {object} map[string]string "Категория изменена другим запросом"
 // @Router /api/categories/{id} [delete]
 func (h *Handler) deleteCategoryByID(c *gin.Context) {
 	userId, err := h.getUserId(c)
@@ -190,11 +208,21 @@ func (h *Handler) deleteCategoryByID(c *gin.Context) {
 		return
 	}
 
+	version, err := ifMatchVersion(c)
+	if err != nil {
+		h.newErrorResponse(c, ifMatchStatus(err), err, err.Error())
+		return
+	}
+
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
 	defer cancel()
 
-	err = h.services.Category.Delete(ctx, userId, categoryId)
+	err = h.services.Category.Delete(ctx, userId, categoryId, version)
 	if err != nil {
+		if errors.Is(err, repository.ErrVersionConflict) {
+			h.newErrorResponse(c, http.StatusPreconditionFailed, err, "category was changed, fetch it again")
+			return
+		}
 		if errors.Is(err, repository.ErrRecordNotFound) {
 			h.newErrorResponse(c, http.StatusNotFound, err, "category not found")
 			return
@@ -7,6 +7,7 @@
 package handler
 
 import (
+	"fmt"
 	"log/slog"
 
 	"github.com/gin-gonic/gin"
@@ -21,8 +22,13 @@ type Handler struct {
 	logger   *slog.Logger
 }
 
-func (h *Handler) InitRoutes() *gin.Engine {
+func (h *Handler) InitRoutes(trustedProxies []string) (*gin.Engine, error) {
 	router := gin.New()
+	// по умолчанию gin верит X-Forwarded-For от любого клиента, а ClientIP попадает в журнал аудита
+	if err := router.SetTrustedProxies(trustedProxies); err != nil {
+		return nil, fmt.Errorf("invalid trusted proxies: %w", err)
+	}
+	router.Use(h.RequestMetaMiddleware())
 	router.Use(h.LoggingMiddleware())
 	router.Use(gin.Recovery())
 
@@ -33,7 +39,7 @@ func (h *Handler) InitRoutes() *gin.Engine {
 		auth.POST("/refresh", h.refresh)
 		auth.POST("/logout-all", h.logoutAll)
 		auth.POST("/logout", h.logout)
-		auth.GET("/me", h.getProfile)
+		auth.GET("/me", h.userIdentity, h.getProfile)
 	}
 	api := router.Group("/api")
 	api.Use(h.userIdentity)
@@ -42,30 +48,106 @@ func (h *Handler) InitRoutes() *gin.Engine {
 	{
 		wallets.GET("/", h.getAllWallets)
 		wallets.GET("/:id", h.getWalletByID)
-		wallets.POST("/", h.createWallet)
+		wallets.POST("/", h.idempotent(maxIdempotentBodySize), h.createWallet)
 		wallets.PUT("/:id", h.updateWalletByID)
 		wallets.DELETE("/:id", h.deleteWalletByID)
+		wallets.GET("/:id/stats", h.getWalletStats)
+		wallets.POST("/:id/import", h.idempotent(maxImportSize), h.importStatement)
 
 		movements := wallets.Group("/:id/movements")
 		{
 			movements.GET("/", h.getAllMovements)
 			movements.GET("/:trId", h.getMovementByID)
-			movements.POST("/", h.createMovement)
+			movements.POST("/", h.idempotent(maxIdempotentBodySize), h.createMovement)
 			movements.PUT("/:trId", h.updateMovementByID)
 			movements.DELETE("/:trId", h.deleteMovementByID)
+			movements.GET("/:trId/attachments", h.getAllAttachments)
+			movements.POST("/:trId/attachments", h.idempotent(maxAttachmentRequestSize), h.uploadAttachment)
+			movements.GET("/:trId/attachments/:attId", h.downloadAttachment)
+			movements.DELETE("/:trId/attachments/:attId", h.deleteAttachment)
 		}
 
 	}
+	movements := api.Group("/movements")
+	{
+		movements.GET("/", h.getUserMovements)
+		movements.GET("/export", h.exportMovements)
+		movements.GET("/search", h.searchMovements)
+	}
+	transfers := api.Group("/transfers")
+	{
+		transfers.GET("/", h.getAllTransfers)
+		transfers.GET("/:id", h.getTransferByID)
+		transfers.POST("/", h.idempotent(maxIdempotentBodySize), h.createTransfer)
+		transfers.PUT("/:id", h.updateTransferByID)
+		transfers.DELETE("/:id", h.deleteTransferByID)
+	}
+	reports := api.Group("/reports")
+	{
+		reports.GET("/summary", h.getSummaryReport)
+		reports.GET("/categories", h.getCategoryReport)
+		reports.GET("/monthly", h.getMonthlyReport)
+		reports.GET("/tags", h.getTagReport)
+		reports.GET("/fx", h.getFXReport)
+	}
+	budgets := api.Group("/budgets")
+	{
+		budgets.GET("/", h.getAllBudgets)
+		budgets.GET("/status", h.getBudgetStatus)
+		budgets.GET("/:id", h.getBudgetByID)
+		budgets.GET("/:id/history", h.getBudgetHistory)
+		budgets.POST("/", h.idempotent(maxIdempotentBodySize), h.createBudget)
+		budgets.PUT("/:id", h.updateBudgetByID)
+		budgets.DELETE("/:id", h.deleteBudgetByID)
+	}
+	recurring := api.Group("/recurring")
+	{
+		recurring.GET("/", h.getAllRecurring)
+		recurring.GET("/:id", h.getRecurringByID)
+		recurring.GET("/:id/preview", h.previewRecurring)
+		recurring.POST("/", h.idempotent(maxIdempotentBodySize), h.createRecurring)
+		recurring.POST("/:id/pause", h.pauseRecurring)
+		recurring.POST("/:id/resume", h.resumeRecurring)
+		recurring.POST("/:id/skip", h.skipRecurringOccurrence)
+		recurring.DELETE("/:id", h.deleteRecurringByID)
+	}
+	api.GET("/rates", h.getRate)
+
+	backup := api.Group("/backup")
+	{
+		backup.GET("", h.exportBackup)
+		backup.POST("/restore", h.restoreBackup)
+	}
+
+	api.GET("/audit/:entity/:id", h.getAuditHistory)
+
+	trash := api.Group("/trash")
+	{
+		trash.GET("", h.getTrash)
+		trash.POST("/wallets/:id/restore", h.restoreWallet)
+		trash.POST("/movements/:id/restore", h.restoreMovement)
+		trash.POST("/categories/:id/restore", h.restoreCategory)
+	}
+
 	categories := api.Group("/categories")
 	{
 		categories.GET("/", h.getAllCategories)
 		categories.GET("/:id", h.getCategoryByID)
-		categories.POST("/", h.createCategory)
+		categories.POST("/", h.idempotent(maxIdempotentBodySize), h.createCategory)
 		categories.PUT("/:id", h.updateCategoryByID)
 		categories.DELETE("/:id", h.deleteCategoryByID)
 	}
 
+	tags := api.Group("/tags")
+	{
+		tags.GET("/", h.getAllTags)
+		tags.GET("/:id", h.getTagByID)
+		tags.POST("/", h.idempotent(maxIdempotentBodySize), h.createTag)
+		tags.PUT("/:id", h.updateTagByID)
+		tags.DELETE("/:id", h.deleteTagByID)
+	}
+
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
-	return router
+	return router, nil
 }
@@ -0,0 +1,140 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/goonsorrow/finance-tracker-api/internal/currency"
+	"github.com/goonsorrow/finance-tracker-api/internal/importer"
+	"github.com/goonsorrow/finance-tracker-api/internal/models"
+	"github.com/goonsorrow/finance-tracker-api/internal/repository"
+	"github.com/goonsorrow/finance-tracker-api/internal/service"
+)
+
+// Максимальный размер загружаемой выписки
+const maxImportSize = 10 << 20
+
+// @Summary Импорт выписки (CSV, OFX/QFX, QIF)
+// @Description Файл выписки передаётся в multipart/form-data, формат по умолчанию определяется по расширению.
+// @Description Для CSV нужно сопоставление колонок (JSON models.CSVMapping). В OFX/QIF операции с уже
+// @Description импортированным идентификатором (FITID) пропускаются. При dry_run=true возвращаются разобранные
+// @Description строки, ошибки и похожие уже записанные операции без записи. Иначе все операции создаются в одной
+// @Description транзакции; если хотя бы одна строка с ошибкой (422) или похожа на существующую операцию без
+// @Description allow_duplicates=true (409), не создаётся ничего
+// @Security Bearer
+// @Tags import
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path int true "Wallet ID"
+// @Param file formData file true "Файл выписки"
+// @Param format formData string false "csv | ofx | qfx | qif"
+// @Param mapping formData string false "Сопоставление колонок CSV, JSON models.CSVMapping"
+// @Param date_format formData string false "Формат дат QIF (Go), по умолчанию 1/2/2006"
+// @Param default_category_id formData int false "Категория для операций без категории (OFX/QIF)"
+// @Param dry_run query bool false "Только разобрать файл"
+// @Param allow_duplicates query bool false "Записать строки, похожие на существующие операции"
+// @Param Idempotency-Key header string false "Ключ идемпотентности: повтор с тем же ключом вернёт первый ответ"
+// @Success 200 {object} models.ImportResult "Dry run"
+// @Success 201 {object} models.ImportResult "Imported"
+// @Failure 400 {object} map[string]string "Invalid file or mapping"
+// @Failure 404 {object} map[string]string "Wallet not found"
+// @Failure 409 {object} models.ImportResult "Rows look like existing movements"
+// @Failure 422 {object} models.ImportResult "Statement has invalid rows"
+// @Failure 422 {object} map[string]string "Ключ уже использован с другим запросом"
+// @Router /api/wallets/{id}/import [post]
+func (h *Handler) importStatement(c *gin.Context) {
+	userId, err := h.getUserId(c)
+	if err != nil {
+		return
+	}
+
+	walletId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		h.newErrorResponse(c, http.StatusBadRequest, err, "invalid wallet id")
+		return
+	}
+
+	var opts models.ImportOptions
+	if err := c.ShouldBindQuery(&opts); err != nil {
+		h.newErrorResponse(c, http.StatusBadRequest, err, "invalid import options")
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportSize)
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		h.newErrorResponse(c, http.StatusBadRequest, err, "file is required (max 10 MB)")
+		return
+	}
+
+	var input models.StatementImportInput
+	if err := c.ShouldBind(&input); err != nil {
+		h.newErrorResponse(c, http.StatusBadRequest, err, "invalid import parameters")
+		return
+	}
+	if input.Format == "" {
+		input.Format = importer.DetectFormat(fileHeader.Filename)
+	}
+
+	var mapping models.CSVMapping
+	if input.Format == "csv" {
+		if err := json.Unmarshal([]byte(c.PostForm("mapping")), &mapping); err != nil {
+			h.newErrorResponse(c, http.StatusBadRequest, err, "invalid mapping")
+			return
+		}
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		h.newErrorResponse(c, http.StatusBadRequest, err, "failed to read file")
+		return
+	}
+	defer file.Close()
+
+	// запись большой выписки может занять больше обычных 5 секунд
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	var result models.ImportResult
+	if input.Format == "csv" {
+		result, err = h.services.Import.ImportCSV(ctx, userId, walletId, file, mapping, opts)
+	} else {
+		result, err = h.services.Import.ImportStatement(ctx, userId, walletId, file, input, opts)
+	}
+	if err != nil {
+		h.importError(c, result, err)
+		return
+	}
+
+	status := http.StatusCreated
+	if opts.DryRun {
+		status = http.StatusOK
+	}
+	c.JSON(status, result)
+}
+
+func (h *Handler) importError(c *gin.Context, result models.ImportResult, err error) {
+	switch {
+	case errors.Is(err, service.ErrImportRejected):
+		h.logger.Warn("import rejected", slog.Int("invalid_rows", len(result.Errors)))
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, result)
+	case errors.Is(err, service.ErrDuplicateMovement):
+		h.logger.Warn("import rejected", slog.String("error", err.Error()))
+		c.AbortWithStatusJSON(http.StatusConflict, result)
+	case errors.Is(err, repository.ErrRecordNotFound):
+		h.newErrorResponse(c, http.StatusNotFound, err, "wallet not found")
+	case errors.Is(err, repository.ErrDuplicate):
+		h.newErrorResponse(c, http.StatusConflict, err, "statement is being imported concurrently, retry")
+	case errors.Is(err, service.ErrInvalidImport), errors.Is(err, importer.ErrInvalidFile),
+		errors.Is(err, importer.ErrInvalidMapping), errors.Is(err, currency.ErrInvalidAmount):
+		h.newErrorResponse(c, http.StatusBadRequest, err, err.Error())
+	default:
+		h.newErrorResponse(c, http.StatusInternalServerError, err, "error while importing statement")
+	}
+}
@@ -1,19 +1,38 @@
 package handler
 
 import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"io"
 	"log/slog"
+	"mime"
 	"net/http"
 	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/goonsorrow/finance-tracker-api/internal/audit"
+	"github.com/goonsorrow/finance-tracker-api/internal/models"
+	"github.com/goonsorrow/finance-tracker-api/internal/service"
 )
 
 const (
-	userCtx             = "userId"
-	autorizathionHeader = "Authorization"
+	userCtx                 = "userId"
+	requestIDCtx            = "requestId"
+	autorizathionHeader     = "Authorization"
+	requestIDHeader         = "X-Request-ID"
+	maxRequestIDLength      = 64
+	idempotencyHeader       = "Idempotency-Key"
+	replayedHeader          = "Idempotent-Replayed"
+	maxIdempotencyKeyLength = 255
+	// тело запроса с ключом читается целиком ради хеша. Это предел для JSON-запросов,
+	// маршруты с загрузкой файлов передают в idempotent свой
+	maxIdempotentBodySize = 1 << 20
 )
 
 // @SecurityDefinitions.apikey Bearer
@@ -72,6 +91,169 @@ func (h *Handler) getUserId(c *gin.Context) (int, error) {
 
 }
 
+// RequestMetaMiddleware присваивает запросу ID (или берёт присланный клиентом) и кладёт его
+// вместе с IP клиента в контекст запроса, откуда их берёт журнал изменений
+func (h *Handler) RequestMetaMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestId := c.GetHeader(requestIDHeader)
+		if !validRequestID(requestId) {
+			requestId = newRequestID()
+		}
+		c.Header(requestIDHeader, requestId)
+		c.Set(requestIDCtx, requestId)
+
+		meta := audit.Meta{RequestID: requestId, IP: c.ClientIP()}
+		c.Request = c.Request.WithContext(audit.WithMeta(c.Request.Context(), meta))
+		c.Next()
+	}
+}
+
+// validRequestID пропускает только короткие ID из латиницы, цифр, '-' и '_'
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, r := range id {
+		ok := r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_'
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestID() string {
+	buf := make([]byte, 8)
+	_, _ = rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
+
+// idempotent отдаёт сохранённый ответ, если запрос с тем же Idempotency-Key уже выполнялся,
+// и 422, если ключ пришёл с другим запросом. Без заголовка запрос проходит как обычно.
+// Ответы 5xx не сохраняются: повтор выполнит запрос заново. Тело больше maxBodySize
+// отклоняется с 413 ещё до обработчика
+func (h *Handler) idempotent(maxBodySize int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		h.handleIdempotent(c, maxBodySize)
+	}
+}
+
+func (h *Handler) handleIdempotent(c *gin.Context, maxBodySize int64) {
+	key := c.GetHeader(idempotencyHeader)
+	if key == "" {
+		c.Next()
+		return
+	}
+	if len(key) > maxIdempotencyKeyLength {
+		h.newErrorResponse(c, http.StatusBadRequest, errors.New("idempotency key too long"),
+			fmt.Sprintf("%s must be at most %d characters", idempotencyHeader, maxIdempotencyKeyLength))
+		return
+	}
+
+	userId, err := h.getUserId(c)
+	if err != nil {
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxBodySize))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			h.newErrorResponse(c, http.StatusRequestEntityTooLarge, err, "request body is too large")
+			return
+		}
+		h.newErrorResponse(c, http.StatusBadRequest, err, "failed to read request body")
+		return
+	}
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	hash := requestHash(c.Request, body)
+	record, err := h.services.Idempotency.Begin(ctx, userId, key, hash)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrIdempotencyKeyReused):
+			h.newErrorResponse(c, http.StatusUnprocessableEntity, err, err.Error())
+		case errors.Is(err, service.ErrIdempotencyInProgress):
+			h.newErrorResponse(c, http.StatusConflict, err, err.Error())
+		default:
+			h.newErrorResponse(c, http.StatusInternalServerError, err, "failed to check idempotency key")
+		}
+		return
+	}
+	if record != nil {
+		c.Header(replayedHeader, "true")
+		c.Data(record.StatusCode, record.ContentType, record.Body)
+		c.Abort()
+		return
+	}
+
+	// ответ сохраняется и после отключения клиента: именно он придёт за ним повтором
+	saveCtx, saveCancel := context.WithTimeout(context.WithoutCancel(c.Request.Context()), 5*time.Second)
+	defer saveCancel()
+
+	completed := false
+	defer func() {
+		// при панике обработчика ключ тоже освобождается
+		if completed {
+			return
+		}
+		if err := h.services.Idempotency.Abort(saveCtx, userId, key); err != nil {
+			h.logger.Error("failed to release idempotency key", slog.String("error", err.Error()))
+		}
+	}()
+
+	recorder := &responseRecorder{ResponseWriter: c.Writer}
+	c.Writer = recorder
+	c.Next()
+
+	if recorder.Status() >= http.StatusInternalServerError {
+		return
+	}
+	err = h.services.Idempotency.Complete(saveCtx, userId, key, hash, models.IdempotencyRecord{
+		StatusCode:  recorder.Status(),
+		ContentType: recorder.Header().Get("Content-Type"),
+		Body:        recorder.body.Bytes(),
+	})
+	if err != nil {
+		h.logger.Error("failed to save idempotent response", slog.String("error", err.Error()))
+		return
+	}
+	completed = true
+}
+
+// requestHash отличает запросы по методу, адресу и телу. Граница multipart генерируется
+// клиентом заново при каждой отправке, поэтому из тела она вырезается
+func requestHash(r *http.Request, body []byte) string {
+	if mediaType, params, err := mime.ParseMediaType(r.Header.Get("Content-Type")); err == nil &&
+		strings.HasPrefix(mediaType, "multipart/") && params["boundary"] != "" {
+		body = bytes.ReplaceAll(body, []byte(params["boundary"]), nil)
+	}
+
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%s %s?%s\n", r.Method, r.URL.Path, r.URL.RawQuery)
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// responseRecorder копирует тело ответа, чтобы его можно было отдать повторно
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
 func (h *Handler) LoggingMiddleware() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		start := time.Now()
@@ -97,6 +279,7 @@ func (h *Handler) LoggingMiddleware() gin.HandlerFunc {
 			slog.Int("status", status),
 			slog.Duration("latency", latency),
 			slog.String("clientIP", clientIP),
+			slog.String("request_id", ctx.GetString(requestIDCtx)),
 			userAttr,
 		}
 
@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestIdempotentBodyLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := &Handler{logger: slog.New(slog.NewTextHandler(io.Discard, nil))}
+
+	tests := []struct {
+		name   string
+		key    string
+		size   int
+		status int
+	}{
+		{"without key the limit does not apply", "", maxIdempotentBodySize + 1, http.StatusCreated},
+		{"body over the limit", "key-1", maxIdempotentBodySize + 1, http.StatusRequestEntityTooLarge},
+	}
+	for _, tt := range tests {
+		router := gin.New()
+		router.POST("/", func(c *gin.Context) { c.Set(userCtx, 1) }, h.idempotent(maxIdempotentBodySize),
+			func(c *gin.Context) { c.Status(http.StatusCreated) })
+
+		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(make([]byte, tt.size)))
+		if tt.key != "" {
+			req.Header.Set(idempotencyHeader, tt.key)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != tt.status {
+			t.Errorf("%s: status = %d, want %d", tt.name, w.Code, tt.status)
+		}
+	}
+}
@@ -2,17 +2,31 @@ package handler
 
 import (
 	"context"
+	"errors"
+	"log/slog"
 	"net/http"
 	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/goonsorrow/finance-tracker-api/internal/currency"
 	"github.com/goonsorrow/finance-tracker-api/internal/models"
+	"github.com/goonsorrow/finance-tracker-api/internal/repository"
+	"github.com/goonsorrow/finance-tracker-api/internal/service"
 )
 
 type getAllMovementsResponse struct {
 	Wallet models.Wallet     `json:"wallet"`
 	Data   []models.Movement `json:"movements"`
+	Total  int               `json:"total"`
+	Limit  int               `json:"limit"`
+	Offset int               `json:"offset"`
+}
+
+// Похожие операции того же типа и суммы рядом по дате
+type duplicateMovementResponse struct {
+	Error      string            `json:"error"`
+	Candidates []models.Movement `json:"candidates"`
 }
 
 type getMovementByIdResponse struct {
@@ -22,13 +36,19 @@ type getMovementByIdResponse struct {
 
 // @Summary Создать транзакцию
 // @Description Пополнение (+) или списание (-) с кошелька
+// @Description Если в кошельке есть операция того же типа и суммы рядом по дате с похожим описанием,
+// @Description возвращается 409 со списком похожих; allow_duplicate=true создаёт операцию всё равно
+// @Description splits разбивает сумму по категориям: не меньше двух строк, их сумма равна amount
 // @Security Bearer
 // @Tags movements
 // @Accept json
 // @Produce json
 // @Param wallet_id path int true "Wallet ID"
 // @Param input body models.CreateMovementInput true "Сумма + Тип"
+// @Param Idempotency-Key header string false "Ключ идемпотентности: повтор с тем же ключом вернёт первый ответ"
 // @Success 200 {object} map[string]int "Movement ID"
+// @Failure 409 {object} handler.duplicateMovementResponse "Похожая операция уже есть"
+// @Failure 422 {object} map[string]string "Ключ уже использован с другим запросом"
 // @Router /api/wallets/{wallet_id}/movements/ [post]
 func (h *Handler) createMovement(c *gin.Context) {
 	userId, err := h.getUserId(c)
@@ -42,6 +62,11 @@ func (h *Handler) createMovement(c *gin.Context) {
 		return
 	}
 
+	if err := input.Validate(); err != nil {
+		h.newErrorResponse(c, http.StatusBadRequest, err, err.Error())
+		return
+	}
+
 	walletId, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
 		h.newErrorResponse(c, http.StatusBadRequest, err, "invalid wallet id")
@@ -53,6 +78,19 @@ func (h *Handler) createMovement(c *gin.Context) {
 
 	id, err := h.services.Movement.Create(ctx, userId, walletId, input)
 	if err != nil {
+		var duplicate *service.DuplicateError
+		if errors.As(err, &duplicate) {
+			h.logger.Warn("possible duplicate movement", slog.Int("candidates", len(duplicate.Candidates)))
+			c.AbortWithStatusJSON(http.StatusConflict, duplicateMovementResponse{
+				Error:      "similar movement already exists, pass allow_duplicate=true to create it anyway",
+				Candidates: duplicate.Candidates,
+			})
+			return
+		}
+		if errors.Is(err, currency.ErrInvalidAmount) || errors.Is(err, service.ErrInvalidSplits) || errors.Is(err, service.ErrInvalidTags) {
+			h.newErrorResponse(c, http.StatusBadRequest, err, err.Error())
+			return
+		}
 		h.newErrorResponse(c, http.StatusInternalServerError, err, "error while creating movement")
 		return
 	}
@@ -63,12 +101,24 @@ func (h *Handler) createMovement(c *gin.Context) {
 }
 
 // @Summary Список транзакций кошелька
-// @Description Получить все операции по кошельку
+// @Description Получить операции по кошельку с фильтрацией, сортировкой и пагинацией
 // @Security Bearer
 // @Tags movements
 // @Produce json
 // @Param wallet_id path int true "Wallet ID"
+// @Param type query string false "income | expense | initial | transfer"
+// @Param category_id query int false "Category ID"
+// @Param tag_id query int false "Tag ID"
+// @Param from query string false "Дата начала (YYYY-MM-DD)"
+// @Param to query string false "Дата конца включительно (YYYY-MM-DD)"
+// @Param min_amount query string false "Минимальная сумма в валюте кошелька"
+// @Param max_amount query string false "Максимальная сумма в валюте кошелька"
+// @Param sort_by query string false "date | amount | created_at"
+// @Param sort_order query string false "asc | desc"
+// @Param limit query int false "Размер страницы (1-100, по умолчанию 50)"
+// @Param offset query int false "Смещение"
 // @Success 200 {object} handler.getAllMovementsResponse
+// @Failure 400 {object} map[string]string "Invalid filter"
 // @Router /api/wallets/{wallet_id}/movements/ [get]
 func (h *Handler) getAllMovements(c *gin.Context) {
 	userId, err := h.getUserId(c)
@@ -82,6 +132,17 @@ func (h *Handler) getAllMovements(c *gin.Context) {
 		return
 	}
 
+	var filter models.MovementFilterInput
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		h.newErrorResponse(c, http.StatusBadRequest, err, "invalid filter")
+		return
+	}
+
+	if err := filter.Validate(); err != nil {
+		h.newErrorResponse(c, http.StatusBadRequest, err, err.Error())
+		return
+	}
+
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
 	defer cancel()
 
@@ -91,18 +152,142 @@ func (h *Handler) getAllMovements(c *gin.Context) {
 		return
 	}
 
-	items, err := h.services.Movement.GetAll(ctx, userId, walletId)
+	page, err := h.services.Movement.GetAll(ctx, userId, walletId, filter)
 	if err != nil {
+		if errors.Is(err, currency.ErrInvalidAmount) {
+			h.newErrorResponse(c, http.StatusBadRequest, err, err.Error())
+			return
+		}
 		h.newErrorResponse(c, http.StatusInternalServerError, err, "error while getting movements")
 		return
 	}
 
 	c.JSON(http.StatusOK, getAllMovementsResponse{
 		Wallet: wallet,
-		Data:   items,
+		Data:   page.Items,
+		Total:  page.Total,
+		Limit:  page.Limit,
+		Offset: page.Offset,
 	})
 }
 
+// @Summary Лента транзакций по всем кошелькам
+// @Description Операции пользователя по всем кошелькам с курсорной (keyset) пагинацией
+// @Security Bearer
+// @Tags movements
+// @Produce json
+// @Param wallet_id query int false "Wallet ID"
+// @Param type query string false "income | expense | initial | transfer"
+// @Param category_id query int false "Category ID"
+// @Param tag_id query int false "Tag ID"
+// @Param from query string false "Дата начала (YYYY-MM-DD)"
+// @Param to query string false "Дата конца включительно (YYYY-MM-DD)"
+// @Param min_amount query string false "Минимальная сумма, десятичная строка"
+// @Param max_amount query string false "Максимальная сумма, десятичная строка"
+// @Param currency query string false "Валюта сумм в min_amount/max_amount; без wallet_id обязательна и оставляет только кошельки в этой валюте"
+// @Param sort_order query string false "desc (по умолчанию) | asc"
+// @Param cursor query string false "next_cursor из предыдущего ответа"
+// @Param limit query int false "Размер страницы (1-100, по умолчанию 50)"
+// @Success 200 {object} models.MovementCursorPage
+// @Failure 400 {object} map[string]string "Invalid filter or cursor"
+// @Router /api/movements/ [get]
+func (h *Handler) getUserMovements(c *gin.Context) {
+	userId, err := h.getUserId(c)
+	if err != nil {
+		return
+	}
+
+	var input models.MovementCursorInput
+	if err := c.ShouldBindQuery(&input); err != nil {
+		h.newErrorResponse(c, http.StatusBadRequest, err, "invalid filter")
+		return
+	}
+
+	if err := input.Validate(); err != nil {
+		h.newErrorResponse(c, http.StatusBadRequest, err, err.Error())
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	page, err := h.services.Movement.List(ctx, userId, input)
+	if err != nil {
+		if errors.Is(err, models.ErrInvalidCursor) {
+			h.newErrorResponse(c, http.StatusBadRequest, err, "invalid cursor")
+			return
+		}
+		if errors.Is(err, currency.ErrInvalidAmount) {
+			h.newErrorResponse(c, http.StatusBadRequest, err, err.Error())
+			return
+		}
+		h.newErrorResponse(c, http.StatusInternalServerError, err, "error while getting movements")
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// @Summary Поиск по описаниям операций
+// @Description Полнотекстовый поиск по всем кошелькам: каждое слово запроса должно совпасть с началом слова
+// @Description в описании, регистр не важен. Фильтры те же, что у ленты. В snippet совпадения выделены <b></b>,
+// @Description остальной текст HTML-экранирован
+// @Security Bearer
+// @Tags movements
+// @Produce json
+// @Param q query string true "Поисковый запрос"
+// @Param wallet_id query int false "Wallet ID"
+// @Param type query string false "income | expense | initial | transfer"
+// @Param category_id query int false "Category ID"
+// @Param tag_id query int false "Tag ID"
+// @Param from query string false "Дата начала (YYYY-MM-DD)"
+// @Param to query string false "Дата конца включительно (YYYY-MM-DD)"
+// @Param min_amount query string false "Минимальная сумма, десятичная строка"
+// @Param max_amount query string false "Максимальная сумма, десятичная строка"
+// @Param currency query string false "Валюта сумм в min_amount/max_amount; без wallet_id обязательна и оставляет только кошельки в этой валюте"
+// @Param limit query int false "Размер страницы (1-100, по умолчанию 50)"
+// @Param offset query int false "Смещение"
+// @Success 200 {object} models.MovementSearchPage
+// @Failure 400 {object} map[string]string "Invalid query or filter"
+// @Failure 404 {object} map[string]string "Wallet not found"
+// @Router /api/movements/search [get]
+func (h *Handler) searchMovements(c *gin.Context) {
+	userId, err := h.getUserId(c)
+	if err != nil {
+		return
+	}
+
+	var input models.MovementSearchInput
+	if err := c.ShouldBindQuery(&input); err != nil {
+		h.newErrorResponse(c, http.StatusBadRequest, err, "invalid search query")
+		return
+	}
+
+	if err := input.Validate(); err != nil {
+		h.newErrorResponse(c, http.StatusBadRequest, err, err.Error())
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	page, err := h.services.Movement.Search(ctx, userId, input)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			h.newErrorResponse(c, http.StatusNotFound, err, "wallet not found")
+			return
+		}
+		if errors.Is(err, currency.ErrInvalidAmount) {
+			h.newErrorResponse(c, http.StatusBadRequest, err, err.Error())
+			return
+		}
+		h.newErrorResponse(c, http.StatusInternalServerError, err, "error while searching movements")
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
 // @Summary Транзакция по ID
 // @Security Bearer
 // @Tags movements
@@ -110,6 +295,7 @@ func (h *Handler) getAllMovements(c *gin.Context) {
 // @Param wallet_id path int true "Wallet ID"
 // @Param trId path int true "Movement ID"
 // @Success 200 {object} handler.getMovementByIdResponse
+// @Header 200 {string} ETag "Версия транзакции для If-Match"
 // @Router /api/wallets/{wallet_id}/movements/{trId} [get]
 func (h *Handler) getMovementByID(c *gin.Context) {
 	userId, err := h.getUserId(c)
@@ -144,6 +330,7 @@ func (h *Handler) getMovementByID(c *gin.Context) {
 		return
 	}
 
+	setETag(c, movement.Version)
 	c.JSON(http.StatusOK, getMovementByIdResponse{
 		Wallet: wallet,
 		Data:   movement,
@@ -158,7 +345,11 @@ func (h *Handler) getMovementByID(c *gin.Context) {
 // @Param wallet_id path int true "Wallet ID"
 // @Param trId path int true "Movement ID"
 // @Param input body models.UpdateMovementInput true "Changes"
+// @Param If-Match header string false "ETag из GET транзакции"
 // @Success 200 {object} handler.statusResponse
+// @Failure 400 {object} map[string]string "Invalid amount or splits"
+// @Failure 409 {object} map[string]string "Movement belongs to a transfer"
+// @Failure 412 {object} map[string]string "Транзакция изменена другим запросом"
 // @Router /api/wallets/{wallet_id}/movements/{trId} [put]
 func (h *Handler) updateMovementByID(c *gin.Context) {
 	userId, err := h.getUserId(c)
@@ -178,6 +369,12 @@ func (h *Handler) updateMovementByID(c *gin.Context) {
 		return
 	}
 
+	version, err := ifMatchVersion(c)
+	if err != nil {
+		h.newErrorResponse(c, ifMatchStatus(err), err, err.Error())
+		return
+	}
+
 	var input models.UpdateMovementInput
 	if err := c.BindJSON(&input); err != nil {
 		h.newErrorResponse(c, http.StatusBadRequest, err, "invalid input data")
@@ -192,8 +389,20 @@ func (h *Handler) updateMovementByID(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
 	defer cancel()
 
-	err = h.services.Movement.Update(ctx, userId, walletId, movementId, input)
+	err = h.services.Movement.Update(ctx, userId, walletId, movementId, input, version)
 	if err != nil {
+		if errors.Is(err, repository.ErrVersionConflict) {
+			h.newErrorResponse(c, http.StatusPreconditionFailed, err, "movement was changed, fetch it again")
+			return
+		}
+		if errors.Is(err, service.ErrTransferMovement) {
+			h.newErrorResponse(c, http.StatusConflict, err, err.Error())
+			return
+		}
+		if errors.Is(err, currency.ErrInvalidAmount) || errors.Is(err, service.ErrInvalidSplits) || errors.Is(err, service.ErrInvalidTags) {
+			h.newErrorResponse(c, http.StatusBadRequest, err, err.Error())
+			return
+		}
 		h.newErrorResponse(c, http.StatusInternalServerError, err, "error while updating todo item")
 		return
 	}
@@ -205,12 +414,17 @@ func (h *Handler) updateMovementByID(c *gin.Context) {
 }
 
 // @Summary Удалить транзакцию
+// @Description Переносит транзакцию в корзину, её сумма снимается с баланса кошелька
 // @Security Bearer
 // @Tags movements
 // @Produce json
 // @Param wallet_id path int true "Wallet ID"
 // @Param trId path int true "Movement ID"
+// @Param If-Match header string false "ETag из GET транзакции"
 // @Success 200 {object} handler.statusResponse
+// @Failure 404 {object} map[string]string "Wallet or movement not found"
+// @Failure 409 {object} map[string]string "Movement belongs to a transfer"
+// @Failure 412 {object} map[string]string "Транзакция изменена другим запросом"
 // @Router /api/wallets/{wallet_id}/movements/{trId} [delete]
 func (h *Handler) deleteMovementByID(c *gin.Context) {
 	userId, err := h.getUserId(c)
@@ -230,11 +444,29 @@ func (h *Handler) deleteMovementByID(c *gin.Context) {
 		return
 	}
 
+	version, err := ifMatchVersion(c)
+	if err != nil {
+		h.newErrorResponse(c, ifMatchStatus(err), err, err.Error())
+		return
+	}
+
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
 	defer cancel()
 
-	err = h.services.Movement.Delete(ctx, userId, walletId, movementId)
+	err = h.services.Movement.Delete(ctx, userId, walletId, movementId, version)
 	if err != nil {
+		if errors.Is(err, repository.ErrVersionConflict) {
+			h.newErrorResponse(c, http.StatusPreconditionFailed, err, "movement was changed, fetch it again")
+			return
+		}
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			h.newErrorResponse(c, http.StatusNotFound, err, "movement not found")
+			return
+		}
+		if errors.Is(err, service.ErrTransferMovement) {
+			h.newErrorResponse(c, http.StatusConflict, err, err.Error())
+			return
+		}
 		h.newErrorResponse(c, http.StatusInternalServerError, err, "error while deleting movement")
 		return
 	}
@@ -1,10 +1,36 @@
 package handler
 
 import (
+	"context"
+	"net/http"
+	"time"
+
 	"github.com/gin-gonic/gin"
 )
 
-func (h Handler) getProfile(c *gin.Context) {
-	// ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
-	// defer cancel()
+// @Summary Профиль пользователя
+// @Description Пользователь, суммы кошельков по валютам и общий итог в базовой валюте.
+// @Description rate_date — дата курса, по которому сделан пересчёт
+// @Security Bearer
+// @Tags auth
+// @Produce json
+// @Success 200 {object} models.Profile
+// @Failure 401 {object} map[string]string "Not Authorized"
+// @Router /auth/me [get]
+func (h *Handler) getProfile(c *gin.Context) {
+	userId, err := h.getUserId(c)
+	if err != nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	profile, err := h.services.Profile.GetMe(ctx, userId)
+	if err != nil {
+		h.newErrorResponse(c, http.StatusInternalServerError, err, "failed to get profile")
+		return
+	}
+
+	c.JSON(http.StatusOK, profile)
 }
@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/goonsorrow/finance-tracker-api/internal/currency"
+	"github.com/goonsorrow/finance-tracker-api/internal/models"
+)
+
+// @Summary Курс валют на дату
+// @Description Курс, действующий на дату: последний известный не позже неё. Ищется напрямую, через обратную пару или кросс-курсом
+// @Security Bearer
+// @Tags rates
+// @Produce json
+// @Param base query string true "Базовая валюта" example(USD)
+// @Param quote query string true "Котируемая валюта" example(RUB)
+// @Param date query string false "Дата (YYYY-MM-DD), по умолчанию сегодня"
+// @Success 200 {object} currency.Rate
+// @Failure 400 {object} map[string]string "Invalid pair"
+// @Failure 404 {object} map[string]string "Rate not found"
+// @Router /api/rates [get]
+func (h *Handler) getRate(c *gin.Context) {
+	var input models.RateLookupInput
+	if err := c.ShouldBindQuery(&input); err != nil {
+		h.newErrorResponse(c, http.StatusBadRequest, err, "invalid input")
+		return
+	}
+
+	if err := input.Validate(); err != nil {
+		h.newErrorResponse(c, http.StatusBadRequest, err, err.Error())
+		return
+	}
+
+	date := time.Now().UTC()
+	if input.Date != nil {
+		date = *input.Date
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	rate, err := h.services.Rates.GetRate(ctx, input.Base, input.Quote, date)
+	if err != nil {
+		if errors.Is(err, currency.ErrRateNotFound) {
+			h.newErrorResponse(c, http.StatusNotFound, err, "exchange rate not found")
+			return
+		}
+		h.newErrorResponse(c, http.StatusInternalServerError, err, "failed to get exchange rate")
+		return
+	}
+
+	c.JSON(http.StatusOK, rate)
+}
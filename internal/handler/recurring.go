@@ -0,0 +1,305 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/goonsorrow/finance-tracker-api/internal/currency"
+	"github.com/goonsorrow/finance-tracker-api/internal/models"
+	"github.com/goonsorrow/finance-tracker-api/internal/repository"
+	"github.com/goonsorrow/finance-tracker-api/internal/service"
+)
+
+type getAllRecurringResponse struct {
+	Data []models.RecurringMovement `json:"recurring"`
+}
+
+type recurringPreviewResponse struct {
+	Data []models.RecurringOccurrence `json:"occurrences"`
+}
+
+type skipOccurrenceResponse struct {
+	Skipped time.Time `json:"skipped"`
+}
+
+// @Summary Создать повторяющуюся операцию
+// @Description Шаблон операции по расписанию: daily/weekly/monthly/yearly с шагом interval.
+// @Description Наступившие повторения создаются фоновым планировщиком, в том числе прошедшие с start_date
+// @Security Bearer
+// @Tags recurring
+// @Accept json
+// @Produce json
+// @Param input body models.CreateRecurringInput true "Кошелёк + Операция + Расписание"
+// @Param Idempotency-Key header string false "Ключ идемпотентности: повтор с тем же ключом вернёт первый ответ"
+// @Success 201 {object} map[string]int "Recurring ID"
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 404 {object} map[string]string "Wallet not found"
+// @Failure 422 {object} map[string]string "Ключ уже использован с другим запросом"
+// @Router /api/recurring/ [post]
+func (h *Handler) createRecurring(c *gin.Context) {
+	userId, err := h.getUserId(c)
+	if err != nil {
+		return
+	}
+
+	var input models.CreateRecurringInput
+	if err := c.BindJSON(&input); err != nil {
+		h.newErrorResponse(c, http.StatusBadRequest, err, "invalid input data")
+		return
+	}
+
+	if err := input.Validate(); err != nil {
+		h.newErrorResponse(c, http.StatusBadRequest, err, err.Error())
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	id, err := h.services.Recurring.Create(ctx, userId, input)
+	if err != nil {
+		h.recurringError(c, err, "error while creating recurring movement")
+		return
+	}
+
+	c.JSON(http.StatusCreated, map[string]interface{}{
+		"id": id,
+	})
+}
+
+// @Summary Список повторяющихся операций
+// @Security Bearer
+// @Tags recurring
+// @Produce json
+// @Success 200 {object} handler.getAllRecurringResponse
+// @Router /api/recurring/ [get]
+func (h *Handler) getAllRecurring(c *gin.Context) {
+	userId, err := h.getUserId(c)
+	if err != nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	items, err := h.services.Recurring.GetAll(ctx, userId)
+	if err != nil {
+		h.newErrorResponse(c, http.StatusInternalServerError, err, "failed to get recurring movements")
+		return
+	}
+	if items == nil {
+		items = []models.RecurringMovement{}
+	}
+
+	c.JSON(http.StatusOK, getAllRecurringResponse{
+		Data: items,
+	})
+}
+
+// @Summary Повторяющаяся операция по ID
+// @Security Bearer
+// @Tags recurring
+// @Produce json
+// @Param id path int true "Recurring ID"
+// @Success 200 {object} models.RecurringMovement
+// @Failure 404 {object} map[string]string "Not found"
+// @Router /api/recurring/{id} [get]
+func (h *Handler) getRecurringByID(c *gin.Context) {
+	userId, recurringId, ok := h.recurringParams(c)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	item, err := h.services.Recurring.GetById(ctx, userId, recurringId)
+	if err != nil {
+		h.recurringError(c, err, "error while getting recurring movement")
+		return
+	}
+
+	c.JSON(http.StatusOK, item)
+}
+
+// @Summary Удалить повторяющуюся операцию
+// @Description Уже созданные операции остаются
+// @Security Bearer
+// @Tags recurring
+// @Produce json
+// @Param id path int true "Recurring ID"
+// @Success 200 {object} handler.statusResponse
+// @Failure 404 {object} map[string]string "Not found"
+// @Router /api/recurring/{id} [delete]
+func (h *Handler) deleteRecurringByID(c *gin.Context) {
+	userId, recurringId, ok := h.recurringParams(c)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	if err := h.services.Recurring.Delete(ctx, userId, recurringId); err != nil {
+		h.recurringError(c, err, "error while deleting recurring movement")
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{Status: "ok"})
+}
+
+// @Summary Поставить на паузу
+// @Security Bearer
+// @Tags recurring
+// @Produce json
+// @Param id path int true "Recurring ID"
+// @Success 200 {object} handler.statusResponse
+// @Failure 404 {object} map[string]string "Not found"
+// @Router /api/recurring/{id}/pause [post]
+func (h *Handler) pauseRecurring(c *gin.Context) {
+	userId, recurringId, ok := h.recurringParams(c)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	if err := h.services.Recurring.Pause(ctx, userId, recurringId); err != nil {
+		h.recurringError(c, err, "error while pausing recurring movement")
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{Status: "ok"})
+}
+
+// @Summary Снять с паузы
+// @Description Повторения, выпавшие на время паузы, не создаются
+// @Security Bearer
+// @Tags recurring
+// @Produce json
+// @Param id path int true "Recurring ID"
+// @Success 200 {object} handler.statusResponse
+// @Failure 404 {object} map[string]string "Not found"
+// @Router /api/recurring/{id}/resume [post]
+func (h *Handler) resumeRecurring(c *gin.Context) {
+	userId, recurringId, ok := h.recurringParams(c)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	if err := h.services.Recurring.Resume(ctx, userId, recurringId); err != nil {
+		h.recurringError(c, err, "error while resuming recurring movement")
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{Status: "ok"})
+}
+
+// @Summary Пропустить повторение
+// @Description Без даты пропускается ближайшее повторение
+// @Security Bearer
+// @Tags recurring
+// @Accept json
+// @Produce json
+// @Param id path int true "Recurring ID"
+// @Param input body models.SkipOccurrenceInput false "Дата повторения"
+// @Success 200 {object} handler.skipOccurrenceResponse
+// @Failure 400 {object} map[string]string "Date is not an occurrence"
+// @Failure 404 {object} map[string]string "Not found"
+// @Failure 409 {object} map[string]string "Occurrence already created or skipped"
+// @Router /api/recurring/{id}/skip [post]
+func (h *Handler) skipRecurringOccurrence(c *gin.Context) {
+	userId, recurringId, ok := h.recurringParams(c)
+	if !ok {
+		return
+	}
+
+	var input models.SkipOccurrenceInput
+	if c.Request.ContentLength > 0 {
+		if err := c.BindJSON(&input); err != nil {
+			h.newErrorResponse(c, http.StatusBadRequest, err, "invalid input data")
+			return
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	date, err := h.services.Recurring.Skip(ctx, userId, recurringId, input)
+	if err != nil {
+		h.recurringError(c, err, "error while skipping occurrence")
+		return
+	}
+
+	c.JSON(http.StatusOK, skipOccurrenceResponse{Skipped: date})
+}
+
+// @Summary Ближайшие повторения
+// @Security Bearer
+// @Tags recurring
+// @Produce json
+// @Param id path int true "Recurring ID"
+// @Param count query int false "Сколько повторений (1-100, по умолчанию 10)"
+// @Success 200 {object} handler.recurringPreviewResponse
+// @Failure 404 {object} map[string]string "Not found"
+// @Router /api/recurring/{id}/preview [get]
+func (h *Handler) previewRecurring(c *gin.Context) {
+	userId, recurringId, ok := h.recurringParams(c)
+	if !ok {
+		return
+	}
+
+	var input models.RecurringPreviewInput
+	if err := c.ShouldBindQuery(&input); err != nil {
+		h.newErrorResponse(c, http.StatusBadRequest, err, "invalid count")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	occurrences, err := h.services.Recurring.Preview(ctx, userId, recurringId, input)
+	if err != nil {
+		h.recurringError(c, err, "error while building preview")
+		return
+	}
+
+	c.JSON(http.StatusOK, recurringPreviewResponse{
+		Data: occurrences,
+	})
+}
+
+func (h *Handler) recurringParams(c *gin.Context) (int, int, bool) {
+	userId, err := h.getUserId(c)
+	if err != nil {
+		return 0, 0, false
+	}
+
+	recurringId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		h.newErrorResponse(c, http.StatusBadRequest, err, "invalid recurring id")
+		return 0, 0, false
+	}
+	return userId, recurringId, true
+}
+
+func (h *Handler) recurringError(c *gin.Context, err error, msg string) {
+	switch {
+	case errors.Is(err, repository.ErrRecordNotFound):
+		h.newErrorResponse(c, http.StatusNotFound, err, "not found")
+	case errors.Is(err, service.ErrOccurrenceTaken):
+		h.newErrorResponse(c, http.StatusConflict, err, err.Error())
+	case errors.Is(err, service.ErrInvalidOccurrence), errors.Is(err, currency.ErrInvalidAmount):
+		h.newErrorResponse(c, http.StatusBadRequest, err, err.Error())
+	default:
+		h.newErrorResponse(c, http.StatusInternalServerError, err, msg)
+	}
+}
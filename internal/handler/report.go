@@ -0,0 +1,235 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/goonsorrow/finance-tracker-api/internal/models"
+	"github.com/goonsorrow/finance-tracker-api/internal/repository"
+)
+
+// @Summary Сводный отчёт
+// @Description Балансы, доходы и расходы за период по выбранным кошелькам, пересчитанные в базовую валюту.
+// @Description Переводы и начальные остатки не считаются доходами и расходами
+// @Security Bearer
+// @Tags reports
+// @Produce json
+// @Param from query string false "Дата начала (YYYY-MM-DD)"
+// @Param to query string false "Дата конца включительно (YYYY-MM-DD)"
+// @Param wallet_id query []int false "ID кошельков, можно несколько раз" collectionFormat(multi)
+// @Success 200 {object} models.SummaryReport
+// @Failure 400 {object} map[string]string "Invalid filter"
+// @Failure 404 {object} map[string]string "Wallet not found"
+// @Router /api/reports/summary [get]
+func (h *Handler) getSummaryReport(c *gin.Context) {
+	userId, err := h.getUserId(c)
+	if err != nil {
+		return
+	}
+
+	var input models.ReportFilterInput
+	if err := c.ShouldBindQuery(&input); err != nil {
+		h.newErrorResponse(c, http.StatusBadRequest, err, "invalid filter")
+		return
+	}
+
+	if err := input.Validate(); err != nil {
+		h.newErrorResponse(c, http.StatusBadRequest, err, err.Error())
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	report, err := h.services.Report.Summary(ctx, userId, input)
+	if err != nil {
+		h.reportError(c, err, "failed to build summary report")
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// @Summary Отчёт по категориям
+// @Description Суммы по категориям за период в базовой валюте и доля каждой категории
+// @Security Bearer
+// @Tags reports
+// @Produce json
+// @Param type query string false "expense (по умолчанию) | income"
+// @Param from query string false "Дата начала (YYYY-MM-DD)"
+// @Param to query string false "Дата конца включительно (YYYY-MM-DD)"
+// @Param wallet_id query []int false "ID кошельков, можно несколько раз" collectionFormat(multi)
+// @Success 200 {object} models.CategoryReport
+// @Failure 400 {object} map[string]string "Invalid filter"
+// @Failure 404 {object} map[string]string "Wallet not found"
+// @Router /api/reports/categories [get]
+func (h *Handler) getCategoryReport(c *gin.Context) {
+	userId, err := h.getUserId(c)
+	if err != nil {
+		return
+	}
+
+	var input models.CategoryReportInput
+	if err := c.ShouldBindQuery(&input); err != nil {
+		h.newErrorResponse(c, http.StatusBadRequest, err, "invalid filter")
+		return
+	}
+
+	if err := input.Validate(); err != nil {
+		h.newErrorResponse(c, http.StatusBadRequest, err, err.Error())
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	report, err := h.services.Report.Categories(ctx, userId, input)
+	if err != nil {
+		h.reportError(c, err, "failed to build category report")
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// @Summary Отчёт по меткам
+// @Description Суммы по меткам за период в базовой валюте. Операция с несколькими метками входит в каждую
+// @Security Bearer
+// @Tags reports
+// @Produce json
+// @Param type query string false "expense (по умолчанию) | income"
+// @Param from query string false "Дата начала (YYYY-MM-DD)"
+// @Param to query string false "Дата конца включительно (YYYY-MM-DD)"
+// @Param wallet_id query []int false "ID кошельков, можно несколько раз" collectionFormat(multi)
+// @Success 200 {object} models.TagReport
+// @Failure 400 {object} map[string]string "Invalid filter"
+// @Failure 404 {object} map[string]string "Wallet not found"
+// @Router /api/reports/tags [get]
+func (h *Handler) getTagReport(c *gin.Context) {
+	userId, err := h.getUserId(c)
+	if err != nil {
+		return
+	}
+
+	var input models.TagReportInput
+	if err := c.ShouldBindQuery(&input); err != nil {
+		h.newErrorResponse(c, http.StatusBadRequest, err, "invalid filter")
+		return
+	}
+
+	if err := input.Validate(); err != nil {
+		h.newErrorResponse(c, http.StatusBadRequest, err, err.Error())
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	report, err := h.services.Report.Tags(ctx, userId, input)
+	if err != nil {
+		h.reportError(c, err, "failed to build tag report")
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// @Summary Помесячный отчёт
+// @Description Доходы, расходы и изменение по месяцам в базовой валюте, по курсу на конец каждого месяца
+// @Security Bearer
+// @Tags reports
+// @Produce json
+// @Param from query string false "Дата начала (YYYY-MM-DD)"
+// @Param to query string false "Дата конца включительно (YYYY-MM-DD)"
+// @Param wallet_id query []int false "ID кошельков, можно несколько раз" collectionFormat(multi)
+// @Success 200 {object} models.MonthlyReport
+// @Failure 400 {object} map[string]string "Invalid filter"
+// @Failure 404 {object} map[string]string "Wallet not found"
+// @Router /api/reports/monthly [get]
+func (h *Handler) getMonthlyReport(c *gin.Context) {
+	userId, err := h.getUserId(c)
+	if err != nil {
+		return
+	}
+
+	var input models.ReportFilterInput
+	if err := c.ShouldBindQuery(&input); err != nil {
+		h.newErrorResponse(c, http.StatusBadRequest, err, "invalid filter")
+		return
+	}
+
+	if err := input.Validate(); err != nil {
+		h.newErrorResponse(c, http.StatusBadRequest, err, err.Error())
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	report, err := h.services.Report.Monthly(ctx, userId, input)
+	if err != nil {
+		h.reportError(c, err, "failed to build monthly report")
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// @Summary Статистика кошелька
+// @Description Баланс, доходы, расходы и число операций за период в валюте кошелька
+// @Security Bearer
+// @Tags wallets
+// @Produce json
+// @Param id path int true "Wallet ID"
+// @Param from query string false "Дата начала (YYYY-MM-DD)"
+// @Param to query string false "Дата конца включительно (YYYY-MM-DD)"
+// @Success 200 {object} models.WalletStats
+// @Failure 400 {object} map[string]string "Invalid period"
+// @Failure 404 {object} map[string]string "Wallet not found"
+// @Router /api/wallets/{id}/stats [get]
+func (h *Handler) getWalletStats(c *gin.Context) {
+	userId, err := h.getUserId(c)
+	if err != nil {
+		return
+	}
+
+	walletId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		h.newErrorResponse(c, http.StatusBadRequest, err, "invalid wallet id")
+		return
+	}
+
+	var input models.ReportPeriodInput
+	if err := c.ShouldBindQuery(&input); err != nil {
+		h.newErrorResponse(c, http.StatusBadRequest, err, "invalid period")
+		return
+	}
+
+	if err := input.Validate(); err != nil {
+		h.newErrorResponse(c, http.StatusBadRequest, err, err.Error())
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	stats, err := h.services.Report.WalletStats(ctx, userId, walletId, input)
+	if err != nil {
+		h.reportError(c, err, "failed to get wallet stats")
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
+
+func (h *Handler) reportError(c *gin.Context, err error, msg string) {
+	if errors.Is(err, repository.ErrRecordNotFound) {
+		h.newErrorResponse(c, http.StatusNotFound, err, "wallet not found")
+		return
+	}
+	h.newErrorResponse(c, http.StatusInternalServerError, err, msg)
+}
@@ -1,7 +1,11 @@
 package handler
 
 import (
+	"errors"
 	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/goonsorrow/finance-tracker-api/internal/service"
@@ -22,3 +26,42 @@ func (h *Handler) newErrorResponse(c *gin.Context, statusCode int, err error, me
 		"error": message,
 	})
 }
+
+var (
+	errInvalidIfMatch = errors.New("If-Match must hold a single entity tag")
+	// If-Match сравнивает теги строго, слабый тег не совпадает ни с одной версией
+	errWeakIfMatch = errors.New("weak entity tags never match If-Match")
+)
+
+// setETag отдаёт версию записи как ETag, её клиент возвращает в If-Match при изменении
+func setETag(c *gin.Context, version int) {
+	c.Header("ETag", `"`+strconv.Itoa(version)+`"`)
+}
+
+// ifMatchVersion достаёт ожидаемую версию из If-Match. Без заголовка или с "*" возвращает nil —
+// тогда запись меняется без проверки
+func ifMatchVersion(c *gin.Context) (*int, error) {
+	tag := strings.TrimSpace(c.GetHeader("If-Match"))
+	if tag == "" || tag == "*" {
+		return nil, nil
+	}
+	if strings.HasPrefix(tag, "W/") {
+		return nil, errWeakIfMatch
+	}
+	if len(tag) < 2 || tag[0] != '"' || tag[len(tag)-1] != '"' {
+		return nil, errInvalidIfMatch
+	}
+	version, err := strconv.Atoi(tag[1 : len(tag)-1])
+	if err != nil || version < 1 {
+		return nil, errInvalidIfMatch
+	}
+	return &version, nil
+}
+
+// ifMatchStatus — код ответа на ошибку If-Match: слабый тег означает несовпавшее условие
+func ifMatchStatus(err error) int {
+	if errors.Is(err, errWeakIfMatch) {
+		return http.StatusPreconditionFailed
+	}
+	return http.StatusBadRequest
+}
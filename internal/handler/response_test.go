@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestIfMatchVersion(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		header  string
+		want    int   // 0 — версия не передана
+		wantErr error // nil — заголовок разобран
+	}{
+		{"", 0, nil},
+		{"*", 0, nil},
+		{` "3" `, 3, nil},
+		{`W/"3"`, 0, errWeakIfMatch},
+		{`W/"x"`, 0, errWeakIfMatch},
+		{"3", 0, errInvalidIfMatch},
+		{`"0"`, 0, errInvalidIfMatch},
+		{`"x"`, 0, errInvalidIfMatch},
+		{`"3", "4"`, 0, errInvalidIfMatch},
+		{`"`, 0, errInvalidIfMatch},
+	}
+	for _, tt := range tests {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest(http.MethodPut, "/api/wallets/1", nil)
+		if tt.header != "" {
+			c.Request.Header.Set("If-Match", tt.header)
+		}
+
+		got, err := ifMatchVersion(c)
+		if tt.wantErr != nil {
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("If-Match %q: error = %v, want %v", tt.header, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("If-Match %q: unexpected error %v", tt.header, err)
+			continue
+		}
+		switch {
+		case tt.want == 0 && got != nil:
+			t.Errorf("If-Match %q: version = %d, want nil", tt.header, *got)
+		case tt.want != 0 && (got == nil || *got != tt.want):
+			t.Errorf("If-Match %q: version = %v, want %d", tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestIfMatchStatus(t *testing.T) {
+	if got := ifMatchStatus(errWeakIfMatch); got != http.StatusPreconditionFailed {
+		t.Errorf("weak tag status = %d, want %d", got, http.StatusPreconditionFailed)
+	}
+	if got := ifMatchStatus(errInvalidIfMatch); got != http.StatusBadRequest {
+		t.Errorf("invalid tag status = %d, want %d", got, http.StatusBadRequest)
+	}
+}
+
+func TestSetETag(t *testing.T) {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	setETag(c, 7)
+	if got := w.Header().Get("ETag"); got != `"7"` {
+		t.Errorf("ETag = %q, want %q", got, `"7"`)
+	}
+}
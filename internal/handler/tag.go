@@ -0,0 +1,208 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/goonsorrow/finance-tracker-api/internal/models"
+	"github.com/goonsorrow/finance-tracker-api/internal/repository"
+	"github.com/goonsorrow/finance-tracker-api/internal/service"
+)
+
+type getAllTagsResponse struct {
+	Data []models.Tag `json:"tags"`
+}
+
+// @Summary Создать метку
+// @Description Метки пользователя свободнее категорий: на операцию можно повесить несколько
+// @Security Bearer
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Param input body models.CreateTagInput true "Name + Color"
+// @Param Idempotency-Key header string false "Ключ идемпотентности: повтор с тем же ключом вернёт первый ответ"
+// @Success 201 {object} map[string]int
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 409 {object} map[string]string "Tag already exists"
+// @Failure 422 {object} map[string]string "Ключ уже использован с другим запросом"
+// @Router /api/tags/ [post]
+func (h *Handler) createTag(c *gin.Context) {
+	userId, err := h.getUserId(c)
+	if err != nil {
+		return
+	}
+
+	var input models.CreateTagInput
+	if err := c.BindJSON(&input); err != nil {
+		h.newErrorResponse(c, http.StatusBadRequest, err, "invalid input data")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	id, err := h.services.Tag.Create(ctx, userId, input)
+	if err != nil {
+		h.tagError(c, err, "error while creating tag")
+		return
+	}
+
+	c.JSON(http.StatusCreated, map[string]interface{}{
+		"id": id,
+	})
+}
+
+// @Summary Список меток
+// @Description Все метки пользователя с числом операций
+// @Security Bearer
+// @Tags tags
+// @Produce json
+// @Success 200 {object} handler.getAllTagsResponse
+// @Router /api/tags/ [get]
+func (h *Handler) getAllTags(c *gin.Context) {
+	userId, err := h.getUserId(c)
+	if err != nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	tags, err := h.services.Tag.GetAll(ctx, userId)
+	if err != nil {
+		h.newErrorResponse(c, http.StatusInternalServerError, err, "failed to get tags")
+		return
+	}
+
+	c.JSON(http.StatusOK, getAllTagsResponse{
+		Data: tags,
+	})
+}
+
+// @Summary Получить метку по ID
+// @Security Bearer
+// @Tags tags
+// @Produce json
+// @Param id path int true "Tag ID"
+// @Success 200 {object} models.Tag
+// @Failure 400 {object} map[string]string "Invalid ID"
+// @Failure 404 {object} map[string]string "Not found"
+// @Router /api/tags/{id} [get]
+func (h *Handler) getTagByID(c *gin.Context) {
+	userId, err := h.getUserId(c)
+	if err != nil {
+		return
+	}
+
+	tagId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		h.newErrorResponse(c, http.StatusBadRequest, err, "invalid tag id")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	tag, err := h.services.Tag.GetById(ctx, userId, tagId)
+	if err != nil {
+		h.tagError(c, err, "error while getting tag")
+		return
+	}
+
+	c.JSON(http.StatusOK, tag)
+}
+
+// @Summary Обновить метку
+// @Security Bearer
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Param id path int true "Tag ID"
+// @Param input body models.UpdateTagInput true "Changes"
+// @Success 200 {object} handler.statusResponse
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 404 {object} map[string]string "Not found"
+// @Failure 409 {object} map[string]string "Tag already exists"
+// @Router /api/tags/{id} [put]
+func (h *Handler) updateTagByID(c *gin.Context) {
+	userId, err := h.getUserId(c)
+	if err != nil {
+		return
+	}
+
+	tagId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		h.newErrorResponse(c, http.StatusBadRequest, err, "invalid tag id")
+		return
+	}
+
+	var input models.UpdateTagInput
+	if err := c.BindJSON(&input); err != nil {
+		h.newErrorResponse(c, http.StatusBadRequest, err, "invalid input data")
+		return
+	}
+
+	if err := input.Validate(); err != nil {
+		h.newErrorResponse(c, http.StatusBadRequest, err, err.Error())
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	if err := h.services.Tag.Update(ctx, userId, tagId, input); err != nil {
+		h.tagError(c, err, "error while updating tag")
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{Status: "ok"})
+}
+
+// @Summary Удалить метку
+// @Description Метка снимается со всех операций, сами операции остаются
+// @Security Bearer
+// @Tags tags
+// @Produce json
+// @Param id path int true "Tag ID"
+// @Success 200 {object} handler.statusResponse
+// @Failure 404 {object} map[string]string "Not found"
+// @Router /api/tags/{id} [delete]
+func (h *Handler) deleteTagByID(c *gin.Context) {
+	userId, err := h.getUserId(c)
+	if err != nil {
+		return
+	}
+
+	tagId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		h.newErrorResponse(c, http.StatusBadRequest, err, "invalid tag id")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	if err := h.services.Tag.Delete(ctx, userId, tagId); err != nil {
+		h.tagError(c, err, "error while deleting tag")
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{Status: "ok"})
+}
+
+func (h *Handler) tagError(c *gin.Context, err error, msg string) {
+	switch {
+	case errors.Is(err, repository.ErrRecordNotFound):
+		h.newErrorResponse(c, http.StatusNotFound, err, "tag not found")
+	case errors.Is(err, repository.ErrDuplicate):
+		h.newErrorResponse(c, http.StatusConflict, err, "tag with this name already exists")
+	case errors.Is(err, service.ErrInvalidTags):
+		h.newErrorResponse(c, http.StatusBadRequest, err, err.Error())
+	default:
+		h.newErrorResponse(c, http.StatusInternalServerError, err, msg)
+	}
+}
@@ -0,0 +1,275 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/goonsorrow/finance-tracker-api/internal/currency"
+	"github.com/goonsorrow/finance-tracker-api/internal/models"
+	"github.com/goonsorrow/finance-tracker-api/internal/repository"
+	"github.com/goonsorrow/finance-tracker-api/internal/service"
+)
+
+type getAllTransfersResponse struct {
+	Data []models.Transfer `json:"transfers"`
+}
+
+// @Summary Перевод между кошельками
+// @Description Списать сумму с одного кошелька и зачислить на другой. Пишется двумя связанными операциями.
+// @Description Между кошельками в разных валютах нужен to_amount (сумма зачисления) или rate (курс)
+// @Security Bearer
+// @Tags transfers
+// @Accept json
+// @Produce json
+// @Param input body models.CreateTransferInput true "Кошельки + Сумма"
+// @Param Idempotency-Key header string false "Ключ идемпотентности: повтор с тем же ключом вернёт первый ответ"
+// @Success 201 {object} map[string]int "Transfer ID"
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 404 {object} map[string]string "Wallet not found"
+// @Failure 422 {object} map[string]string "Ключ уже использован с другим запросом"
+// @Router /api/transfers/ [post]
+func (h *Handler) createTransfer(c *gin.Context) {
+	userId, err := h.getUserId(c)
+	if err != nil {
+		return
+	}
+
+	var input models.CreateTransferInput
+	if err := c.BindJSON(&input); err != nil {
+		h.newErrorResponse(c, http.StatusBadRequest, err, "invalid input data")
+		return
+	}
+
+	if err := input.Validate(); err != nil {
+		h.newErrorResponse(c, http.StatusBadRequest, err, err.Error())
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	id, err := h.services.Transfer.Create(ctx, userId, input)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			h.newErrorResponse(c, http.StatusNotFound, err, "wallet not found")
+			return
+		}
+		if errors.Is(err, service.ErrInvalidTransferRate) {
+			h.newErrorResponse(c, http.StatusBadRequest, err, err.Error())
+			return
+		}
+		if errors.Is(err, currency.ErrInvalidAmount) {
+			h.newErrorResponse(c, http.StatusBadRequest, err, err.Error())
+			return
+		}
+		h.newErrorResponse(c, http.StatusInternalServerError, err, "error while creating transfer")
+		return
+	}
+
+	c.JSON(http.StatusCreated, map[string]interface{}{
+		"id": id,
+	})
+}
+
+// @Summary Список переводов
+// @Security Bearer
+// @Tags transfers
+// @Produce json
+// @Success 200 {object} handler.getAllTransfersResponse
+// @Router /api/transfers/ [get]
+func (h *Handler) getAllTransfers(c *gin.Context) {
+	userId, err := h.getUserId(c)
+	if err != nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	transfers, err := h.services.Transfer.GetAll(ctx, userId)
+	if err != nil {
+		h.newErrorResponse(c, http.StatusInternalServerError, err, "failed to get transfers")
+		return
+	}
+
+	c.JSON(http.StatusOK, getAllTransfersResponse{
+		Data: transfers,
+	})
+}
+
+// @Summary Перевод по ID
+// @Description Перевод вместе с обеими операциями
+// @Security Bearer
+// @Tags transfers
+// @Produce json
+// @Param id path int true "Transfer ID"
+// @Success 200 {object} models.Transfer
+// @Failure 404 {object} map[string]string "Not found"
+// @Router /api/transfers/{id} [get]
+func (h *Handler) getTransferByID(c *gin.Context) {
+	userId, err := h.getUserId(c)
+	if err != nil {
+		return
+	}
+
+	transferId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		h.newErrorResponse(c, http.StatusBadRequest, err, "invalid transfer id")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	transfer, err := h.services.Transfer.GetById(ctx, userId, transferId)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			h.newErrorResponse(c, http.StatusNotFound, err, "transfer not found")
+			return
+		}
+		h.newErrorResponse(c, http.StatusInternalServerError, err, "error while getting transfer")
+		return
+	}
+
+	c.JSON(http.StatusOK, transfer)
+}
+
+// @Summary Обновить перевод
+// @Description Меняет обе операции перевода и балансы обоих кошельков
+// @Security Bearer
+// @Tags transfers
+// @Accept json
+// @Produce json
+// @Param id path int true "Transfer ID"
+// @Param input body models.UpdateTransferInput true "Changes"
+// @Success 200 {object} handler.statusResponse
+// @Failure 400 {object} map[string]string "Invalid amounts"
+// @Failure 404 {object} map[string]string "Not found"
+// @Router /api/transfers/{id} [put]
+func (h *Handler) updateTransferByID(c *gin.Context) {
+	userId, err := h.getUserId(c)
+	if err != nil {
+		return
+	}
+
+	transferId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		h.newErrorResponse(c, http.StatusBadRequest, err, "invalid transfer id")
+		return
+	}
+
+	var input models.UpdateTransferInput
+	if err := c.BindJSON(&input); err != nil {
+		h.newErrorResponse(c, http.StatusBadRequest, err, "invalid input data")
+		return
+	}
+
+	if err := input.Validate(); err != nil {
+		h.newErrorResponse(c, http.StatusBadRequest, err, err.Error())
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	err = h.services.Transfer.Update(ctx, userId, transferId, input)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			h.newErrorResponse(c, http.StatusNotFound, err, "transfer not found")
+			return
+		}
+		if errors.Is(err, service.ErrInvalidTransferRate) {
+			h.newErrorResponse(c, http.StatusBadRequest, err, err.Error())
+			return
+		}
+		if errors.Is(err, currency.ErrInvalidAmount) {
+			h.newErrorResponse(c, http.StatusBadRequest, err, err.Error())
+			return
+		}
+		h.newErrorResponse(c, http.StatusInternalServerError, err, "error while updating transfer")
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{Status: "ok"})
+}
+
+// @Summary Удалить перевод
+// @Description Удаляет обе операции перевода и возвращает балансы кошельков
+// @Security Bearer
+// @Tags transfers
+// @Produce json
+// @Param id path int true "Transfer ID"
+// @Success 200 {object} handler.statusResponse
+// @Failure 404 {object} map[string]string "Not found"
+// @Router /api/transfers/{id} [delete]
+func (h *Handler) deleteTransferByID(c *gin.Context) {
+	userId, err := h.getUserId(c)
+	if err != nil {
+		return
+	}
+
+	transferId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		h.newErrorResponse(c, http.StatusBadRequest, err, "invalid transfer id")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	err = h.services.Transfer.Delete(ctx, userId, transferId)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			h.newErrorResponse(c, http.StatusNotFound, err, "transfer not found")
+			return
+		}
+		h.newErrorResponse(c, http.StatusInternalServerError, err, "error while deleting transfer")
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{Status: "ok"})
+}
+
+// @Summary Курсовые разницы по переводам
+// @Description Сколько отправлено и получено по каждой паре валют, средний фактический курс и итог по каждой валюте.
+// @Description fx_gain — выигрыш или потеря на обменах против справочного курса на дату перевода, в базовой валюте
+// @Security Bearer
+// @Tags reports
+// @Produce json
+// @Param from query string false "Дата начала (YYYY-MM-DD)"
+// @Param to query string false "Дата конца включительно (YYYY-MM-DD)"
+// @Success 200 {object} models.FXReport
+// @Failure 400 {object} map[string]string "Invalid period"
+// @Router /api/reports/fx [get]
+func (h *Handler) getFXReport(c *gin.Context) {
+	userId, err := h.getUserId(c)
+	if err != nil {
+		return
+	}
+
+	var input models.ReportPeriodInput
+	if err := c.ShouldBindQuery(&input); err != nil {
+		h.newErrorResponse(c, http.StatusBadRequest, err, "invalid period")
+		return
+	}
+
+	if err := input.Validate(); err != nil {
+		h.newErrorResponse(c, http.StatusBadRequest, err, err.Error())
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	report, err := h.services.Transfer.FXReport(ctx, userId, input)
+	if err != nil {
+		h.newErrorResponse(c, http.StatusInternalServerError, err, "failed to build fx report")
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,109 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/goonsorrow/finance-tracker-api/internal/repository"
+	"github.com/goonsorrow/finance-tracker-api/internal/service"
+)
+
+// @Summary Корзина
+// @Description Удалённые кошельки, транзакции и категории. Записи хранятся ограниченное время,
+// @Description после чего удаляются окончательно вместе с вложениями
+// @Security Bearer
+// @Tags trash
+// @Produce json
+// @Success 200 {object} models.Trash
+// @Router /api/trash [get]
+func (h *Handler) getTrash(c *gin.Context) {
+	userId, err := h.getUserId(c)
+	if err != nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	trash, err := h.services.Trash.List(ctx, userId)
+	if err != nil {
+		h.newErrorResponse(c, http.StatusInternalServerError, err, "error while getting trash")
+		return
+	}
+	c.JSON(http.StatusOK, trash)
+}
+
+// @Summary Восстановить кошелёк
+// @Description Возвращает кошелёк с его транзакциями и переводами; суммы переводов снова учитываются в других кошельках
+// @Security Bearer
+// @Tags trash
+// @Produce json
+// @Param id path int true "Wallet ID"
+// @Success 200 {object} handler.statusResponse
+// @Failure 404 {object} map[string]string "Wallet is not in the trash"
+// @Router /api/trash/wallets/{id}/restore [post]
+func (h *Handler) restoreWallet(c *gin.Context) {
+	h.restoreFromTrash(c, "wallet", h.services.Trash.RestoreWallet)
+}
+
+// @Summary Восстановить транзакцию
+// @Description Возвращает транзакцию и снова учитывает её сумму в балансе кошелька
+// @Security Bearer
+// @Tags trash
+// @Produce json
+// @Param id path int true "Movement ID"
+// @Success 200 {object} handler.statusResponse
+// @Failure 404 {object} map[string]string "Movement is not in the trash"
+// @Failure 409 {object} map[string]string "Wallet is in the trash"
+// @Router /api/trash/movements/{id}/restore [post]
+func (h *Handler) restoreMovement(c *gin.Context) {
+	h.restoreFromTrash(c, "movement", h.services.Trash.RestoreMovement)
+}
+
+// @Summary Восстановить категорию
+// @Security Bearer
+// @Tags trash
+// @Produce json
+// @Param id path int true "Category ID"
+// @Success 200 {object} handler.statusResponse
+// @Failure 404 {object} map[string]string "Category is not in the trash"
+// @Failure 409 {object} map[string]string "Category with this name already exists"
+// @Router /api/trash/categories/{id}/restore [post]
+func (h *Handler) restoreCategory(c *gin.Context) {
+	h.restoreFromTrash(c, "category", h.services.Trash.RestoreCategory)
+}
+
+func (h *Handler) restoreFromTrash(c *gin.Context, entity string, restore func(ctx context.Context, userId, id int) error) {
+	userId, err := h.getUserId(c)
+	if err != nil {
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		h.newErrorResponse(c, http.StatusBadRequest, err, "invalid "+entity+" id")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	if err := restore(ctx, userId, id); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRecordNotFound):
+			h.newErrorResponse(c, http.StatusNotFound, err, entity+" is not in the trash")
+		case errors.Is(err, service.ErrWalletInTrash):
+			h.newErrorResponse(c, http.StatusConflict, err, err.Error())
+		case errors.Is(err, repository.ErrDuplicate):
+			h.newErrorResponse(c, http.StatusConflict, err, entity+" with this name already exists")
+		default:
+			h.newErrorResponse(c, http.StatusInternalServerError, err, "error while restoring "+entity)
+		}
+		return
+	}
+	c.JSON(http.StatusOK, statusResponse{Status: "ok"})
+}
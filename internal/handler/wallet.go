@@ -2,12 +2,16 @@ package handler
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/goonsorrow/finance-tracker-api/internal/currency"
 	"github.com/goonsorrow/finance-tracker-api/internal/models"
+	"github.com/goonsorrow/finance-tracker-api/internal/repository"
+	"github.com/goonsorrow/finance-tracker-api/internal/service"
 )
 
 type getAllWalletsResponse struct {
@@ -25,7 +29,9 @@ type statusResponse struct {
 // @Accept json
 // @Produce json
 // @Param input body models.CreateWalletInput true "Name + Currency"
+// @Param Idempotency-Key header string false "Ключ идемпотентности: повтор с тем же ключом вернёт первый ответ"
 // @Success 201 {object} map[string]int
+// @Failure 422 {object} map[string]string "Ключ уже использован с другим запросом"
 // @Router /api/wallets/ [post]
 func (h *Handler) createWallet(c *gin.Context) {
 	userId, err := h.getUserId(c)
@@ -44,6 +50,10 @@ func (h *Handler) createWallet(c *gin.Context) {
 
 	id, err := h.services.Wallet.Create(ctx, userId, input)
 	if err != nil {
+		if errors.Is(err, currency.ErrInvalidAmount) {
+			h.newErrorResponse(c, http.StatusBadRequest, err, err.Error())
+			return
+		}
 		h.newErrorResponse(c, http.StatusInternalServerError, err, "error while creating wallet")
 		return
 	}
@@ -88,6 +98,7 @@ func (h *Handler) getAllWallets(c *gin.Context) {
 // @Produce json
 // @Param id path int true "Wallet ID"
 // @Success 200 {object} models.Wallet
+// @Header 200 {string} ETag "Версия кошелька для If-Match"
 // @Failure 400 {object} map[string]string "Invalid ID"
 // @Failure 404 {object} map[string]string "Not found"
 // @Router /api/wallets/{id} [get]
@@ -111,6 +122,7 @@ func (h *Handler) getWalletByID(c *gin.Context) {
 		return
 	}
 
+	setETag(c, wallet.Version)
 	c.JSON(http.StatusOK, wallet)
 }
 
@@ -120,9 +132,12 @@ func (h *Handler) getWalletByID(c *gin.Context) {
 // @Tags wallets
 // @Produce json
 // @Param id path int true "Wallet ID"
+// @Param If-Match header string false "ETag из GET /api/wallets/{id}"
 // @Success 200 {object} models.Wallet
 // @Failure 400 {object} map[string]string "Invalid ID"
 // @Failure 404 {object} map[string]string "Not found"
+// @Failure 409 {object} map[string]string "Валюту кошелька с операциями менять нельзя"
+// @Failure 412 {object} map[string]string "Кошелёк изменён другим запросом"
 // @Router /api/wallets/{id} [get]
 func (h *Handler) updateWalletByID(c *gin.Context) {
 	userId, err := h.getUserId(c)
@@ -136,6 +151,12 @@ func (h *Handler) updateWalletByID(c *gin.Context) {
 		return
 	}
 
+	version, err := ifMatchVersion(c)
+	if err != nil {
+		h.newErrorResponse(c, ifMatchStatus(err), err, err.Error())
+		return
+	}
+
 	var input models.UpdateWalletInput
 	if err := c.BindJSON(&input); err != nil {
 		h.newErrorResponse(c, http.StatusBadRequest, err, "error while reading input")
@@ -145,8 +166,20 @@ func (h *Handler) updateWalletByID(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
 	defer cancel()
 
-	err = h.services.Wallet.Update(ctx, userId, id, input)
+	err = h.services.Wallet.Update(ctx, userId, id, input, version)
 	if err != nil {
+		if errors.Is(err, repository.ErrVersionConflict) {
+			h.newErrorResponse(c, http.StatusPreconditionFailed, err, "wallet was changed, fetch it again")
+			return
+		}
+		if errors.Is(err, service.ErrWalletCurrencyLocked) {
+			h.newErrorResponse(c, http.StatusConflict, err, err.Error())
+			return
+		}
+		if errors.Is(err, currency.ErrInvalidAmount) {
+			h.newErrorResponse(c, http.StatusBadRequest, err, err.Error())
+			return
+		}
 		h.newErrorResponse(c, http.StatusInternalServerError, err, "error while updating user wallet by id")
 		return
 	}
@@ -155,13 +188,15 @@ func (h *Handler) updateWalletByID(c *gin.Context) {
 }
 
 // @Summary Удалить кошелёк
-// @Description Удалить кошелёк и все транзакции
+// @Description Переносит кошелёк со всеми транзакциями в корзину; восстановление — POST /api/trash/wallets/{id}/restore
 // @Security Bearer
 // @Tags wallets
 // @Produce json
 // @Param id path int true "Wallet ID"
+// @Param If-Match header string false "ETag из GET /api/wallets/{id}"
 // @Success 200 {object} handler.statusResponse
 // @Failure 400 {object} map[string]string "Invalid ID"
+// @Failure 412 {object} map[string]string "Кошелёк изменён другим запросом"
 // @Router /api/wallets/{id} [delete]
 func (h *Handler) deleteWalletByID(c *gin.Context) {
 	userId, err := h.getUserId(c)
@@ -175,11 +210,21 @@ func (h *Handler) deleteWalletByID(c *gin.Context) {
 		return
 	}
 
+	version, err := ifMatchVersion(c)
+	if err != nil {
+		h.newErrorResponse(c, ifMatchStatus(err), err, err.Error())
+		return
+	}
+
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
 	defer cancel()
 
-	err = h.services.Wallet.Delete(ctx, userId, id)
+	err = h.services.Wallet.Delete(ctx, userId, id, version)
 	if err != nil {
+		if errors.Is(err, repository.ErrVersionConflict) {
+			h.newErrorResponse(c, http.StatusPreconditionFailed, err, "wallet was changed, fetch it again")
+			return
+		}
 		h.newErrorResponse(c, http.StatusInternalServerError, err, "error while deleting user wallet by id")
 		return
 	}
@@ -0,0 +1,238 @@
+package importer
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/goonsorrow/finance-tracker-api/internal/currency"
+	"github.com/goonsorrow/finance-tracker-api/internal/models"
+)
+
+// ParseCSV разбирает выписку по сопоставлению колонок. Суммы переводятся в минимальные
+// единицы валюты кошелька. Ошибки в отдельных строках не прерывают разбор и
+// возвращаются списком; error — только если файл целиком непригоден.
+func ParseCSV(r io.Reader, mapping models.CSVMapping, currencyCode string) ([]Entry, []models.ImportRowError, error) {
+	reader := csv.NewReader(skipBOM(r))
+	if mapping.Delimiter != "" {
+		reader.Comma = []rune(mapping.Delimiter)[0]
+	}
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	reader.TrimLeadingSpace = true
+
+	for i := 0; i < mapping.SkipRows; i++ {
+		if _, err := reader.Read(); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil, nil, nil
+			}
+			return nil, nil, fmt.Errorf("%w: %v", ErrInvalidFile, err)
+		}
+	}
+
+	var header []string
+	if mapping.HasHeader {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return nil, nil, nil
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: %v", ErrInvalidFile, err)
+		}
+		header = record
+	}
+
+	cols, err := resolveColumns(mapping, header)
+	if err != nil {
+		return nil, nil, err
+	}
+	layout := mapping.DateFormat
+	if layout == "" {
+		layout = "2006-01-02"
+	}
+
+	var (
+		entries []Entry
+		rowErrs []models.ImportRowError
+	)
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: %v", ErrInvalidFile, err)
+		}
+		line, _ := reader.FieldPos(0)
+
+		if len(entries)+len(rowErrs) >= MaxRows {
+			return nil, nil, fmt.Errorf("%w: more than %d rows", ErrInvalidFile, MaxRows)
+		}
+
+		entry, err := parseRecord(record, cols, mapping, layout, currencyCode)
+		if err != nil {
+			rowErrs = append(rowErrs, models.ImportRowError{Line: line, Error: err.Error()})
+			continue
+		}
+		entry.Line = line
+		entries = append(entries, entry)
+	}
+	return entries, rowErrs, nil
+}
+
+// Номера колонок; -1 — колонка не задана
+type csvColumns struct {
+	date, amount, debit, credit, description, category int
+}
+
+func resolveColumns(mapping models.CSVMapping, header []string) (csvColumns, error) {
+	index := make(map[string]int, len(header))
+	for i, name := range header {
+		index[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+
+	resolve := func(ref string) (int, error) {
+		if ref == "" {
+			return -1, nil
+		}
+		if n, err := strconv.Atoi(ref); err == nil {
+			if n < 1 {
+				return 0, fmt.Errorf("%w: column number must start from 1", ErrInvalidMapping)
+			}
+			return n - 1, nil
+		}
+		if i, ok := index[strings.ToLower(strings.TrimSpace(ref))]; ok {
+			return i, nil
+		}
+		return 0, fmt.Errorf("%w: column %q not found in header", ErrInvalidMapping, ref)
+	}
+
+	var (
+		cols csvColumns
+		err  error
+	)
+	for _, c := range []struct {
+		dst *int
+		ref string
+	}{
+		{&cols.date, mapping.Date},
+		{&cols.amount, mapping.Amount},
+		{&cols.debit, mapping.Debit},
+		{&cols.credit, mapping.Credit},
+		{&cols.description, mapping.Description},
+		{&cols.category, mapping.Category},
+	} {
+		if *c.dst, err = resolve(c.ref); err != nil {
+			return cols, err
+		}
+	}
+	return cols, nil
+}
+
+func parseRecord(record []string, cols csvColumns, mapping models.CSVMapping, layout, currencyCode string) (Entry, error) {
+	field := func(i int) string {
+		if i < 0 || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	rawDate := field(cols.date)
+	date, err := time.Parse(layout, rawDate)
+	if err != nil {
+		return Entry{}, fmt.Errorf("invalid date %q", rawDate)
+	}
+
+	var amount int64
+	if mapping.Sign == models.SignSplit {
+		debit, err := parseOptionalAmount(field(cols.debit), mapping.DecimalSeparator, currencyCode)
+		if err != nil {
+			return Entry{}, err
+		}
+		credit, err := parseOptionalAmount(field(cols.credit), mapping.DecimalSeparator, currencyCode)
+		if err != nil {
+			return Entry{}, err
+		}
+		// списание может быть записано как с минусом, так и без
+		amount = abs(credit) - abs(debit)
+	} else {
+		raw := field(cols.amount)
+		if raw == "" {
+			return Entry{}, errors.New("amount is empty")
+		}
+		if amount, err = parseAmount(raw, mapping.DecimalSeparator, currencyCode); err != nil {
+			return Entry{}, err
+		}
+		if mapping.Sign == models.SignInverted {
+			amount = -amount
+		}
+	}
+	if amount == 0 {
+		return Entry{}, errors.New("amount must not be zero")
+	}
+
+	entry := newEntry(date, amount, currencyCode, field(cols.description), "")
+	entry.Category = field(cols.category)
+	return entry, nil
+}
+
+// skipBOM убирает UTF-8 BOM, который Excel и многие банки пишут в начало файла
+func skipBOM(r io.Reader) io.Reader {
+	br := bufio.NewReader(r)
+	if bom, err := br.Peek(3); err == nil && string(bom) == "\ufeff" {
+		br.Discard(3)
+	}
+	return br
+}
+
+func parseOptionalAmount(raw, decimalSeparator, currencyCode string) (int64, error) {
+	if raw == "" {
+		return 0, nil
+	}
+	return parseAmount(raw, decimalSeparator, currencyCode)
+}
+
+// parseAmount приводит банковскую запись суммы ("1 234,50", "(12.00)", "-1,234.50") к виду,
+// понятному currency.ParseAmount
+func parseAmount(raw, decimalSeparator, currencyCode string) (int64, error) {
+	s := strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '\u00a0', '\u202f', '\'':
+			return -1
+		}
+		return r
+	}, raw)
+
+	negative := false
+	if strings.HasPrefix(s, "(") && strings.HasSuffix(s, ")") {
+		negative = true
+		s = s[1 : len(s)-1]
+	}
+	if decimalSeparator == "," {
+		s = strings.ReplaceAll(s, ".", "")
+		s = strings.ReplaceAll(s, ",", ".")
+	} else {
+		s = strings.ReplaceAll(s, ",", "")
+	}
+
+	amount, err := currency.ParseAmount(s, currencyCode)
+	if err != nil {
+		return 0, fmt.Errorf("invalid amount %q: %w", raw, err)
+	}
+	if negative {
+		amount = -amount
+	}
+	return amount, nil
+}
+
+func abs(v int64) int64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,101 @@
+package importer
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/goonsorrow/finance-tracker-api/internal/models"
+)
+
+func TestParseCSV(t *testing.T) {
+	tests := []struct {
+		name       string
+		data       string
+		mapping    models.CSVMapping
+		wantTypes  []string
+		wantAmount []int64
+		wantLines  []int
+		wantErrs   []int
+	}{
+		{
+			name: "signed amount with header and comma separator",
+			data: "\ufeffДата;Сумма;Описание\n27.01.2026;-1 234,50;Магазин\n28.01.2026;50000,00;Зарплата\n",
+			mapping: models.CSVMapping{Delimiter: ";", HasHeader: true, Date: "Дата", DateFormat: "02.01.2006",
+				Amount: "Сумма", DecimalSeparator: ",", Description: "Описание"},
+			wantTypes:  []string{"expense", "income"},
+			wantAmount: []int64{123450, 5000000},
+			wantLines:  []int{2, 3},
+		},
+		{
+			name:       "inverted sign and column numbers",
+			data:       "2026-01-27,12.00\n2026-01-28,-3.50\n",
+			mapping:    models.CSVMapping{Date: "1", Amount: "2", Sign: models.SignInverted},
+			wantTypes:  []string{"expense", "income"},
+			wantAmount: []int64{1200, 350},
+			wantLines:  []int{1, 2},
+		},
+		{
+			name: "split debit and credit",
+			data: "skip me\ndate,debit,credit\n2026-01-27,-10.00,\n2026-01-28,,20.00\n",
+			mapping: models.CSVMapping{SkipRows: 1, HasHeader: true, Date: "date",
+				Debit: "debit", Credit: "credit", Sign: models.SignSplit},
+			wantTypes:  []string{"expense", "income"},
+			wantAmount: []int64{1000, 2000},
+			wantLines:  []int{3, 4},
+		},
+		{
+			name:       "bad rows are reported and do not stop parsing",
+			data:       "2026-01-27,0\n27/01/2026,5\n2026-01-28,abc\n2026-01-29,7.00\n",
+			mapping:    models.CSVMapping{Date: "1", Amount: "2"},
+			wantTypes:  []string{"income"},
+			wantAmount: []int64{700},
+			wantLines:  []int{4},
+			wantErrs:   []int{1, 2, 3},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, rowErrs, err := ParseCSV(strings.NewReader(tt.data), tt.mapping, "RUB")
+			if err != nil {
+				t.Fatalf("ParseCSV: %v", err)
+			}
+			if len(entries) != len(tt.wantTypes) {
+				t.Fatalf("got %d entries, want %d", len(entries), len(tt.wantTypes))
+			}
+			for i, e := range entries {
+				if e.Movement.Type != tt.wantTypes[i] || e.Movement.Amount != tt.wantAmount[i] || e.Line != tt.wantLines[i] {
+					t.Errorf("entry %d = %s %d at line %d, want %s %d at line %d", i,
+						e.Movement.Type, e.Movement.Amount, e.Line, tt.wantTypes[i], tt.wantAmount[i], tt.wantLines[i])
+				}
+			}
+			if len(rowErrs) != len(tt.wantErrs) {
+				t.Fatalf("got row errors %+v, want lines %v", rowErrs, tt.wantErrs)
+			}
+			for i, rowErr := range rowErrs {
+				if rowErr.Line != tt.wantErrs[i] {
+					t.Errorf("row error %d at line %d, want %d", i, rowErr.Line, tt.wantErrs[i])
+				}
+			}
+		})
+	}
+}
+
+func TestParseCSVUnknownColumn(t *testing.T) {
+	_, _, err := ParseCSV(strings.NewReader("date,amount\n"), models.CSVMapping{HasHeader: true, Date: "date", Amount: "sum"}, "USD")
+	if !errors.Is(err, ErrInvalidMapping) {
+		t.Fatalf("err = %v, want %v", err, ErrInvalidMapping)
+	}
+}
+
+func TestParseCSVRowLimit(t *testing.T) {
+	var b strings.Builder
+	for i := 0; i < MaxRows+1; i++ {
+		fmt.Fprintf(&b, "2026-01-27,%d.00\n", i+1)
+	}
+	_, _, err := ParseCSV(strings.NewReader(b.String()), models.CSVMapping{Date: "1", Amount: "2"}, "USD")
+	if !errors.Is(err, ErrInvalidFile) {
+		t.Fatalf("err = %v, want %v", err, ErrInvalidFile)
+	}
+}
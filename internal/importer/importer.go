@@ -0,0 +1,99 @@
+// Package importer разбирает банковские выписки в операции кошелька
+package importer
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/goonsorrow/finance-tracker-api/internal/models"
+)
+
+// Сколько строк допускается в одном файле
+const MaxRows = 10000
+
+var (
+	ErrInvalidFile    = errors.New("invalid statement file")
+	ErrInvalidMapping = errors.New("invalid column mapping")
+)
+
+// Entry — операция из выписки. Category — название категории как в файле,
+// сопоставление с категориями пользователя делает сервис.
+type Entry struct {
+	Line     int
+	Movement models.Movement
+	Category string
+}
+
+// newEntry строит операцию по сумме со знаком: отрицательная — расход
+func newEntry(date time.Time, amount int64, currencyCode, description, externalId string) Entry {
+	movementType := "income"
+	if amount < 0 {
+		movementType = "expense"
+	}
+	entry := Entry{
+		Movement: models.Movement{
+			Type:        movementType,
+			Amount:      abs(amount),
+			Currency:    currencyCode,
+			Description: description,
+			Date:        date.UTC(),
+		},
+	}
+	if externalId != "" {
+		entry.Movement.ExternalID = &externalId
+	}
+	return entry
+}
+
+// DetectFormat определяет формат выписки по расширению файла
+func DetectFormat(filename string) string {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".ofx", ".qfx":
+		return "ofx"
+	case ".qif":
+		return "qif"
+	default:
+		return "csv"
+	}
+}
+
+// guessDecimalSeparator: десятичный разделитель — последний из '.' и ','. Одиночная запятая
+// считается десятичной, только если после неё не больше двух цифр ("12,5", но не "1,234").
+func guessDecimalSeparator(raw string) string {
+	dot, comma := strings.LastIndex(raw, "."), strings.LastIndex(raw, ",")
+	switch {
+	case comma < 0:
+		return "."
+	case dot >= 0:
+		if comma > dot {
+			return ","
+		}
+		return "."
+	case len(strings.TrimRight(raw[comma+1:], ")")) <= 2:
+		return ","
+	default:
+		return "."
+	}
+}
+
+// fallbackIDs строит идентификаторы для операций без FITID. Одинаковые операции в один
+// день различаются порядковым номером, поэтому повторный импорт того же файла даёт те же id.
+type fallbackIDs struct {
+	seen map[string]int
+}
+
+func newFallbackIDs() *fallbackIDs {
+	return &fallbackIDs{seen: make(map[string]int)}
+}
+
+func (f *fallbackIDs) next(date time.Time, amount int64, description string) string {
+	key := fmt.Sprintf("%s|%d|%s", date.Format("2006-01-02"), amount, strings.ToLower(description))
+	f.seen[key]++
+	sum := sha1.Sum([]byte(fmt.Sprintf("%s|%d", key, f.seen[key])))
+	return "sha1:" + hex.EncodeToString(sum[:])
+}
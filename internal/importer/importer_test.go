@@ -0,0 +1,80 @@
+package importer
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGuessDecimalSeparator(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want string
+	}{
+		{"12.50", "."},
+		{"12,50", ","},
+		{"12,5", ","},
+		{"1,234", "."},
+		{"1,234.50", "."},
+		{"1.234,50", ","},
+		{"(12,50)", ","},
+		{"-1 234,50", ","},
+		{"1200", "."},
+	}
+	for _, tt := range tests {
+		if got := guessDecimalSeparator(tt.raw); got != tt.want {
+			t.Errorf("guessDecimalSeparator(%q) = %q, want %q", tt.raw, got, tt.want)
+		}
+	}
+}
+
+func TestParseAmount(t *testing.T) {
+	tests := []struct {
+		raw       string
+		separator string
+		code      string
+		want      int64
+		wantErr   bool
+	}{
+		{"1 234,50", ",", "RUB", 123450, false},
+		{"1.234,50", ",", "EUR", 123450, false},
+		{"-1,234.50", ".", "USD", -123450, false},
+		{"(12.00)", ".", "USD", -1200, false},
+		{"1'000", ".", "JPY", 1000, false},
+		{"12.345", ".", "USD", 0, true},
+		{"abc", ".", "USD", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := parseAmount(tt.raw, tt.separator, tt.code)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseAmount(%q) error = %v, wantErr %v", tt.raw, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parseAmount(%q) = %d, want %d", tt.raw, got, tt.want)
+		}
+	}
+}
+
+func TestFallbackIDs(t *testing.T) {
+	date := time.Date(2026, 1, 27, 0, 0, 0, 0, time.UTC)
+
+	first := newFallbackIDs()
+	a := first.next(date, -500, "Coffee")
+	b := first.next(date, -500, "coffee")
+	c := first.next(date, -600, "Coffee")
+	if a == b {
+		t.Error("identical movements on one day must get different ids")
+	}
+	if a == c {
+		t.Error("movements with different amounts must get different ids")
+	}
+
+	// повторный разбор того же файла даёт те же идентификаторы
+	second := newFallbackIDs()
+	if got := second.next(date, -500, "COFFEE"); got != a {
+		t.Errorf("second import: id = %q, want %q", got, a)
+	}
+	if got := second.next(date, -500, "Coffee"); got != b {
+		t.Errorf("second import: id = %q, want %q", got, b)
+	}
+}
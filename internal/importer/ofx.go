@@ -0,0 +1,132 @@
+package importer
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/goonsorrow/finance-tracker-api/internal/models"
+)
+
+// Тег OFX с текстом до следующего тега. В SGML-варианте (OFX 1.x) у полей нет
+// закрывающих тегов, поэтому значение — всё, что идёт до следующего '<'.
+var ofxTag = regexp.MustCompile(`<(/?)([A-Za-z0-9._]+)[^>]*>([^<]*)`)
+
+// ParseOFX разбирает выписку OFX/QFX: и SGML 1.x, и XML 2.x. Идентификатором
+// операции служит FITID.
+func ParseOFX(r io.Reader, currencyCode string) ([]Entry, []models.ImportRowError, error) {
+	raw, err := io.ReadAll(r)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrInvalidFile, err)
+	}
+	data := string(raw)
+	if !strings.Contains(strings.ToUpper(data), "<OFX>") {
+		return nil, nil, fmt.Errorf("%w: <OFX> element not found", ErrInvalidFile)
+	}
+
+	var (
+		entries  []Entry
+		rowErrs  []models.ImportRowError
+		trn      map[string]string
+		trnLine  int
+		statCurr string
+		ids      = newFallbackIDs()
+		// номер строки считается от предыдущего тега, а не от начала файла
+		line    = 1
+		counted = 0
+	)
+	for _, m := range ofxTag.FindAllStringSubmatchIndex(data, -1) {
+		closing := m[3] > m[2]
+		tag := strings.ToUpper(data[m[4]:m[5]])
+		value := strings.TrimSpace(html.UnescapeString(data[m[6]:m[7]]))
+
+		switch {
+		case tag == "STMTTRN" && !closing:
+			trn = make(map[string]string)
+			line += strings.Count(data[counted:m[0]], "\n")
+			counted = m[0]
+			trnLine = line
+		case tag == "STMTTRN" && closing:
+			if trn == nil {
+				continue
+			}
+			if len(entries)+len(rowErrs) >= MaxRows {
+				return nil, nil, fmt.Errorf("%w: more than %d transactions", ErrInvalidFile, MaxRows)
+			}
+			entry, err := ofxEntry(trn, currencyCode, ids)
+			if err != nil {
+				rowErrs = append(rowErrs, models.ImportRowError{Line: trnLine, Error: err.Error()})
+			} else {
+				entry.Line = trnLine
+				entries = append(entries, entry)
+			}
+			trn = nil
+		case closing || value == "":
+		case trn != nil:
+			trn[tag] = value
+		case tag == "CURDEF":
+			statCurr = value
+		}
+	}
+
+	if statCurr != "" && !strings.EqualFold(statCurr, currencyCode) {
+		return nil, nil, fmt.Errorf("%w: statement currency %s differs from wallet currency %s", ErrInvalidFile, statCurr, currencyCode)
+	}
+	return entries, rowErrs, nil
+}
+
+func ofxEntry(trn map[string]string, currencyCode string, ids *fallbackIDs) (Entry, error) {
+	date, err := parseOFXDate(trn["DTPOSTED"])
+	if err != nil {
+		return Entry{}, err
+	}
+
+	rawAmount := trn["TRNAMT"]
+	if rawAmount == "" {
+		return Entry{}, fmt.Errorf("transaction %s has no TRNAMT", trn["FITID"])
+	}
+	amount, err := parseAmount(rawAmount, guessDecimalSeparator(rawAmount), currencyCode)
+	if err != nil {
+		return Entry{}, err
+	}
+	if amount == 0 {
+		return Entry{}, fmt.Errorf("transaction %s has zero amount", trn["FITID"])
+	}
+
+	description := trn["NAME"]
+	if memo := trn["MEMO"]; memo != "" && memo != description {
+		if description != "" {
+			description += " — "
+		}
+		description += memo
+	}
+
+	externalId := trn["FITID"]
+	if externalId == "" {
+		externalId = ids.next(date, amount, description)
+	}
+	return newEntry(date, amount, currencyCode, description, externalId), nil
+}
+
+// parseOFXDate понимает YYYYMMDD[HHMMSS[.XXX]][[-5:EST]]; часовой пояс банка отбрасывается,
+// как и у дат из CSV — важна дата операции по выписке
+func parseOFXDate(raw string) (time.Time, error) {
+	digits := raw
+	if i := strings.IndexAny(digits, ".["); i >= 0 {
+		digits = digits[:i]
+	}
+	layout := "20060102"
+	if len(digits) >= 14 {
+		digits, layout = digits[:14], "20060102150405"
+	} else if len(digits) > 8 {
+		digits = digits[:8]
+	}
+	date, err := time.Parse(layout, digits)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q", raw)
+	}
+	return date, nil
+}
@@ -0,0 +1,135 @@
+package importer
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+const ofxSGML = `OFXHEADER:100
+DATA:OFXSGML
+VERSION:102
+
+<OFX>
+<BANKMSGSRSV1><STMTTRNRS><STMTRS>
+<CURDEF>USD
+<BANKTRANLIST>
+<STMTTRN>
+<TRNTYPE>DEBIT
+<DTPOSTED>20260127120000.000[-5:EST]
+<TRNAMT>-12.50
+<FITID>1001
+<NAME>Coffee &amp; Co
+<MEMO>Card 1234
+</STMTTRN>
+<STMTTRN>
+<TRNTYPE>CREDIT
+<DTPOSTED>20260128
+<TRNAMT>1000,00
+<NAME>Salary
+</STMTTRN>
+</BANKTRANLIST>
+</STMTRS></STMTTRNRS></BANKMSGSRSV1>
+</OFX>
+`
+
+const ofxXML = `<?xml version="1.0" encoding="UTF-8"?>
+<?OFX OFXHEADER="200" VERSION="220"?>
+<OFX>
+  <BANKMSGSRSV1><STMTTRNRS><STMTRS>
+    <CURDEF>USD</CURDEF>
+    <BANKTRANLIST>
+      <STMTTRN>
+        <DTPOSTED>20260127</DTPOSTED>
+        <TRNAMT>-12.50</TRNAMT>
+        <FITID>1001</FITID>
+        <NAME>Coffee &amp; Co</NAME>
+        <MEMO>Card 1234</MEMO>
+      </STMTTRN>
+      <STMTTRN>
+        <DTPOSTED>20260128</DTPOSTED>
+        <TRNAMT>1000,00</TRNAMT>
+        <NAME>Salary</NAME>
+      </STMTTRN>
+    </BANKTRANLIST>
+  </STMTRS></STMTTRNRS></BANKMSGSRSV1>
+</OFX>
+`
+
+func TestParseOFX(t *testing.T) {
+	tests := []struct {
+		name      string
+		data      string
+		wantLines []int
+	}{
+		{"sgml", ofxSGML, []int{9, 17}},
+		{"xml", ofxXML, []int{7, 14}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, rowErrs, err := ParseOFX(strings.NewReader(tt.data), "USD")
+			if err != nil {
+				t.Fatalf("ParseOFX: %v", err)
+			}
+			if len(rowErrs) != 0 || len(entries) != 2 {
+				t.Fatalf("got %d entries and errors %+v, want 2 entries", len(entries), rowErrs)
+			}
+
+			coffee, salary := entries[0].Movement, entries[1].Movement
+			if coffee.Type != "expense" || coffee.Amount != 1250 || coffee.Description != "Coffee & Co — Card 1234" {
+				t.Errorf("first entry = %s %d %q", coffee.Type, coffee.Amount, coffee.Description)
+			}
+			// часовой пояс банка отбрасывается, дата остаётся той, что в выписке
+			if got := coffee.Date.Format(time.DateOnly); got != "2026-01-27" {
+				t.Errorf("first entry date = %s, want 2026-01-27", got)
+			}
+			if coffee.ExternalID == nil || *coffee.ExternalID != "1001" {
+				t.Errorf("first entry external id = %v, want FITID 1001", coffee.ExternalID)
+			}
+			if salary.Type != "income" || salary.Amount != 100000 {
+				t.Errorf("second entry = %s %d, want income 100000", salary.Type, salary.Amount)
+			}
+			// без FITID идентификатор строится из даты, суммы и описания
+			if salary.ExternalID == nil || !strings.HasPrefix(*salary.ExternalID, "sha1:") {
+				t.Errorf("second entry external id = %v, want fallback id", salary.ExternalID)
+			}
+			for i, e := range entries {
+				if e.Line != tt.wantLines[i] {
+					t.Errorf("entry %d at line %d, want %d", i, e.Line, tt.wantLines[i])
+				}
+			}
+		})
+	}
+}
+
+func TestParseOFXErrors(t *testing.T) {
+	if _, _, err := ParseOFX(strings.NewReader("not a statement"), "USD"); !errors.Is(err, ErrInvalidFile) {
+		t.Errorf("missing <OFX>: err = %v, want %v", err, ErrInvalidFile)
+	}
+	if _, _, err := ParseOFX(strings.NewReader(ofxSGML), "EUR"); !errors.Is(err, ErrInvalidFile) {
+		t.Errorf("currency mismatch: err = %v, want %v", err, ErrInvalidFile)
+	}
+
+	broken := "<OFX>\n<STMTTRN>\n<DTPOSTED>2026\n<TRNAMT>-1.00\n</STMTTRN>\n<STMTTRN>\n<DTPOSTED>20260127\n</STMTTRN>\n</OFX>"
+	entries, rowErrs, err := ParseOFX(strings.NewReader(broken), "USD")
+	if err != nil {
+		t.Fatalf("ParseOFX: %v", err)
+	}
+	if len(entries) != 0 || len(rowErrs) != 2 || rowErrs[0].Line != 2 || rowErrs[1].Line != 6 {
+		t.Errorf("got entries %+v, row errors %+v; want errors at lines 2 and 6", entries, rowErrs)
+	}
+}
+
+func TestParseOFXRowLimit(t *testing.T) {
+	var b strings.Builder
+	b.WriteString("<OFX>\n")
+	for i := 0; i < MaxRows+1; i++ {
+		fmt.Fprintf(&b, "<STMTTRN><DTPOSTED>20260127<TRNAMT>-1.00<FITID>%d</STMTTRN>\n", i)
+	}
+	b.WriteString("</OFX>\n")
+	if _, _, err := ParseOFX(strings.NewReader(b.String()), "USD"); !errors.Is(err, ErrInvalidFile) {
+		t.Fatalf("err = %v, want %v", err, ErrInvalidFile)
+	}
+}
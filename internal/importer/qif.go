@@ -0,0 +1,174 @@
+package importer
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/goonsorrow/finance-tracker-api/internal/models"
+)
+
+// Типы счетов QIF, операции которых импортируются. Остальные секции
+// (списки категорий, классов, шаблонов) пропускаются.
+var qifAccountTypes = map[string]bool{
+	"bank":  true,
+	"cash":  true,
+	"ccard": true,
+	"oth a": true,
+	"oth l": true,
+}
+
+// ParseQIF разбирает QIF банковского счёта или карты. dateFormat — формат Go для поля D,
+// по умолчанию американский 1/2/2006; год из двух цифр ("1/27'26") тоже понимается.
+// В QIF нет идентификаторов операций, поэтому для пропуска повторов используется
+// хеш даты, суммы и описания.
+func ParseQIF(r io.Reader, dateFormat, currencyCode string) ([]Entry, []models.ImportRowError, error) {
+	layouts := qifDateLayouts(dateFormat)
+
+	var (
+		entries []Entry
+		rowErrs []models.ImportRowError
+		ids     = newFallbackIDs()
+		fields  map[byte]string
+		start   int
+		active  = true // до заголовка !Type считаем файл банковским
+	)
+	flush := func() {
+		if fields == nil {
+			return
+		}
+		if active {
+			entry, err := qifEntry(fields, layouts, currencyCode, ids)
+			if err != nil {
+				rowErrs = append(rowErrs, models.ImportRowError{Line: start, Error: err.Error()})
+			} else {
+				entry.Line = start
+				entries = append(entries, entry)
+			}
+		}
+		fields = nil
+	}
+
+	scanner := bufio.NewScanner(skipBOM(r))
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimRight(scanner.Text(), "\r")
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+
+		if text[0] == '!' {
+			flush()
+			header := strings.ToLower(strings.TrimSpace(text[1:]))
+			if strings.HasPrefix(header, "option") || strings.HasPrefix(header, "clear") {
+				continue
+			}
+			accountType, isType := strings.CutPrefix(header, "type:")
+			if isType && accountType == "invst" {
+				return nil, nil, fmt.Errorf("%w: investment accounts are not supported", ErrInvalidFile)
+			}
+			active = isType && qifAccountTypes[accountType]
+			continue
+		}
+		if text[0] == '^' {
+			flush()
+			continue
+		}
+
+		if fields == nil {
+			fields = make(map[byte]string)
+			start = line
+			if len(entries)+len(rowErrs) >= MaxRows {
+				return nil, nil, fmt.Errorf("%w: more than %d transactions", ErrInvalidFile, MaxRows)
+			}
+		}
+		code, value := text[0], strings.TrimSpace(text[1:])
+		// строки разбивки (S, E, $) не разбираются — операция импортируется одной суммой
+		if _, ok := fields[code]; !ok {
+			fields[code] = value
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrInvalidFile, err)
+	}
+	flush()
+	return entries, rowErrs, nil
+}
+
+func qifEntry(fields map[byte]string, layouts []string, currencyCode string, ids *fallbackIDs) (Entry, error) {
+	date, err := parseQIFDate(fields['D'], layouts)
+	if err != nil {
+		return Entry{}, err
+	}
+
+	rawAmount := fields['T']
+	if rawAmount == "" {
+		rawAmount = fields['U']
+	}
+	if rawAmount == "" {
+		return Entry{}, fmt.Errorf("entry dated %s has no amount", fields['D'])
+	}
+	amount, err := parseAmount(rawAmount, guessDecimalSeparator(rawAmount), currencyCode)
+	if err != nil {
+		return Entry{}, err
+	}
+	if amount == 0 {
+		return Entry{}, fmt.Errorf("entry dated %s has zero amount", fields['D'])
+	}
+
+	description := fields['P']
+	if memo := fields['M']; memo != "" && memo != description {
+		if description != "" {
+			description += " — "
+		}
+		description += memo
+	}
+
+	entry := newEntry(date, amount, currencyCode, description, ids.next(date, amount, description))
+	entry.Category = qifCategory(fields['L'])
+	return entry, nil
+}
+
+// qifCategory: "[Счёт]" — перевод, категории нет; "Еда:Продукты/Класс" — берётся
+// подкатегория без класса
+func qifCategory(raw string) string {
+	if strings.HasPrefix(raw, "[") {
+		return ""
+	}
+	raw, _, _ = strings.Cut(raw, "/")
+	if i := strings.LastIndex(raw, ":"); i >= 0 {
+		raw = raw[i+1:]
+	}
+	return strings.TrimSpace(raw)
+}
+
+func qifDateLayouts(dateFormat string) []string {
+	if dateFormat == "" {
+		dateFormat = "1/2/2006"
+	}
+	layouts := []string{dateFormat}
+	if strings.Contains(dateFormat, "2006") {
+		layouts = append(layouts, strings.Replace(dateFormat, "2006", "06", 1))
+	}
+	return layouts
+}
+
+// parseQIFDate: Quicken пишет " 1/ 2'26" — апостроф перед годом из двух цифр и пробелы вместо нулей
+func parseQIFDate(raw string, layouts []string) (time.Time, error) {
+	// апостроф заменяется разделителем из формата
+	separator := "/"
+	if i := strings.IndexFunc(layouts[0], func(r rune) bool { return r < '0' || r > '9' }); i >= 0 {
+		separator = layouts[0][i : i+1]
+	}
+	value := strings.ReplaceAll(raw, " ", "")
+	value = strings.Replace(value, "'", separator, 1)
+	for _, layout := range layouts {
+		if date, err := time.Parse(layout, value); err == nil {
+			return date, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q", raw)
+}
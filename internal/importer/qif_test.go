@@ -0,0 +1,92 @@
+package importer
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseQIFDates(t *testing.T) {
+	tests := []struct {
+		name   string
+		format string
+		raw    string
+		want   time.Time
+	}{
+		{"us default", "", "1/27/2026", time.Date(2026, 1, 27, 0, 0, 0, 0, time.UTC)},
+		{"quicken apostrophe", "", " 1/ 2'26", time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)},
+		{"european", "02.01.2006", "27.01.2026", time.Date(2026, 1, 27, 0, 0, 0, 0, time.UTC)},
+		{"european two-digit year", "02.01.2006", "27.01'26", time.Date(2026, 1, 27, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := fmt.Sprintf("!Type:Bank\nD%s\nT-10.00\nPShop\n^\n", tt.raw)
+			entries, rowErrs, err := ParseQIF(strings.NewReader(data), tt.format, "USD")
+			if err != nil {
+				t.Fatalf("ParseQIF: %v", err)
+			}
+			if len(rowErrs) != 0 || len(entries) != 1 {
+				t.Fatalf("got %d entries and errors %+v, want 1 entry", len(entries), rowErrs)
+			}
+			if got := entries[0].Movement.Date; !got.Equal(tt.want) {
+				t.Errorf("date = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseQIF(t *testing.T) {
+	data := "!Option:AutoSwitch\n" +
+		"!Type:Cat\nNFood\n^\n" +
+		"!Type:Bank\n" +
+		"D1/27/2026\nT-1,234.50\nPMarket\nMWeekly\nLFood:Groceries/Home\n^\n" +
+		"D1/28/2026\nU500.00\nPTransfer\nL[Savings]\n^\n" +
+		"Dbad\nT-1.00\n^\n" +
+		"D1/29/2026\nT0\n^\n"
+
+	entries, rowErrs, err := ParseQIF(strings.NewReader(data), "", "USD")
+	if err != nil {
+		t.Fatalf("ParseQIF: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("got %d entries, want 2", len(entries))
+	}
+
+	market := entries[0]
+	if market.Movement.Type != "expense" || market.Movement.Amount != 123450 ||
+		market.Movement.Description != "Market — Weekly" || market.Category != "Groceries" || market.Line != 6 {
+		t.Errorf("first entry = %+v (category %q, line %d)", market.Movement, market.Category, market.Line)
+	}
+	if market.Movement.ExternalID == nil || !strings.HasPrefix(*market.Movement.ExternalID, "sha1:") {
+		t.Errorf("first entry external id = %v, want fallback id", market.Movement.ExternalID)
+	}
+
+	transfer := entries[1]
+	if transfer.Movement.Type != "income" || transfer.Movement.Amount != 50000 || transfer.Category != "" {
+		t.Errorf("second entry = %+v (category %q)", transfer.Movement, transfer.Category)
+	}
+
+	if len(rowErrs) != 2 || rowErrs[0].Line != 17 || rowErrs[1].Line != 20 {
+		t.Errorf("row errors = %+v, want lines 17 and 20", rowErrs)
+	}
+}
+
+func TestParseQIFInvestment(t *testing.T) {
+	_, _, err := ParseQIF(strings.NewReader("!Type:Invst\nD1/27/2026\nT1.00\n^\n"), "", "USD")
+	if !errors.Is(err, ErrInvalidFile) {
+		t.Fatalf("err = %v, want %v", err, ErrInvalidFile)
+	}
+}
+
+func TestParseQIFRowLimit(t *testing.T) {
+	var b strings.Builder
+	b.WriteString("!Type:Bank\n")
+	for i := 0; i < MaxRows+1; i++ {
+		fmt.Fprintf(&b, "D1/27/2026\nT-%d.00\n^\n", i+1)
+	}
+	if _, _, err := ParseQIF(strings.NewReader(b.String()), "", "USD"); !errors.Is(err, ErrInvalidFile) {
+		t.Fatalf("err = %v, want %v", err, ErrInvalidFile)
+	}
+}
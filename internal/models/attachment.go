@@ -0,0 +1,24 @@
+package models
+
+import (
+	"io"
+	"time"
+)
+
+type Attachment struct {
+	ID          int       `db:"id" json:"id" example:"1"`
+	UserID      int       `db:"user_id" json:"user_id" example:"10"`
+	MovementID  int       `db:"movement_id" json:"movement_id" example:"42"`
+	StorageKey  string    `db:"storage_key" json:"-"`
+	Filename    string    `db:"filename" json:"filename" example:"receipt.jpg"`
+	ContentType string    `db:"content_type" json:"content_type" example:"image/jpeg"`
+	Size        int64     `db:"size" json:"size" example:"183422"`
+	CreatedAt   time.Time `db:"created_at" json:"created_at"`
+}
+
+// Загружаемый файл: размер известен заранее, тело читается потоком
+type AttachmentUpload struct {
+	Filename string
+	Size     int64
+	Body     io.Reader
+}
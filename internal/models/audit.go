@@ -0,0 +1,75 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Сущности и действия журнала изменений
+const (
+	AuditWallet   = "wallet"
+	AuditMovement = "movement"
+	AuditCategory = "category"
+
+	AuditCreate  = "create"
+	AuditUpdate  = "update"
+	AuditDelete  = "delete"
+	AuditRestore = "restore"
+)
+
+const maxAuditLimit = 200
+
+type AuditEntry struct {
+	ID        int64     `db:"id" json:"id" example:"1"`
+	UserID    int       `db:"user_id" json:"user_id" example:"10"` // кто изменил
+	Entity    string    `db:"entity" json:"entity" example:"movement"`
+	EntityID  int       `db:"entity_id" json:"entity_id" example:"42"`
+	Action    string    `db:"action" json:"action" example:"update"`
+	Before    *string   `db:"before" json:"before" swaggertype:"object"` // JSON состояния до изменения
+	After     *string   `db:"after" json:"after" swaggertype:"object"`   // JSON состояния после изменения
+	RequestID *string   `db:"request_id" json:"request_id,omitempty" example:"3f2a9c1e0b7d4e65"`
+	IP        *string   `db:"ip" json:"ip,omitempty" example:"203.0.113.7"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
+
+// Состояния отдаются как вложенные объекты, а не строки
+func (e AuditEntry) MarshalJSON() ([]byte, error) {
+	type entry AuditEntry
+	return json.Marshal(struct {
+		entry
+		Before json.RawMessage `json:"before"`
+		After  json.RawMessage `json:"after"`
+	}{entry(e), rawJSON(e.Before), rawJSON(e.After)})
+}
+
+func rawJSON(s *string) json.RawMessage {
+	if s == nil {
+		return json.RawMessage("null")
+	}
+	return json.RawMessage(*s)
+}
+
+type AuditHistoryInput struct {
+	Limit  int `form:"limit" example:"50"`
+	Offset int `form:"offset" example:"0"`
+}
+
+func (i *AuditHistoryInput) Validate() error {
+	if i.Limit == 0 {
+		i.Limit = 50
+	}
+	if i.Limit < 0 || i.Limit > maxAuditLimit {
+		return fmt.Errorf("limit must be between 1 and %d", maxAuditLimit)
+	}
+	if i.Offset < 0 {
+		return errors.New("offset must not be negative")
+	}
+	return nil
+}
+
+// ValidAuditEntity — история есть только у кошельков, операций и категорий
+func ValidAuditEntity(entity string) bool {
+	return entity == AuditWallet || entity == AuditMovement || entity == AuditCategory
+}
@@ -0,0 +1,114 @@
+package models
+
+import "time"
+
+// Текущая версия формата архива. Восстановление принимает только её.
+const BackupVersion = 1
+
+// Архив всех данных пользователя. ID в архиве — исходные, по ним записи архива
+// ссылаются друг на друга; при восстановлении создаются новые. Суммы хранятся
+// десятичными строками в валюте кошелька или бюджета.
+type Backup struct {
+	Version    int               `json:"version" example:"1"`
+	ExportedAt time.Time         `json:"exported_at"`
+	Settings   BackupSettings    `json:"settings"`
+	Categories []BackupCategory  `json:"categories"`
+	Tags       []BackupTag       `json:"tags"`
+	Wallets    []BackupWallet    `json:"wallets"`
+	Transfers  []BackupTransfer  `json:"transfers"`
+	Movements  []BackupMovement  `json:"movements"`
+	Budgets    []BackupBudget    `json:"budgets"`
+	Recurring  []BackupRecurring `json:"recurring"`
+}
+
+type BackupSettings struct {
+	BaseCurrency string `json:"base_currency" example:"RUB"`
+}
+
+type BackupCategory struct {
+	ID      int     `json:"id" example:"14"`
+	Name    string  `json:"name" example:"Продукты"`
+	Type    string  `json:"type" example:"expense"`
+	Icon    *string `json:"icon"`
+	Builtin bool    `json:"builtin" example:"true"` // общая категория, при восстановлении ищется по названию и типу
+}
+
+type BackupTag struct {
+	ID    int     `json:"id" example:"2"`
+	Name  string  `json:"name" example:"отпуск 2026"`
+	Color *string `json:"color"`
+}
+
+type BackupWallet struct {
+	ID        int       `json:"id" example:"3"`
+	Name      string    `json:"name" example:"Main Wallet"`
+	Currency  string    `json:"currency" example:"RUB"`
+	Balance   string    `json:"balance" example:"15000.00"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type BackupTransfer struct {
+	ID           int       `json:"id" example:"2"`
+	FromWalletID int       `json:"from_wallet_id" example:"3"`
+	ToWalletID   int       `json:"to_wallet_id" example:"4"`
+	FromAmount   string    `json:"from_amount" example:"250.00"`
+	ToAmount     string    `json:"to_amount" example:"22500.00"`
+	Rate         float64   `json:"rate" example:"90"`
+	Description  string    `json:"description"`
+	Date         time.Time `json:"date"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type BackupMovement struct {
+	ID          int           `json:"id" example:"42"`
+	WalletID    int           `json:"wallet_id" example:"3"`
+	Type        string        `json:"type" example:"expense"`
+	Amount      string        `json:"amount" example:"150.50"`
+	CategoryID  *int          `json:"category_id" example:"14"`
+	Description string        `json:"description"`
+	Date        time.Time     `json:"date"`
+	TransferID  *int          `json:"transfer_id,omitempty"`
+	ExternalID  *string       `json:"external_id,omitempty"`
+	CreatedAt   time.Time     `json:"created_at"`
+	Splits      []BackupSplit `json:"splits,omitempty"`
+	Tags        []int         `json:"tags,omitempty"` // ID меток из архива
+}
+
+type BackupSplit struct {
+	CategoryID int    `json:"category_id" example:"14"`
+	Amount     string `json:"amount" example:"100.00"`
+	Note       string `json:"note"`
+}
+
+type BackupBudget struct {
+	CategoryID int    `json:"category_id" example:"14"`
+	Amount     string `json:"amount" example:"30000.00"`
+	Currency   string `json:"currency" example:"RUB"`
+	Rollover   bool   `json:"rollover"`
+	StartMonth string `json:"start_month" example:"2026-01"`
+}
+
+type BackupRecurring struct {
+	WalletID    int        `json:"wallet_id" example:"3"`
+	Type        string     `json:"type" example:"expense"`
+	Amount      string     `json:"amount" example:"45000.00"`
+	CategoryID  *int       `json:"category_id"`
+	Description string     `json:"description"`
+	Frequency   string     `json:"frequency" example:"monthly"`
+	Interval    int        `json:"interval" example:"1"`
+	StartDate   time.Time  `json:"start_date"`
+	EndDate     *time.Time `json:"end_date"`
+	NextDate    time.Time  `json:"next_date"`
+	Paused      bool       `json:"paused"`
+}
+
+// Сколько записей создано при восстановлении
+type RestoreResult struct {
+	Categories int `json:"categories"`
+	Tags       int `json:"tags"`
+	Wallets    int `json:"wallets"`
+	Transfers  int `json:"transfers"`
+	Movements  int `json:"movements"`
+	Budgets    int `json:"budgets"`
+	Recurring  int `json:"recurring"`
+}
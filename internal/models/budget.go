@@ -0,0 +1,112 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/goonsorrow/finance-tracker-api/internal/currency"
+)
+
+const BudgetMonthLayout = "2006-01"
+
+// Месячный лимит расходов по категории. Лимит действует с StartMonth, при Rollover
+// неизрасходованный остаток переносится на следующий месяц.
+type Budget struct {
+	ID         int       `db:"id" json:"id" example:"1"`
+	UserID     int       `db:"user_id" json:"user_id" example:"10"`
+	CategoryID int       `db:"category_id" json:"category_id" example:"5"`
+	Category   string    `db:"category" json:"category" example:"Продукты"`
+	Amount     int64     `db:"amount" json:"amount" swaggertype:"string" example:"30000.00"`
+	Currency   string    `db:"currency" json:"currency" example:"RUB"`
+	Rollover   bool      `db:"rollover" json:"rollover" example:"true"`
+	StartMonth time.Time `db:"start_month" json:"start_month" swaggertype:"string" example:"2026-01"`
+	CreatedAt  time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt  time.Time `db:"updated_at" json:"updated_at"`
+}
+
+func (b Budget) MarshalJSON() ([]byte, error) {
+	type budget Budget
+	return json.Marshal(struct {
+		budget
+		Amount     string `json:"amount"`
+		StartMonth string `json:"start_month"`
+	}{budget(b), currency.FormatAmount(b.Amount, b.Currency), b.StartMonth.Format(BudgetMonthLayout)})
+}
+
+type CreateBudgetInput struct {
+	CategoryID int              `json:"category_id" binding:"required,gt=0" example:"5"`
+	Amount     currency.Decimal `json:"amount" binding:"required" swaggertype:"string" example:"30000.00"`
+	Currency   string           `json:"currency" binding:"omitempty,len=3" example:"RUB"` // по умолчанию базовая валюта пользователя
+	Rollover   bool             `json:"rollover" example:"true"`
+	StartMonth string           `json:"start_month" example:"2026-01"` // по умолчанию текущий месяц
+}
+
+type UpdateBudgetInput struct {
+	Amount   *currency.Decimal `json:"amount" swaggertype:"string" example:"35000.00"`
+	Rollover *bool             `json:"rollover" example:"false"`
+}
+
+type UpdateBudgetData struct {
+	Amount   *int64
+	Rollover *bool
+}
+
+func (b CreateBudgetInput) Validate() error {
+	if !isPositiveDecimal(&b.Amount) {
+		return errors.New("amount must be a decimal number greater than 0")
+	}
+	if b.StartMonth != "" {
+		if _, err := time.Parse(BudgetMonthLayout, b.StartMonth); err != nil {
+			return errors.New("start_month must be in YYYY-MM format")
+		}
+	}
+	return nil
+}
+
+func (b UpdateBudgetInput) Validate() error {
+	if b.Amount == nil && b.Rollover == nil {
+		return errors.New("at least one field must be provided for update")
+	}
+	if b.Amount != nil && !isPositiveDecimal(b.Amount) {
+		return errors.New("amount must be a decimal number greater than 0")
+	}
+	return nil
+}
+
+// Месяц, за который считается исполнение бюджетов; по умолчанию текущий
+type BudgetPeriodInput struct {
+	Month string `form:"month" example:"2026-03"`
+}
+
+func (p BudgetPeriodInput) Validate() error {
+	if p.Month != "" {
+		if _, err := time.Parse(BudgetMonthLayout, p.Month); err != nil {
+			return errors.New("month must be in YYYY-MM format")
+		}
+	}
+	return nil
+}
+
+// Расходы по категории за месяц в валюте кошельков
+type CategorySpend struct {
+	Month    time.Time `db:"month"`
+	Currency string    `db:"currency"`
+	Spent    int64     `db:"spent"`
+}
+
+// Исполнение бюджета за месяц, все суммы в валюте бюджета
+type BudgetStatus struct {
+	BudgetID     int            `json:"budget_id" example:"1"`
+	CategoryID   int            `json:"category_id" example:"5"`
+	Category     string         `json:"category" example:"Продукты"`
+	Month        string         `json:"month" example:"2026-03"`
+	Limit        currency.Money `json:"limit" swaggertype:"string" example:"30000.00"`
+	CarriedOver  currency.Money `json:"carried_over" swaggertype:"string" example:"4500.00"` // перенесено с прошлых месяцев
+	Available    currency.Money `json:"available" swaggertype:"string" example:"34500.00"`   // limit + carried_over
+	Spent        currency.Money `json:"spent" swaggertype:"string" example:"21000.00"`
+	Remaining    currency.Money `json:"remaining" swaggertype:"string" example:"13500.00"` // отрицательный при перерасходе
+	Percent      float64        `json:"percent" example:"60.9"`                            // spent / available
+	Overspent    bool           `json:"overspent" example:"false"`
+	MissingRates []string       `json:"missing_rates,omitempty"` // расходы в этих валютах не учтены
+}
@@ -6,14 +6,16 @@ import (
 )
 
 type Category struct {
-	ID         int       `db:"id" json:"id" example:"1"`
-	UserID     *int      `db:"user_id" json:"user_id" binding:"omitempty" example:"10"`
-	Name       string    `db:"name" json:"name" binding:"required" example:"Groceries"`
-	Type       string    `db:"type" json:"type" example:"expense"` // "income" or "expense"
-	Icon       *string   `db:"icon" json:"icon" binding:"omitempty" example:"🛒"`
-	CreatedAt  time.Time `db:"created_at" json:"created_at"`
-	UpdatedAt  time.Time `db:"updated_at" json:"updated_at"`
-	UsageCount int       `db:"usage_count" json:"usage_count" example:"5"`
+	ID         int        `db:"id" json:"id" example:"1"`
+	UserID     *int       `db:"user_id" json:"user_id" binding:"omitempty" example:"10"`
+	Name       string     `db:"name" json:"name" binding:"required" example:"Groceries"`
+	Type       string     `db:"type" json:"type" example:"expense"` // "income" or "expense"
+	Icon       *string    `db:"icon" json:"icon" binding:"omitempty" example:"🛒"`
+	CreatedAt  time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt  time.Time  `db:"updated_at" json:"updated_at"`
+	UsageCount int        `db:"usage_count" json:"usage_count" example:"5"`
+	DeletedAt  *time.Time `db:"deleted_at" json:"deleted_at,omitempty"` // заполнено только у категорий в корзине
+	Version    int        `db:"version" json:"version" example:"1"`     // растёт при каждом изменении, отдаётся как ETag
 }
 
 type CreateCategoryInput struct {
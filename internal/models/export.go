@@ -0,0 +1,43 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/goonsorrow/finance-tracker-api/internal/currency"
+)
+
+// Query-параметры выгрузки операций
+type MovementExportInput struct {
+	MovementConditions
+	WalletID *int   `form:"wallet_id" binding:"omitempty,gt=0" example:"1"`
+	Currency string `form:"currency" binding:"omitempty,len=3" example:"USD"` // обязателен для min_amount/max_amount без wallet_id
+	Format   string `form:"format" binding:"omitempty,oneof=csv ndjson xlsx" example:"csv"`
+}
+
+func (f MovementExportInput) Validate() error {
+	if err := f.MovementConditions.Validate(); err != nil {
+		return err
+	}
+	if f.HasAmountRange() && f.WalletID == nil && f.Currency == "" {
+		return errors.New("currency is required for amount filters without wallet_id")
+	}
+	return nil
+}
+
+// Операция для выгрузки вместе с названиями кошелька и категории
+type ExportMovement struct {
+	Movement
+	WalletName   string  `db:"wallet_name"`
+	CategoryName *string `db:"category_name"`
+}
+
+func (e ExportMovement) MarshalJSON() ([]byte, error) {
+	type movement Movement
+	return json.Marshal(struct {
+		movement
+		Amount       string  `json:"amount"`
+		WalletName   string  `json:"wallet_name"`
+		CategoryName *string `json:"category_name"`
+	}{movement(e.Movement), currency.FormatAmount(e.Amount, e.Currency), e.WalletName, e.CategoryName})
+}
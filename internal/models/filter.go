@@ -0,0 +1,154 @@
+package models
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/goonsorrow/finance-tracker-api/internal/currency"
+)
+
+const (
+	DefaultMovementLimit = 50
+	MaxMovementLimit     = 100
+)
+
+type MovementFilter struct {
+	WalletID   int
+	Type       string // "income", "expense", "initial" или "transfer" (обе ноги перевода)
+	CategoryID *int   // для фильтрации
+	TagID      *int
+	StartDate  time.Time
+	EndDate    time.Time // не включительно
+	Currency   string    // только кошельки в этой валюте
+	MinAmount  *int64    // в минимальных единицах валюты
+	MaxAmount  *int64
+	Limit      int
+	Offset     int
+	SortBy     string // "date", "amount", "created_at"
+	SortOrder  string // "asc", "desc"
+}
+
+// Общие условия отбора операций
+type MovementConditions struct {
+	Type       string            `form:"type" binding:"omitempty,oneof=income expense initial transfer" example:"expense"`
+	CategoryID *int              `form:"category_id" binding:"omitempty,gt=0" example:"1"`
+	TagID      *int              `form:"tag_id" binding:"omitempty,gt=0" example:"2"`
+	StartDate  *time.Time        `form:"from" time_format:"2006-01-02" example:"2026-01-01"`
+	EndDate    *time.Time        `form:"to" time_format:"2006-01-02" example:"2026-01-31"`
+	MinAmount  *currency.Decimal `form:"min_amount" swaggertype:"string" example:"10.00"`
+	MaxAmount  *currency.Decimal `form:"max_amount" swaggertype:"string" example:"500.00"`
+}
+
+func (f MovementConditions) Validate() error {
+	if f.StartDate != nil && f.EndDate != nil && f.EndDate.Before(*f.StartDate) {
+		return errors.New("'to' must not be before 'from'")
+	}
+	for _, amount := range []*currency.Decimal{f.MinAmount, f.MaxAmount} {
+		if amount == nil {
+			continue
+		}
+		if sign, err := amount.Sign(); err != nil || sign < 0 {
+			return errors.New("min_amount and max_amount must be non-negative decimal numbers")
+		}
+	}
+	return nil
+}
+
+func (f MovementConditions) HasAmountRange() bool {
+	return f.MinAmount != nil || f.MaxAmount != nil
+}
+
+// Query-параметры списка операций кошелька
+type MovementFilterInput struct {
+	MovementConditions
+	SortBy    string `form:"sort_by" binding:"omitempty,oneof=date amount created_at" example:"date"`
+	SortOrder string `form:"sort_order" binding:"omitempty,oneof=asc desc" example:"desc"`
+	Limit     int    `form:"limit" binding:"omitempty,min=1,max=100" example:"50"`
+	Offset    int    `form:"offset" binding:"omitempty,min=0" example:"0"`
+}
+
+func (f MovementFilterInput) Validate() error {
+	if err := f.MovementConditions.Validate(); err != nil {
+		return err
+	}
+	if f.Limit < 0 || f.Limit > MaxMovementLimit {
+		return errors.New("limit must be between 1 and 100")
+	}
+	if f.Offset < 0 {
+		return errors.New("offset must not be negative")
+	}
+	return nil
+}
+
+// Query-параметры ленты операций по всем кошелькам (keyset-пагинация)
+type MovementCursorInput struct {
+	MovementConditions
+	WalletID  *int   `form:"wallet_id" binding:"omitempty,gt=0" example:"1"`
+	Currency  string `form:"currency" binding:"omitempty,len=3" example:"USD"` // обязателен для min_amount/max_amount без wallet_id
+	SortOrder string `form:"sort_order" binding:"omitempty,oneof=asc desc" example:"desc"`
+	Cursor    string `form:"cursor" example:"eyJkIjoiMjAyNi0wMS0yN1QxMjowMDowMFoiLCJpIjo0Mn0"`
+	Limit     int    `form:"limit" binding:"omitempty,min=1,max=100" example:"50"`
+}
+
+func (f MovementCursorInput) Validate() error {
+	if err := f.MovementConditions.Validate(); err != nil {
+		return err
+	}
+	// суммы в разных валютах несравнимы, поэтому диапазон сумм по всем кошелькам задаётся в одной валюте
+	if f.HasAmountRange() && f.WalletID == nil && f.Currency == "" {
+		return errors.New("currency is required for amount filters without wallet_id")
+	}
+	if f.Limit < 0 || f.Limit > MaxMovementLimit {
+		return errors.New("limit must be between 1 and 100")
+	}
+	return nil
+}
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Позиция в ленте: последняя отданная операция по (date, id)
+type MovementCursor struct {
+	Date time.Time `json:"d"`
+	ID   int       `json:"i"`
+}
+
+func (c MovementCursor) Encode() string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func DecodeMovementCursor(s string) (MovementCursor, error) {
+	var cursor MovementCursor
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return cursor, ErrInvalidCursor
+	}
+	if err := json.Unmarshal(raw, &cursor); err != nil || cursor.ID <= 0 || cursor.Date.IsZero() {
+		return cursor, ErrInvalidCursor
+	}
+	return cursor, nil
+}
+
+type MovementPage struct {
+	Items  []Movement `json:"movements"`
+	Total  int        `json:"total"`
+	Limit  int        `json:"limit"`
+	Offset int        `json:"offset"`
+}
+
+type MovementCursorPage struct {
+	Items      []Movement `json:"movements"`
+	NextCursor string     `json:"next_cursor,omitempty"`
+	HasMore    bool       `json:"has_more"`
+}
+
+type WalletFilter struct {
+	UserID    int
+	Currency  string
+	Limit     int
+	Offset    int
+	SortBy    string // "name", "balance", "created_at"
+	SortOrder string // "asc", "desc"
+}
@@ -0,0 +1,11 @@
+package models
+
+// Запрос, выполненный с заголовком Idempotency-Key. Пока запрос выполняется, Completed = false
+// и ответа ещё нет
+type IdempotencyRecord struct {
+	RequestHash string `json:"request_hash"`
+	Completed   bool   `json:"completed"`
+	StatusCode  int    `json:"status_code,omitempty"`
+	ContentType string `json:"content_type,omitempty"`
+	Body        []byte `json:"body,omitempty"`
+}
@@ -0,0 +1,113 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+	"unicode/utf8"
+
+	"github.com/goonsorrow/finance-tracker-api/internal/currency"
+)
+
+// Знак суммы в выписке
+const (
+	SignSigned   = "signed"   // отрицательная сумма — расход (по умолчанию)
+	SignInverted = "inverted" // отрицательная сумма — доход, как в выписках по кредитным картам
+	SignSplit    = "split"    // расход и доход в отдельных колонках debit/credit
+)
+
+// Сопоставление колонок CSV полям операции. Колонка задаётся именем из заголовка
+// или номером, начиная с 1.
+type CSVMapping struct {
+	Delimiter         string `json:"delimiter" example:";"` // по умолчанию ","
+	HasHeader         bool   `json:"has_header" example:"true"`
+	SkipRows          int    `json:"skip_rows" example:"0"` // строки до заголовка или данных
+	Date              string `json:"date" binding:"required" example:"Дата операции"`
+	DateFormat        string `json:"date_format" example:"02.01.2006"` // формат Go, по умолчанию 2006-01-02
+	Amount            string `json:"amount" example:"Сумма"`
+	Debit             string `json:"debit" example:"Списание"`
+	Credit            string `json:"credit" example:"Зачисление"`
+	Sign              string `json:"sign" example:"signed"` // signed | inverted | split
+	DecimalSeparator  string `json:"decimal_separator" example:","`
+	Description       string `json:"description" example:"Описание"`
+	Category          string `json:"category" example:"Категория"`
+	DefaultCategoryID *int   `json:"default_category_id" example:"1"` // для строк без категории или с неизвестной
+}
+
+func (m CSVMapping) Validate() error {
+	if m.Date == "" {
+		return errors.New("date column is required")
+	}
+	if m.Delimiter != "" && utf8.RuneCountInString(m.Delimiter) != 1 {
+		return errors.New("delimiter must be a single character")
+	}
+	if m.DecimalSeparator != "" && m.DecimalSeparator != "." && m.DecimalSeparator != "," {
+		return errors.New("decimal_separator must be '.' or ','")
+	}
+	if m.SkipRows < 0 {
+		return errors.New("skip_rows must not be negative")
+	}
+	switch m.Sign {
+	case "", SignSigned, SignInverted:
+		if m.Amount == "" {
+			return errors.New("amount column is required")
+		}
+	case SignSplit:
+		if m.Debit == "" || m.Credit == "" {
+			return errors.New("debit and credit columns are required for split sign")
+		}
+	default:
+		return errors.New("sign must be 'signed', 'inverted' or 'split'")
+	}
+	return nil
+}
+
+// Режим импорта, query-параметры
+type ImportOptions struct {
+	DryRun          bool `form:"dry_run" example:"true"`
+	AllowDuplicates bool `form:"allow_duplicates" example:"false"` // записать строки, похожие на уже существующие операции
+}
+
+// Параметры импорта; для CSV сопоставление колонок передаётся отдельно
+type StatementImportInput struct {
+	Format            string `form:"format" binding:"omitempty,oneof=csv ofx qfx qif" example:"ofx"` // по умолчанию по расширению файла
+	DateFormat        string `form:"date_format" example:"02.01.2006"`                               // формат дат QIF, по умолчанию 1/2/2006
+	DefaultCategoryID *int   `form:"default_category_id" example:"1"`                                // в OFX категорий нет
+}
+
+// Разобранная строка выписки
+type ImportRow struct {
+	Line        int        `json:"line" example:"2"`
+	Date        time.Time  `json:"date"`
+	Type        string     `json:"type" example:"expense"`
+	Amount      int64      `json:"amount" swaggertype:"string" example:"150.50"`
+	Currency    string     `json:"currency" example:"RUB"`
+	Description string     `json:"description" example:"PYATEROCHKA 1234"`
+	Category    string     `json:"category,omitempty" example:"Продукты"` // как в файле
+	CategoryID  *int       `json:"category_id" example:"1"`
+	ExternalID  *string    `json:"external_id,omitempty" example:"202601270001"` // FITID из OFX или хеш записи QIF
+	MovementID  *int       `json:"movement_id,omitempty" example:"42"`           // только после записи
+	Duplicates  []Movement `json:"duplicates,omitempty"`                         // похожие операции, уже записанные в кошелёк
+}
+
+func (r ImportRow) MarshalJSON() ([]byte, error) {
+	type row ImportRow
+	return json.Marshal(struct {
+		row
+		Amount string `json:"amount"`
+	}{row(r), currency.FormatAmount(r.Amount, r.Currency)})
+}
+
+type ImportRowError struct {
+	Line  int    `json:"line" example:"7"`
+	Error string `json:"error" example:"invalid date \"31.02.2026\""`
+}
+
+type ImportResult struct {
+	DryRun       bool             `json:"dry_run"`
+	Rows         []ImportRow      `json:"rows"`
+	Errors       []ImportRowError `json:"errors"`
+	Skipped      []ImportRow      `json:"skipped"` // уже импортированы раньше
+	Created      int              `json:"created"`
+	BalanceDelta currency.Money   `json:"balance_delta" swaggertype:"string" example:"-1250.00"`
+}
@@ -0,0 +1,15 @@
+package models
+
+// Расхождение сохранённого баланса кошелька с суммой его операций
+type BalanceMismatch struct {
+	WalletID int    `db:"wallet_id" json:"wallet_id"`
+	UserID   int    `db:"user_id" json:"user_id"`
+	Currency string `db:"currency" json:"currency"`
+	Stored   int64  `db:"stored" json:"stored"`
+	Computed int64  `db:"computed" json:"computed"`
+}
+
+// Diff показывает, на сколько сохранённый баланс отличается от пересчитанного
+func (m BalanceMismatch) Diff() int64 {
+	return m.Stored - m.Computed
+}
@@ -0,0 +1,40 @@
+package models
+
+// Виды счетов двойной записи. Счёт кошелька один на кошелёк, остальные заводятся
+// на пользователя в каждой валюте
+const (
+	AccountWallet   = "wallet"
+	AccountIncome   = "income"
+	AccountExpense  = "expense"
+	AccountEquity   = "equity"   // начальные остатки кошельков
+	AccountTransfer = "transfer" // транзитный счёт переводов, между валютами на нём остаётся курсовая разница
+)
+
+// Проводка по счёту в минимальных единицах валюты; приход на счёт положительный
+type Posting struct {
+	AccountID int    `db:"account_id"`
+	Amount    int64  `db:"amount"`
+	Currency  string `db:"currency"`
+}
+
+// Запись журнала по одной операции
+type JournalEntry struct {
+	ID         int64     `db:"id"`
+	UserID     int       `db:"user_id"`
+	MovementID int       `db:"movement_id"`
+	Postings   []Posting `db:"-"`
+}
+
+// Balanced — суммы проводок в каждой валюте дают ноль
+func (e JournalEntry) Balanced() bool {
+	sums := make(map[string]int64, 1)
+	for _, p := range e.Postings {
+		sums[p.Currency] += p.Amount
+	}
+	for _, sum := range sums {
+		if sum != 0 {
+			return false
+		}
+	}
+	return true
+}
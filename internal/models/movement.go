@@ -1,39 +1,102 @@
 package models
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
 	"time"
+
+	"github.com/goonsorrow/finance-tracker-api/internal/currency"
 )
 
+// Сколько строк может быть в разбивке одной операции
+const MaxMovementSplits = 50
+
 type Movement struct {
-	ID          int       `db:"id" json:"id"`
-	WalletID    int       `db:"wallet_id" json:"wallet_id"`
-	UserId      int       `db:"user_id" json:"user_id"`
-	Type        string    `db:"type" json:"type"` // "income" или "expense" или "initial"(только при создании кошелька с первоначальным балансом)
-	Amount      int64     `db:"amount" json:"amount"`
-	CategoryID  *int      `db:"category_id" json:"category_id"`
-	Description string    `db:"description" json:"description"`
-	Date        time.Time `db:"date" json:"date"`
-	CreatedAt   time.Time `db:"created_at" json:"created_at"`
-	UpdatedAt   time.Time `db:"updated_at" json:"updated_at"`
+	ID          int        `db:"id" json:"id"`
+	WalletID    int        `db:"wallet_id" json:"wallet_id"`
+	UserId      int        `db:"user_id" json:"user_id"`
+	Type        string     `db:"type" json:"type"`                                           // "income" или "expense" или "initial"(только при создании кошелька с первоначальным балансом), "transfer_out"/"transfer_in" — ноги перевода
+	Amount      int64      `db:"amount" json:"amount" swaggertype:"string" example:"150.50"` // в минимальных единицах валюты кошелька
+	Currency    string     `db:"currency" json:"currency" example:"USD"`                     // валюта кошелька
+	CategoryID  *int       `db:"category_id" json:"category_id"`
+	Description string     `db:"description" json:"description"`
+	Date        time.Time  `db:"date" json:"date"`
+	TransferID  *int       `db:"transfer_id" json:"transfer_id,omitempty"`
+	ExternalID  *string    `db:"external_id" json:"external_id,omitempty"` // идентификатор операции в выписке банка
+	CreatedAt   time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt   time.Time  `db:"updated_at" json:"updated_at"`
+	DeletedAt   *time.Time `db:"deleted_at" json:"deleted_at,omitempty"` // заполнено только у операций в корзине
+	Version     int        `db:"version" json:"version" example:"1"`     // растёт при каждом изменении, отдаётся как ETag
+	// разбивка суммы по категориям; если есть, отчёты по категориям считаются по ней
+	Splits []MovementSplit `db:"-" json:"splits,omitempty"`
+	Tags   []Tag           `db:"-" json:"tags,omitempty"`
+}
+
+// В JSON сумма отдаётся десятичной строкой в валюте кошелька
+func (m Movement) MarshalJSON() ([]byte, error) {
+	type movement Movement
+	return json.Marshal(struct {
+		movement
+		Amount string `json:"amount"`
+	}{movement(m), currency.FormatAmount(m.Amount, m.Currency)})
+}
+
+// Часть суммы операции, отнесённая к своей категории
+type MovementSplit struct {
+	ID         int    `db:"id" json:"id" example:"1"`
+	MovementID int    `db:"movement_id" json:"movement_id" example:"42"`
+	CategoryID int    `db:"category_id" json:"category_id" example:"3"`
+	Amount     int64  `db:"amount" json:"amount" swaggertype:"string" example:"100.00"`
+	Currency   string `db:"currency" json:"-"` // валюта кошелька операции
+	Note       string `db:"note" json:"note" example:"Бытовая химия"`
+}
+
+func (s MovementSplit) MarshalJSON() ([]byte, error) {
+	type split MovementSplit
+	return json.Marshal(struct {
+		split
+		Amount string `json:"amount"`
+	}{split(s), currency.FormatAmount(s.Amount, s.Currency)})
+}
+
+// Строка разбивки во входных данных
+type SplitInput struct {
+	CategoryID int              `json:"category_id" binding:"required" example:"3"`
+	Amount     currency.Decimal `json:"amount" binding:"required" swaggertype:"string" example:"100.00"`
+	Note       string           `json:"note" binding:"max=255" example:"Бытовая химия"`
+}
+
+// Найденная операция, похожая на index-ю из проверяемых
+type DuplicateMatch struct {
+	Index int `db:"idx"`
+	Movement
 }
 
 // Input для создания записи
 type CreateMovementInput struct {
-	Type        string    `json:"type" binding:"required,oneof=income expense initial" example:"expense"`
-	Amount      float64   `json:"amount" binding:"required,gt=0" example:"150.50"`
-	CategoryID  *int      `json:"category" binding:"required" example:"1"`
-	Description string    `json:"description" example:"Grocery shopping"`
-	Date        time.Time `json:"date" binding:"required" example:"2026-01-27T12:00:00Z"`
+	Type        string           `json:"type" binding:"required,oneof=income expense initial" example:"expense"`
+	Amount      currency.Decimal `json:"amount" binding:"required" swaggertype:"string" example:"150.50"`
+	CategoryID  *int             `json:"category" example:"1"` // при разбивке можно не указывать, берётся категория первой строки
+	Description string           `json:"description" example:"Grocery shopping"`
+	Date        time.Time        `json:"date" binding:"required" example:"2026-01-27T12:00:00Z"`
+	Splits      []SplitInput     `json:"splits" binding:"omitempty,dive"`
+	TagIDs      []int            `json:"tags" example:"1,2"`
+	// создать, даже если похожая операция уже есть
+	AllowDuplicate bool `json:"allow_duplicate" example:"false"`
 }
 
 // Input для обновления операции
 type UpdateMovementInput struct {
-	Type        *string    `json:"type" example:"income"`
-	Amount      *float64   `json:"amount" binding:"omitempty,gt=0" example:"200.00"`
-	CategoryID  *int       `json:"category_id" example:"3"`
-	Description *string    `json:"description" example:"Updated description"`
-	Date        *time.Time `json:"date" example:"2026-01-28T15:00:00Z"`
+	Type        *string           `json:"type" example:"income"`
+	Amount      *currency.Decimal `json:"amount" swaggertype:"string" example:"200.00"`
+	CategoryID  *int              `json:"category_id" example:"3"`
+	Description *string           `json:"description" example:"Updated description"`
+	Date        *time.Time        `json:"date" example:"2026-01-28T15:00:00Z"`
+	// новая разбивка целиком; пустой список убирает разбивку, без поля — остаётся прежней
+	Splits *[]SplitInput `json:"splits" binding:"omitempty,dive"`
+	// новый набор меток целиком; пустой список снимает все
+	TagIDs *[]int `json:"tags" example:"1,2"`
 }
 
 type UpdateMovementData struct {
@@ -42,6 +105,7 @@ type UpdateMovementData struct {
 	CategoryID  *int       `json:"category_id"`
 	Description *string    `json:"description"`
 	Date        *time.Time `json:"date"`
+	Version     *int       `json:"-"` // если задана, изменение проходит только при совпадении версии
 }
 
 // Валидация
@@ -49,13 +113,19 @@ func (m CreateMovementInput) Validate() error {
 	if m.Type != "income" && m.Type != "expense" && m.Type != "initial" {
 		return errors.New("type must be 'income' or 'expense'")
 	}
-	if m.Amount <= 0 {
-		return errors.New("amount must be greater than 0")
+	if !isPositiveDecimal(&m.Amount) {
+		return errors.New("amount must be a decimal number greater than 0")
 	}
-	if m.CategoryID == nil {
+	if m.CategoryID == nil && len(m.Splits) == 0 {
 		return errors.New("category is required")
 	}
-	return nil
+	if len(m.Splits) > 0 && m.Type == "initial" {
+		return errors.New("initial balance cannot be split")
+	}
+	if err := validateTagIDs(m.TagIDs); err != nil {
+		return err
+	}
+	return validateSplits(m.Splits)
 }
 
 func (m UpdateMovementInput) Validate() error {
@@ -63,8 +133,35 @@ func (m UpdateMovementInput) Validate() error {
 		return errors.New("type must be 'income' or 'expense'")
 	}
 
-	if m.Amount != nil && *m.Amount <= 0 {
-		return errors.New("amount must be greater than 0")
+	if m.Amount != nil && !isPositiveDecimal(m.Amount) {
+		return errors.New("amount must be a decimal number greater than 0")
+	}
+	if m.TagIDs != nil {
+		if err := validateTagIDs(*m.TagIDs); err != nil {
+			return err
+		}
+	}
+	if m.Splits != nil {
+		return validateSplits(*m.Splits)
+	}
+	return nil
+}
+
+// Сверка суммы строк с суммой операции требует валюты кошелька и делается в сервисе
+func validateSplits(splits []SplitInput) error {
+	if len(splits) == 1 {
+		return errors.New("splits must have at least two lines")
+	}
+	if len(splits) > MaxMovementSplits {
+		return fmt.Errorf("splits must have at most %d lines", MaxMovementSplits)
+	}
+	for i := range splits {
+		if splits[i].CategoryID <= 0 {
+			return fmt.Errorf("split %d: category_id is required", i+1)
+		}
+		if !isPositiveDecimal(&splits[i].Amount) {
+			return fmt.Errorf("split %d: amount must be a decimal number greater than 0", i+1)
+		}
 	}
 	return nil
 }
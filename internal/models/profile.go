@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+
+	"github.com/goonsorrow/finance-tracker-api/internal/currency"
+)
+
+// Сумма всех кошельков в одной валюте и её пересчёт в базовую валюту пользователя
+type CurrencyBalance struct {
+	Currency    string          `json:"currency" example:"USD"`
+	Balance     currency.Money  `json:"balance" swaggertype:"string" example:"1500.00"`
+	WalletCount int             `json:"wallet_count" example:"2"`
+	Converted   *currency.Money `json:"converted" swaggertype:"string" example:"135750.00"`
+	Rate        *float64        `json:"rate" example:"90.5"`
+	RateDate    *time.Time      `json:"rate_date"`
+}
+
+type Profile struct {
+	User         User              `json:"user"`
+	BaseCurrency string            `json:"base_currency" example:"RUB"`
+	Balances     []CurrencyBalance `json:"balances"`
+	Total        currency.Money    `json:"total" swaggertype:"string" example:"135750.00"` // в базовой валюте, без валют с неизвестным курсом
+	RateDate     *time.Time        `json:"rate_date"`                                      // самая ранняя дата курса, использованного в пересчёте
+	MissingRates []string          `json:"missing_rates,omitempty"`                        // валюты, для которых курс не найден
+}
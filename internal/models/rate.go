@@ -0,0 +1,21 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/goonsorrow/finance-tracker-api/internal/currency"
+)
+
+type RateLookupInput struct {
+	Base  string     `form:"base" binding:"required,len=3" example:"USD"`
+	Quote string     `form:"quote" binding:"required,len=3" example:"RUB"`
+	Date  *time.Time `form:"date" time_format:"2006-01-02" example:"2026-01-27"`
+}
+
+func (r RateLookupInput) Validate() error {
+	if !currency.IsSupported(r.Base) || !currency.IsSupported(r.Quote) {
+		return fmt.Errorf("unsupported currency pair %s/%s, supported: %v", r.Base, r.Quote, currency.SupportedCurrencies)
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/goonsorrow/finance-tracker-api/internal/currency"
+)
+
+// Шаблон повторяющейся операции. Даты повторений считаются от StartDate:
+// StartDate + n*Interval дней/недель/месяцев/лет. Для месяцев число дня прижимается
+// к концу короткого месяца (31 января -> 28 февраля -> 31 марта).
+type RecurringMovement struct {
+	ID          int        `db:"id" json:"id" example:"1"`
+	UserID      int        `db:"user_id" json:"user_id" example:"10"`
+	WalletID    int        `db:"wallet_id" json:"wallet_id" example:"1"`
+	Type        string     `db:"type" json:"type" example:"expense"` // "income" или "expense"
+	Amount      int64      `db:"amount" json:"amount" swaggertype:"string" example:"45000.00"`
+	Currency    string     `db:"currency" json:"currency" example:"RUB"` // валюта кошелька
+	CategoryID  *int       `db:"category_id" json:"category_id" example:"4"`
+	Description string     `db:"description" json:"description" example:"Rent"`
+	Frequency   string     `db:"frequency" json:"frequency" example:"monthly"` // "daily", "weekly", "monthly", "yearly"
+	Interval    int        `db:"interval_count" json:"interval" example:"1"`
+	StartDate   time.Time  `db:"start_date" json:"start_date" example:"2026-01-05T09:00:00Z"`
+	EndDate     *time.Time `db:"end_date" json:"end_date"`
+	NextDate    time.Time  `db:"next_date" json:"next_date" example:"2026-02-05T09:00:00Z"` // ближайшее ещё не созданное повторение
+	Paused      bool       `db:"paused" json:"paused" example:"false"`
+	CreatedAt   time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt   time.Time  `db:"updated_at" json:"updated_at"`
+}
+
+func (r RecurringMovement) MarshalJSON() ([]byte, error) {
+	type recurring RecurringMovement
+	return json.Marshal(struct {
+		recurring
+		Amount string `json:"amount"`
+	}{recurring(r), currency.FormatAmount(r.Amount, r.Currency)})
+}
+
+// Occurrence — дата n-го повторения, n = 0 соответствует StartDate
+func (r RecurringMovement) Occurrence(n int) time.Time {
+	step := n * r.Interval
+	switch r.Frequency {
+	case "weekly":
+		return r.StartDate.AddDate(0, 0, 7*step)
+	case "monthly":
+		return addMonths(r.StartDate, step)
+	case "yearly":
+		return addMonths(r.StartDate, 12*step)
+	default:
+		return r.StartDate.AddDate(0, 0, step)
+	}
+}
+
+// NextAfter отдаёт первое повторение строго после t; false — расписание закончилось
+func (r RecurringMovement) NextAfter(t time.Time) (time.Time, bool) {
+	n := 0
+	if t.After(r.StartDate) {
+		// оценка номера повторения, дальше дошагиваем до нужного
+		switch r.Frequency {
+		case "weekly":
+			n = int(t.Sub(r.StartDate).Hours()/24) / (7 * r.Interval)
+		case "monthly":
+			n = monthsBetween(r.StartDate, t) / r.Interval
+		case "yearly":
+			n = monthsBetween(r.StartDate, t) / (12 * r.Interval)
+		default:
+			n = int(t.Sub(r.StartDate).Hours()/24) / r.Interval
+		}
+		n = max(n-1, 0)
+	}
+
+	next := r.Occurrence(n)
+	for !next.After(t) {
+		n++
+		next = r.Occurrence(n)
+	}
+	if r.EndDate != nil && next.After(*r.EndDate) {
+		return next, false
+	}
+	return next, true
+}
+
+// IsOccurrence — попадает ли дата точно в расписание
+func (r RecurringMovement) IsOccurrence(t time.Time) bool {
+	next, ok := r.NextAfter(t.Add(-time.Nanosecond))
+	return ok && next.Equal(t)
+}
+
+func addMonths(t time.Time, months int) time.Time {
+	first := time.Date(t.Year(), t.Month()+time.Month(months), 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+	lastDay := first.AddDate(0, 1, -1).Day()
+	return first.AddDate(0, 0, min(t.Day(), lastDay)-1)
+}
+
+func monthsBetween(from, to time.Time) int {
+	return (to.Year()-from.Year())*12 + int(to.Month()) - int(from.Month())
+}
+
+type CreateRecurringInput struct {
+	WalletID    int              `json:"wallet_id" binding:"required,gt=0" example:"1"`
+	Type        string           `json:"type" binding:"required,oneof=income expense" example:"expense"`
+	Amount      currency.Decimal `json:"amount" binding:"required" swaggertype:"string" example:"45000.00"`
+	CategoryID  *int             `json:"category_id" binding:"required" example:"4"`
+	Description string           `json:"description" example:"Rent"`
+	Frequency   string           `json:"frequency" binding:"required,oneof=daily weekly monthly yearly" example:"monthly"`
+	Interval    int              `json:"interval" binding:"omitempty,min=1,max=366" example:"1"` // по умолчанию 1
+	StartDate   time.Time        `json:"start_date" binding:"required" example:"2026-01-05T09:00:00Z"`
+	EndDate     *time.Time       `json:"end_date" example:"2026-12-31T00:00:00Z"`
+}
+
+func (r CreateRecurringInput) Validate() error {
+	if r.Type != "income" && r.Type != "expense" {
+		return errors.New("type must be 'income' or 'expense'")
+	}
+	if !isPositiveDecimal(&r.Amount) {
+		return errors.New("amount must be a decimal number greater than 0")
+	}
+	if r.CategoryID == nil {
+		return errors.New("category_id is required")
+	}
+	if r.Interval < 0 {
+		return errors.New("interval must be positive")
+	}
+	if r.EndDate != nil && r.EndDate.Before(r.StartDate) {
+		return errors.New("end_date must not be before start_date")
+	}
+	return nil
+}
+
+// Пропуск повторения; без даты пропускается ближайшее
+type SkipOccurrenceInput struct {
+	Date *time.Time `json:"date" example:"2026-02-05T09:00:00Z"`
+}
+
+type RecurringPreviewInput struct {
+	Count int `form:"count" binding:"omitempty,min=1,max=100" example:"12"` // по умолчанию 10
+}
+
+// Повторение из расписания: созданное, пропущенное или предстоящее
+type RecurringOccurrence struct {
+	RecurringID int       `db:"recurring_id" json:"-"`
+	Date        time.Time `db:"occurrence_date" json:"date"`
+	MovementID  *int      `db:"movement_id" json:"movement_id,omitempty"`
+	Skipped     bool      `db:"skipped" json:"skipped"`
+}
@@ -0,0 +1,99 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func date(y int, m time.Month, d int) time.Time {
+	return time.Date(y, m, d, 9, 0, 0, 0, time.UTC)
+}
+
+func TestAddMonths(t *testing.T) {
+	tests := []struct {
+		from   time.Time
+		months int
+		want   time.Time
+	}{
+		{date(2026, 1, 31), 1, date(2026, 2, 28)},
+		{date(2028, 1, 31), 1, date(2028, 2, 29)},
+		{date(2026, 1, 31), 2, date(2026, 3, 31)},
+		{date(2026, 3, 31), 1, date(2026, 4, 30)},
+		{date(2026, 11, 15), 3, date(2027, 2, 15)},
+		{date(2026, 3, 31), -1, date(2026, 2, 28)},
+	}
+	for _, tt := range tests {
+		if got := addMonths(tt.from, tt.months); !got.Equal(tt.want) {
+			t.Errorf("addMonths(%s, %d) = %s, want %s", tt.from.Format(time.DateOnly), tt.months, got.Format(time.DateOnly), tt.want.Format(time.DateOnly))
+		}
+	}
+}
+
+func TestRecurringOccurrence(t *testing.T) {
+	tests := []struct {
+		name string
+		r    RecurringMovement
+		n    int
+		want time.Time
+	}{
+		{"daily", RecurringMovement{Frequency: "daily", Interval: 3, StartDate: date(2026, 1, 30)}, 1, date(2026, 2, 2)},
+		{"weekly", RecurringMovement{Frequency: "weekly", Interval: 2, StartDate: date(2026, 1, 5)}, 2, date(2026, 2, 2)},
+		{"monthly end of month", RecurringMovement{Frequency: "monthly", Interval: 1, StartDate: date(2026, 1, 31)}, 1, date(2026, 2, 28)},
+		{"monthly keeps day", RecurringMovement{Frequency: "monthly", Interval: 1, StartDate: date(2026, 1, 31)}, 2, date(2026, 3, 31)},
+		{"yearly leap day", RecurringMovement{Frequency: "yearly", Interval: 1, StartDate: date(2028, 2, 29)}, 1, date(2029, 2, 28)},
+	}
+	for _, tt := range tests {
+		if got := tt.r.Occurrence(tt.n); !got.Equal(tt.want) {
+			t.Errorf("%s: Occurrence(%d) = %s, want %s", tt.name, tt.n, got.Format(time.DateOnly), tt.want.Format(time.DateOnly))
+		}
+	}
+}
+
+func TestRecurringNextAfter(t *testing.T) {
+	end := date(2026, 4, 30)
+	monthly := RecurringMovement{Frequency: "monthly", Interval: 1, StartDate: date(2026, 1, 31), EndDate: &end}
+	weekly := RecurringMovement{Frequency: "weekly", Interval: 1, StartDate: date(2026, 1, 5)}
+
+	tests := []struct {
+		name   string
+		r      RecurringMovement
+		after  time.Time
+		want   time.Time
+		wantOk bool
+	}{
+		{"before start", monthly, date(2026, 1, 1), date(2026, 1, 31), true},
+		{"on occurrence is excluded", monthly, date(2026, 1, 31), date(2026, 2, 28), true},
+		{"between occurrences", monthly, date(2026, 3, 1), date(2026, 3, 31), true},
+		{"last before end date", monthly, date(2026, 4, 1), date(2026, 4, 30), true},
+		{"past end date", monthly, date(2026, 4, 30), date(2026, 5, 31), false},
+		{"weekly far ahead", weekly, date(2026, 12, 30), date(2027, 1, 4), true},
+	}
+	for _, tt := range tests {
+		got, ok := tt.r.NextAfter(tt.after)
+		if ok != tt.wantOk || !got.Equal(tt.want) {
+			t.Errorf("%s: NextAfter(%s) = %s, %v; want %s, %v", tt.name, tt.after.Format(time.DateOnly),
+				got.Format(time.DateOnly), ok, tt.want.Format(time.DateOnly), tt.wantOk)
+		}
+	}
+}
+
+func TestRecurringIsOccurrence(t *testing.T) {
+	end := date(2026, 4, 30)
+	r := RecurringMovement{Frequency: "monthly", Interval: 1, StartDate: date(2026, 1, 31), EndDate: &end}
+
+	tests := []struct {
+		at   time.Time
+		want bool
+	}{
+		{date(2026, 1, 31), true},
+		{date(2026, 2, 28), true},
+		{date(2026, 3, 30), false},
+		{date(2026, 2, 28).Add(time.Hour), false},
+		{date(2026, 5, 31), false},
+	}
+	for _, tt := range tests {
+		if got := r.IsOccurrence(tt.at); got != tt.want {
+			t.Errorf("IsOccurrence(%s) = %v, want %v", tt.at, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/goonsorrow/finance-tracker-api/internal/currency"
+)
+
+// Фильтр отчётов: период и набор кошельков (без wallet_id — все кошельки пользователя)
+type ReportFilterInput struct {
+	ReportPeriodInput
+	WalletIDs []int `form:"wallet_id" binding:"omitempty,dive,gt=0" example:"1"`
+}
+
+func (f ReportFilterInput) Validate() error {
+	return f.ReportPeriodInput.Validate()
+}
+
+type CategoryReportInput struct {
+	ReportFilterInput
+	Type string `form:"type" binding:"omitempty,oneof=income expense" example:"expense"`
+}
+
+// Фильтр для агрегатов в репозитории
+type ReportFilter struct {
+	WalletIDs []int
+	StartDate time.Time
+	EndDate   time.Time // не включительно
+}
+
+// Агрегат доходов и расходов по валюте кошельков. Переводы и начальные остатки не учитываются.
+type CurrencyTotals struct {
+	Currency     string     `db:"currency"`
+	Income       int64      `db:"income"`
+	Expense      int64      `db:"expense"`
+	Count        int        `db:"count"`
+	LastMovement *time.Time `db:"last_movement"`
+}
+
+type CategoryTotals struct {
+	CategoryID *int   `db:"category_id"`
+	Name       string `db:"name"`
+	Currency   string `db:"currency"`
+	Total      int64  `db:"total"`
+	Count      int    `db:"count"`
+}
+
+type MonthlyTotals struct {
+	Month    time.Time `db:"month"`
+	Currency string    `db:"currency"`
+	Income   int64     `db:"income"`
+	Expense  int64     `db:"expense"`
+}
+
+// Итоги по одной валюте в валюте кошельков
+type CurrencySummary struct {
+	Currency      string         `json:"currency" example:"USD"`
+	Balance       currency.Money `json:"balance" swaggertype:"string" example:"1500.00"`
+	Income        currency.Money `json:"income" swaggertype:"string" example:"3000.00"`
+	Expense       currency.Money `json:"expense" swaggertype:"string" example:"1200.00"`
+	WalletCount   int            `json:"wallet_count" example:"2"`
+	MovementCount int            `json:"movement_count" example:"40"`
+}
+
+// Все суммы верхнего уровня — в базовой валюте пользователя по курсу на RateDate
+type SummaryReport struct {
+	BaseCurrency  string            `json:"base_currency" example:"RUB"`
+	TotalBalance  currency.Money    `json:"total_balance" swaggertype:"string" example:"150000.00"`
+	TotalIncome   currency.Money    `json:"total_income" swaggertype:"string" example:"300000.00"`
+	TotalExpense  currency.Money    `json:"total_expense" swaggertype:"string" example:"120000.00"`
+	NetBalance    currency.Money    `json:"net_balance" swaggertype:"string" example:"180000.00"` // income - expense
+	WalletCount   int               `json:"wallet_count" example:"3"`
+	MovementCount int               `json:"movement_count" example:"120"`
+	ByCurrency    []CurrencySummary `json:"by_currency"`
+	RateDate      *time.Time        `json:"rate_date"`
+	MissingRates  []string          `json:"missing_rates,omitempty"` // эти валюты не вошли в итог
+}
+
+type CategorySummary struct {
+	CategoryID *int           `json:"category_id" example:"5"`
+	Category   string         `json:"category" example:"Продукты"`
+	Total      currency.Money `json:"total" swaggertype:"string" example:"25000.00"`
+	Count      int            `json:"count" example:"14"`
+	Percent    float64        `json:"percent" example:"20.8"`
+}
+
+type CategoryReport struct {
+	Type         string            `json:"type" example:"expense"`
+	BaseCurrency string            `json:"base_currency" example:"RUB"`
+	Data         []CategorySummary `json:"data"`
+	GrandTotal   currency.Money    `json:"grand_total" swaggertype:"string" example:"120000.00"`
+	RateDate     *time.Time        `json:"rate_date"`
+	MissingRates []string          `json:"missing_rates,omitempty"`
+}
+
+type MonthlySummary struct {
+	Month     string         `json:"month" example:"2026-01"`
+	Income    currency.Money `json:"income" swaggertype:"string" example:"100000.00"`
+	Expense   currency.Money `json:"expense" swaggertype:"string" example:"40000.00"`
+	NetChange currency.Money `json:"net_change" swaggertype:"string" example:"60000.00"`
+}
+
+// Каждый месяц пересчитывается по курсу на конец этого месяца
+type MonthlyReport struct {
+	BaseCurrency string           `json:"base_currency" example:"RUB"`
+	Data         []MonthlySummary `json:"data"`
+	MissingRates []string         `json:"missing_rates,omitempty"`
+}
+
+// Статистика кошелька в его собственной валюте
+type WalletStats struct {
+	WalletID      int            `json:"wallet_id" example:"1"`
+	WalletName    string         `json:"wallet_name" example:"Main Wallet"`
+	Balance       currency.Money `json:"balance" swaggertype:"string" example:"1500.00"`
+	Currency      string         `json:"currency" example:"USD"`
+	TotalIncome   currency.Money `json:"total_income" swaggertype:"string" example:"3000.00"`
+	TotalExpense  currency.Money `json:"total_expense" swaggertype:"string" example:"1500.00"`
+	MovementCount int            `json:"movement_count" example:"25"`
+	LastMovement  *time.Time     `json:"last_movement"`
+}
+
+// Период отчёта, обе даты включительно
+type ReportPeriodInput struct {
+	StartDate *time.Time `form:"from" time_format:"2006-01-02" example:"2026-01-01"`
+	EndDate   *time.Time `form:"to" time_format:"2006-01-02" example:"2026-01-31"`
+}
+
+func (p ReportPeriodInput) Validate() error {
+	if p.StartDate != nil && p.EndDate != nil && p.EndDate.Before(*p.StartDate) {
+		return errors.New("'to' must not be before 'from'")
+	}
+	return nil
+}
+
+// Итоги переводов между валютами по паре валют. Gain — курсовая разница в базовой валюте:
+// стоимость полученного минус стоимость отправленного по справочным курсам на дату каждого перевода
+type FXPairSummary struct {
+	FromCurrency string         `json:"from_currency"`
+	ToCurrency   string         `json:"to_currency"`
+	Count        int            `json:"count"`
+	Sent         int64          `json:"sent" swaggertype:"string" example:"1000.00"`
+	Received     int64          `json:"received" swaggertype:"string" example:"90000.00"`
+	AvgRate      float64        `json:"avg_rate"`
+	Gain         currency.Money `json:"fx_gain" swaggertype:"string" example:"-150.00"`
+	Unvalued     int            `json:"unvalued,omitempty"` // переводы без справочного курса, в Gain не вошли
+}
+
+func (p FXPairSummary) MarshalJSON() ([]byte, error) {
+	type pair FXPairSummary
+	return json.Marshal(struct {
+		pair
+		Sent     string `json:"sent"`
+		Received string `json:"received"`
+	}{pair(p), currency.FormatAmount(p.Sent, p.FromCurrency), currency.FormatAmount(p.Received, p.ToCurrency)})
+}
+
+// Реализованная курсовая разница: сколько ушло и пришло в каждой валюте через обмен и сколько
+// на обменах выиграно или потеряно против справочного курса, в базовой валюте
+type FXReport struct {
+	BaseCurrency  string                    `json:"base_currency" example:"RUB"`
+	Pairs         []FXPairSummary           `json:"pairs"`
+	NetByCurrency map[string]currency.Money `json:"net_by_currency" swaggertype:"object,string"`
+	TotalGain     currency.Money            `json:"total_fx_gain" swaggertype:"string" example:"-150.00"`
+	MissingRates  []string                  `json:"missing_rates,omitempty"`
+}
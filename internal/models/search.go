@@ -0,0 +1,75 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"unicode"
+
+	"github.com/goonsorrow/finance-tracker-api/internal/currency"
+)
+
+// Сколько слов запроса учитывается при поиске
+const MaxSearchTerms = 10
+
+// Query-параметры поиска по описаниям операций
+type MovementSearchInput struct {
+	MovementConditions
+	Query    string `form:"q" binding:"required,max=200" example:"сантехник"`
+	WalletID *int   `form:"wallet_id" binding:"omitempty,gt=0" example:"1"`
+	Currency string `form:"currency" binding:"omitempty,len=3" example:"RUB"` // обязателен для min_amount/max_amount без wallet_id
+	Limit    int    `form:"limit" binding:"omitempty,min=1,max=100" example:"20"`
+	Offset   int    `form:"offset" binding:"omitempty,min=0" example:"0"`
+}
+
+func (f MovementSearchInput) Validate() error {
+	if err := f.MovementConditions.Validate(); err != nil {
+		return err
+	}
+	if len(SearchTerms(f.Query)) == 0 {
+		return errors.New("q must contain at least one letter or digit")
+	}
+	if f.HasAmountRange() && f.WalletID == nil && f.Currency == "" {
+		return errors.New("currency is required for amount filters without wallet_id")
+	}
+	if f.Limit < 0 || f.Limit > MaxMovementLimit {
+		return errors.New("limit must be between 1 and 100")
+	}
+	return nil
+}
+
+// SearchTerms разбивает запрос на слова из букв и цифр в нижнем регистре.
+// Всё остальное отбрасывается, поэтому синтаксис tsquery из запроса не проходит.
+func SearchTerms(query string) []string {
+	words := strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(words) > MaxSearchTerms {
+		words = words[:MaxSearchTerms]
+	}
+	return words
+}
+
+// Найденная операция с фрагментом описания, где совпавшие слова обёрнуты в <b></b>
+type MovementSearchHit struct {
+	Movement
+	Snippet string  `db:"snippet" json:"snippet" example:"оплата <b>сантехнику</b> за замену смесителя"`
+	Rank    float64 `db:"rank" json:"rank" example:"0.0608"`
+}
+
+func (h MovementSearchHit) MarshalJSON() ([]byte, error) {
+	type movement Movement
+	return json.Marshal(struct {
+		movement
+		Amount  string  `json:"amount"`
+		Snippet string  `json:"snippet"`
+		Rank    float64 `json:"rank"`
+	}{movement(h.Movement), currency.FormatAmount(h.Amount, h.Currency), h.Snippet, h.Rank})
+}
+
+type MovementSearchPage struct {
+	Items   []MovementSearchHit `json:"results"`
+	Limit   int                 `json:"limit"`
+	Offset  int                 `json:"offset"`
+	HasMore bool                `json:"has_more"`
+}
@@ -0,0 +1,85 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"github.com/goonsorrow/finance-tracker-api/internal/currency"
+)
+
+// Сколько меток можно повесить на одну операцию
+const MaxMovementTags = 20
+
+type Tag struct {
+	ID            int       `db:"id" json:"id" example:"1"`
+	UserID        int       `db:"user_id" json:"user_id" example:"10"`
+	Name          string    `db:"name" json:"name" example:"отпуск 2026"`
+	Color         *string   `db:"color" json:"color" example:"#FF8800"`
+	MovementCount int       `db:"movement_count" json:"movement_count,omitempty" example:"12"`
+	CreatedAt     time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt     time.Time `db:"updated_at" json:"updated_at"`
+}
+
+// Метка вместе с операцией, к которой она привязана
+type MovementTag struct {
+	MovementID int `db:"movement_id"`
+	Tag
+}
+
+type CreateTagInput struct {
+	Name  string  `json:"name" binding:"required,max=50" example:"отпуск 2026"`
+	Color *string `json:"color" binding:"omitempty,hexcolor" example:"#FF8800"`
+}
+
+type UpdateTagInput struct {
+	Name  *string `json:"name" binding:"omitempty,min=1,max=50" example:"командировка"`
+	Color *string `json:"color" binding:"omitempty,hexcolor" example:"#0088FF"`
+}
+
+func (t UpdateTagInput) Validate() error {
+	if t.Name == nil && t.Color == nil {
+		return errors.New("at least one field must be provided for update")
+	}
+	return nil
+}
+
+func validateTagIDs(ids []int) error {
+	if len(ids) > MaxMovementTags {
+		return errors.New("too many tags on one movement")
+	}
+	for _, id := range ids {
+		if id <= 0 {
+			return errors.New("tag ids must be positive")
+		}
+	}
+	return nil
+}
+
+type TagReportInput struct {
+	ReportFilterInput
+	Type string `form:"type" binding:"omitempty,oneof=income expense" example:"expense"`
+}
+
+type TagTotals struct {
+	TagID    int    `db:"tag_id"`
+	Name     string `db:"name"`
+	Currency string `db:"currency"`
+	Total    int64  `db:"total"`
+	Count    int    `db:"count"`
+}
+
+type TagSummary struct {
+	TagID int            `json:"tag_id" example:"1"`
+	Tag   string         `json:"tag" example:"отпуск 2026"`
+	Total currency.Money `json:"total" swaggertype:"string" example:"85000.00"`
+	Count int            `json:"count" example:"9"`
+}
+
+// Операция с несколькими метками входит в итог каждой из них, поэтому общего итога нет
+type TagReport struct {
+	Type         string       `json:"type" example:"expense"`
+	BaseCurrency string       `json:"base_currency" example:"RUB"`
+	Data         []TagSummary `json:"data"`
+	RateDate     *time.Time   `json:"rate_date"`
+	MissingRates []string     `json:"missing_rates,omitempty"`
+}
@@ -0,0 +1,105 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/goonsorrow/finance-tracker-api/internal/currency"
+)
+
+// Перевод между кошельками пользователя. В movements пишется парой связанных
+// операций transfer_out/transfer_in, поэтому в доходы и расходы не попадает.
+// Для кошельков в разных валютах каждая нога хранит сумму в валюте своего кошелька,
+// Rate — фактический курс: сколько единиц ToCurrency получено за одну единицу FromCurrency.
+type Transfer struct {
+	ID           int        `db:"id" json:"id"`
+	UserID       int        `db:"user_id" json:"user_id"`
+	FromWalletID int        `db:"from_wallet_id" json:"from_wallet_id"`
+	ToWalletID   int        `db:"to_wallet_id" json:"to_wallet_id"`
+	FromAmount   int64      `db:"from_amount" json:"from_amount" swaggertype:"string" example:"250.00"`
+	ToAmount     int64      `db:"to_amount" json:"to_amount" swaggertype:"string" example:"22500.00"`
+	FromCurrency string     `db:"from_currency" json:"from_currency"`
+	ToCurrency   string     `db:"to_currency" json:"to_currency"`
+	Rate         float64    `db:"rate" json:"rate"`
+	Description  string     `db:"description" json:"description"`
+	Date         time.Time  `db:"date" json:"date"`
+	CreatedAt    time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt    time.Time  `db:"updated_at" json:"updated_at"`
+	Movements    []Movement `db:"-" json:"movements,omitempty"`
+}
+
+// Суммы ног отдаются десятичными строками, каждая в валюте своего кошелька
+func (t Transfer) MarshalJSON() ([]byte, error) {
+	type transfer Transfer
+	return json.Marshal(struct {
+		transfer
+		FromAmount string `json:"from_amount"`
+		ToAmount   string `json:"to_amount"`
+	}{transfer(t), currency.FormatAmount(t.FromAmount, t.FromCurrency), currency.FormatAmount(t.ToAmount, t.ToCurrency)})
+}
+
+// Для перевода между валютами нужен to_amount или rate. Если переданы оба — берётся to_amount,
+// курс пересчитывается по фактическим суммам.
+type CreateTransferInput struct {
+	FromWalletID int               `json:"from_wallet_id" binding:"required,gt=0" example:"1"`
+	ToWalletID   int               `json:"to_wallet_id" binding:"required,gt=0" example:"2"`
+	Amount       currency.Decimal  `json:"amount" binding:"required" swaggertype:"string" example:"250.00"`
+	ToAmount     *currency.Decimal `json:"to_amount" swaggertype:"string" example:"22500.00"`
+	Rate         *float64          `json:"rate" binding:"omitempty,gt=0" example:"90.00"`
+	Description  string            `json:"description" example:"Card to savings"`
+	Date         time.Time         `json:"date" binding:"required" example:"2026-01-27T12:00:00Z"`
+}
+
+type UpdateTransferInput struct {
+	Amount      *currency.Decimal `json:"amount" swaggertype:"string" example:"300.00"`
+	ToAmount    *currency.Decimal `json:"to_amount" swaggertype:"string" example:"27000.00"`
+	Rate        *float64          `json:"rate" binding:"omitempty,gt=0" example:"90.00"`
+	Description *string           `json:"description" example:"Updated description"`
+	Date        *time.Time        `json:"date" example:"2026-01-28T15:00:00Z"`
+}
+
+type UpdateTransferData struct {
+	FromAmount  *int64
+	ToAmount    *int64
+	Rate        *float64
+	Description *string
+	Date        *time.Time
+}
+
+func (t CreateTransferInput) Validate() error {
+	if t.FromWalletID == t.ToWalletID {
+		return errors.New("cannot transfer to the same wallet")
+	}
+	if !isPositiveDecimal(&t.Amount) {
+		return errors.New("amount must be a decimal number greater than 0")
+	}
+	if t.ToAmount != nil && !isPositiveDecimal(t.ToAmount) {
+		return errors.New("to_amount must be a decimal number greater than 0")
+	}
+	if t.Rate != nil && *t.Rate <= 0 {
+		return errors.New("rate must be greater than 0")
+	}
+	return nil
+}
+
+func (t UpdateTransferInput) Validate() error {
+	if t.Amount == nil && t.ToAmount == nil && t.Rate == nil && t.Description == nil && t.Date == nil {
+		return errors.New("at least one field must be provided for update")
+	}
+	if t.Amount != nil && !isPositiveDecimal(t.Amount) {
+		return errors.New("amount must be a decimal number greater than 0")
+	}
+	if t.ToAmount != nil && !isPositiveDecimal(t.ToAmount) {
+		return errors.New("to_amount must be a decimal number greater than 0")
+	}
+	if t.Rate != nil && *t.Rate <= 0 {
+		return errors.New("rate must be greater than 0")
+	}
+	return nil
+}
+
+func isPositiveDecimal(d *currency.Decimal) bool {
+	sign, err := d.Sign()
+	return err == nil && sign > 0
+}
@@ -0,0 +1,17 @@
+package models
+
+// Содержимое корзины пользователя. Операции удалённого кошелька отдельно не показываются:
+// они возвращаются вместе с ним
+type Trash struct {
+	Wallets    []Wallet   `json:"wallets"`
+	Movements  []Movement `json:"movements"`
+	Categories []Category `json:"categories"`
+}
+
+// Сколько записей удалено при очистке корзины
+type PurgeResult struct {
+	Wallets    int `json:"wallets"`
+	Movements  int `json:"movements"`
+	Transfers  int `json:"transfers"`
+	Categories int `json:"categories"`
+}
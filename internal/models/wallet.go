@@ -1,25 +1,39 @@
 package models
 
 import (
+	"encoding/json"
 	"errors"
 	"time"
+
+	"github.com/goonsorrow/finance-tracker-api/internal/currency"
 )
 
 type Wallet struct {
-	DisplayId int       `db:"display_id" json:"display_id"`
-	ID        int       `db:"id" json:"id" example:"1"`
-	UserID    int       `db:"user_id" json:"user_id" example:"10"`
-	Name      string    `db:"name" json:"name" example:"Main Wallet"`
-	Balance   int64     `db:"balance" json:"balance" example:"15000"`
-	Currency  string    `db:"currency" json:"currency" example:"USD"`
-	CreatedAt time.Time `db:"created_at" json:"created_at"`
-	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+	DisplayId int        `db:"display_id" json:"display_id"`
+	ID        int        `db:"id" json:"id" example:"1"`
+	UserID    int        `db:"user_id" json:"user_id" example:"10"`
+	Name      string     `db:"name" json:"name" example:"Main Wallet"`
+	Balance   int64      `db:"balance" json:"balance" swaggertype:"string" example:"150.00"` // в минимальных единицах валюты
+	Currency  string     `db:"currency" json:"currency" example:"USD"`
+	CreatedAt time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt time.Time  `db:"updated_at" json:"updated_at"`
+	DeletedAt *time.Time `db:"deleted_at" json:"deleted_at,omitempty"` // заполнено только у кошельков в корзине
+	Version   int        `db:"version" json:"version" example:"1"`     // растёт при каждом изменении, отдаётся как ETag
+}
+
+// В JSON баланс отдаётся десятичной строкой в валюте кошелька
+func (w Wallet) MarshalJSON() ([]byte, error) {
+	type wallet Wallet
+	return json.Marshal(struct {
+		wallet
+		Balance string `json:"balance"`
+	}{wallet(w), currency.FormatAmount(w.Balance, w.Currency)})
 }
 
 type CreateWalletInput struct {
-	Name           string  `json:"name" binding:"required" example:"Salary Card"`
-	InitialBalance float64 `json:"balance" binding:"required" example:"1500"`
-	Currency       string  `json:"currency" binding:"required,len=3" example:"USD"`
+	Name           string           `json:"name" binding:"required" example:"Salary Card"`
+	InitialBalance currency.Decimal `json:"balance" binding:"required" swaggertype:"string" example:"1500.00"`
+	Currency       string           `json:"currency" binding:"required,len=3" example:"USD"`
 }
 
 type UpdateWalletInput struct {
@@ -34,6 +48,9 @@ func (w CreateWalletInput) Validate() error {
 	if w.Currency == "" || len(w.Currency) != 3 {
 		return errors.New("currency must be 3 characters (USD, EUR, RUB)")
 	}
+	if _, err := w.InitialBalance.Sign(); err != nil {
+		return errors.New("balance must be a decimal number")
+	}
 	return nil
 }
 
@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/goonsorrow/finance-tracker-api/internal/models"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+const (
+	createAttachmentQuery = `INSERT INTO attachments (user_id, movement_id, storage_key, filename, content_type, size, created_at)
+								VALUES ($1, $2, $3, $4, $5, $6, NOW())
+								RETURNING id, created_at`
+
+	selectAttachmentColumns = `SELECT id, user_id, movement_id, storage_key, filename, content_type, size, created_at
+								FROM attachments`
+
+	getAllAttachmentsQuery = selectAttachmentColumns + `
+								WHERE user_id = $1 AND movement_id = $2
+								ORDER BY id`
+
+	getAttachmentByIdQuery = selectAttachmentColumns + `
+								WHERE user_id = $1 AND movement_id = $2 AND id = $3`
+
+	deleteAttachmentByIdQuery = `DELETE
+								FROM attachments
+								WHERE user_id = $1 AND id = $2`
+
+	getAttachmentKeysByMovementsQuery = `SELECT storage_key
+								FROM attachments
+								WHERE movement_id = ANY($1)`
+
+	// файлы операций, которые удалит очистка корзины с тем же сроком: сами операции,
+	// операции удаляемых кошельков и обе ноги их переводов
+	getExpiredAttachmentKeysQuery = `SELECT a.storage_key
+								FROM attachments a
+								JOIN movements m ON m.id = a.movement_id
+								JOIN wallets w ON w.id = m.wallet_id
+								WHERE (m.deleted_at < NOW() - $1::float8 * INTERVAL '1 second' AND m.transfer_id IS NULL)
+									OR w.deleted_at < NOW() - $1::float8 * INTERVAL '1 second'
+									OR m.transfer_id IN (SELECT t.id FROM transfers t
+										JOIN wallets tw ON tw.id IN (t.from_wallet_id, t.to_wallet_id)
+										WHERE tw.deleted_at < NOW() - $1::float8 * INTERVAL '1 second')`
+)
+
+type AttachmentPostgres struct {
+	db         *sqlx.DB
+	transactor Transactor
+}
+
+func NewAttachmentPostgres(db *sqlx.DB, transactor Transactor) *AttachmentPostgres {
+	return &AttachmentPostgres{db: db, transactor: transactor}
+}
+
+func (r *AttachmentPostgres) Create(ctx context.Context, attachment *models.Attachment) error {
+	err := r.transactor.GetExecutor(ctx).QueryRowxContext(ctx, createAttachmentQuery,
+		attachment.UserID,                                           //$1
+		attachment.MovementID,                                       //$2
+		attachment.StorageKey,                                       //$3
+		attachment.Filename,                                         //$4
+		attachment.ContentType,                                      //$5
+		attachment.Size).Scan(&attachment.ID, &attachment.CreatedAt) //$6
+	if err != nil {
+		return fmt.Errorf("[AttachmentPostgres.Create] failed creating attachment: %w", err)
+	}
+	return nil
+}
+
+func (r *AttachmentPostgres) GetAll(ctx context.Context, userId, movementId int) ([]models.Attachment, error) {
+	var attachments []models.Attachment
+	err := sqlx.SelectContext(ctx, r.transactor.GetExecutor(ctx), &attachments, getAllAttachmentsQuery, userId, movementId)
+	if err != nil {
+		return nil, fmt.Errorf("[AttachmentPostgres.GetAll] failed getting attachments: %w", err)
+	}
+	return attachments, nil
+}
+
+func (r *AttachmentPostgres) GetById(ctx context.Context, userId, movementId, attachmentId int) (models.Attachment, error) {
+	var attachment models.Attachment
+	err := sqlx.GetContext(ctx, r.transactor.GetExecutor(ctx), &attachment, getAttachmentByIdQuery, userId, movementId, attachmentId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Attachment{}, ErrRecordNotFound
+		}
+		return models.Attachment{}, fmt.Errorf("[AttachmentPostgres.GetById] failed getting attachment: %w", err)
+	}
+	return attachment, nil
+}
+
+func (r *AttachmentPostgres) Delete(ctx context.Context, userId, attachmentId int) error {
+	res, err := r.transactor.GetExecutor(ctx).ExecContext(ctx, deleteAttachmentByIdQuery, userId, attachmentId)
+	if err != nil {
+		return fmt.Errorf("[AttachmentPostgres.Delete] failed deleting attachment: %w", err)
+	}
+	rows, _ := res.RowsAffected()
+	if rows == 0 {
+		return ErrRecordNotFound
+	}
+	return nil
+}
+
+// KeysByMovements — ключи файлов, которые удалятся вместе с операциями
+func (r *AttachmentPostgres) KeysByMovements(ctx context.Context, movementIds []int) ([]string, error) {
+	var keys []string
+	if len(movementIds) == 0 {
+		return keys, nil
+	}
+	err := sqlx.SelectContext(ctx, r.transactor.GetExecutor(ctx), &keys, getAttachmentKeysByMovementsQuery, pq.Array(movementIds))
+	if err != nil {
+		return nil, fmt.Errorf("[AttachmentPostgres.KeysByMovements] failed getting attachment keys: %w", err)
+	}
+	return keys, nil
+}
+
+// ExpiredKeys — ключи файлов, которые уйдут вместе с очисткой корзины; вызывается в той же транзакции
+func (r *AttachmentPostgres) ExpiredKeys(ctx context.Context, retention time.Duration) ([]string, error) {
+	var keys []string
+	err := sqlx.SelectContext(ctx, r.transactor.GetExecutor(ctx), &keys, getExpiredAttachmentKeysQuery, retention.Seconds())
+	if err != nil {
+		return nil, fmt.Errorf("[AttachmentPostgres.ExpiredKeys] failed getting attachment keys: %w", err)
+	}
+	return keys, nil
+}
@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/goonsorrow/finance-tracker-api/internal/models"
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	createAuditEntryQuery = `INSERT INTO audit_log (user_id, entity, entity_id, action, before, after, request_id, ip, created_at)
+								VALUES ($1, $2, $3, $4, $5::jsonb, $6::jsonb, $7, $8, NOW())`
+
+	getAuditHistoryQuery = `SELECT id, user_id, entity, entity_id, action, before::text AS before, after::text AS after, request_id, ip, created_at
+								FROM audit_log
+								WHERE user_id = $1 AND entity = $2 AND entity_id = $3
+								ORDER BY id DESC
+								LIMIT $4 OFFSET $5`
+)
+
+type AuditPostgres struct {
+	db         *sqlx.DB
+	transactor Transactor
+}
+
+func NewAuditPostgres(db *sqlx.DB, transactor Transactor) *AuditPostgres {
+	return &AuditPostgres{db: db, transactor: transactor}
+}
+
+// Create пишет запись через исполнитель из ctx, чтобы она попала в транзакцию самого изменения
+func (r *AuditPostgres) Create(ctx context.Context, entry models.AuditEntry) error {
+	_, err := r.transactor.GetExecutor(ctx).ExecContext(ctx, createAuditEntryQuery,
+		entry.UserID,    //$1
+		entry.Entity,    //$2
+		entry.EntityID,  //$3
+		entry.Action,    //$4
+		entry.Before,    //$5
+		entry.After,     //$6
+		entry.RequestID, //$7
+		entry.IP)        //$8
+	if err != nil {
+		return fmt.Errorf("[AuditPostgres.Create] failed writing audit entry: %w", err)
+	}
+	return nil
+}
+
+func (r *AuditPostgres) GetByEntity(ctx context.Context, userId int, entity string, entityId, limit, offset int) ([]models.AuditEntry, error) {
+	var entries []models.AuditEntry
+	err := sqlx.SelectContext(ctx, r.transactor.GetExecutor(ctx), &entries, getAuditHistoryQuery, userId, entity, entityId, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("[AuditPostgres.GetByEntity] failed getting audit history: %w", err)
+	}
+	return entries, nil
+}
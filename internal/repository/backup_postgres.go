@@ -0,0 +1,210 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/goonsorrow/finance-tracker-api/internal/models"
+	"github.com/jmoiron/sqlx"
+)
+
+// Запись восстанавливаемых из архива данных. В отличие от обычного создания
+// сохраняются исходные даты создания.
+const (
+	isAccountEmptyQuery = `SELECT NOT EXISTS (SELECT 1 FROM wallets WHERE user_id = $1)
+							AND NOT EXISTS (SELECT 1 FROM categories WHERE user_id = $1)
+							AND NOT EXISTS (SELECT 1 FROM budgets WHERE user_id = $1)
+							AND NOT EXISTS (SELECT 1 FROM tags WHERE user_id = $1)`
+
+	// категории из корзины тоже выгружаются: на них могут ссылаться живые операции
+	backupCategoriesQuery = `SELECT id, user_id, name, type, icon, created_at, updated_at
+							FROM categories
+							WHERE user_id = $1 OR user_id IS NULL
+							ORDER BY id`
+
+	restoreCategoryQuery = `INSERT INTO categories (user_id, name, type, icon, created_at, updated_at)
+							VALUES ($1, $2, $3, $4, NOW(), NOW())
+							RETURNING id`
+
+	restoreTagQuery = `INSERT INTO tags (user_id, name, color, created_at, updated_at)
+							VALUES ($1, $2, $3, NOW(), NOW())
+							RETURNING id`
+
+	restoreWalletQuery = `INSERT INTO wallets (user_id, name, currency, created_at, updated_at)
+							VALUES ($1, $2, $3, $4, NOW())
+							RETURNING id`
+
+	restoreTransferQuery = `INSERT INTO transfers (user_id, from_wallet_id, to_wallet_id, from_amount, to_amount, from_currency, to_currency, rate, description, date, created_at, updated_at)
+							VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, NOW())
+							RETURNING id`
+
+	restoreMovementQuery = `INSERT INTO movements (wallet_id, user_id, type, amount, category_id, description, date, transfer_id, external_id, created_at, updated_at)
+							VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, NOW())
+							RETURNING id`
+
+	restoreBudgetQuery = `INSERT INTO budgets (user_id, category_id, amount, currency, rollover, start_month, created_at, updated_at)
+							VALUES ($1, $2, $3, $4, $5, $6, NOW(), NOW())`
+
+	restoreRecurringQuery = `INSERT INTO recurring_movements (user_id, wallet_id, type, amount, category_id, description, frequency, interval_count, start_date, end_date, next_date, paused, created_at, updated_at)
+							VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, NOW(), NOW())`
+
+	setBaseCurrencyQuery = `UPDATE users
+							SET base_currency = $1, updated_at = NOW()
+							WHERE id = $2`
+)
+
+type BackupPostgres struct {
+	db         *sqlx.DB
+	transactor Transactor
+}
+
+func NewBackupPostgres(db *sqlx.DB, transactor Transactor) *BackupPostgres {
+	return &BackupPostgres{db: db, transactor: transactor}
+}
+
+// IsAccountEmpty — у пользователя нет своих кошельков, категорий, меток и бюджетов
+func (r *BackupPostgres) IsAccountEmpty(ctx context.Context, userId int) (bool, error) {
+	var empty bool
+	err := r.transactor.GetExecutor(ctx).QueryRowxContext(ctx, isAccountEmptyQuery, userId).Scan(&empty)
+	if err != nil {
+		return false, fmt.Errorf("[BackupPostgres.IsAccountEmpty] failed checking account: %w", err)
+	}
+	return empty, nil
+}
+
+// GetCategories — собственные и общие категории, доступные пользователю
+func (r *BackupPostgres) GetCategories(ctx context.Context, userId int) ([]models.Category, error) {
+	var categories []models.Category
+	if err := sqlx.SelectContext(ctx, r.transactor.GetExecutor(ctx), &categories, backupCategoriesQuery, userId); err != nil {
+		return nil, fmt.Errorf("[BackupPostgres.GetCategories] failed getting categories: %w", err)
+	}
+	return categories, nil
+}
+
+func (r *BackupPostgres) RestoreCategory(ctx context.Context, userId int, category models.Category) (int, error) {
+	var id int
+	err := r.transactor.GetExecutor(ctx).QueryRowxContext(ctx, restoreCategoryQuery,
+		userId,                  //$1
+		category.Name,           //$2
+		category.Type,           //$3
+		category.Icon).Scan(&id) //$4
+	if isUniqueViolation(err) {
+		return 0, fmt.Errorf("[BackupPostgres.RestoreCategory] category %q: %w", category.Name, ErrDuplicate)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("[BackupPostgres.RestoreCategory] failed restoring category: %w", err)
+	}
+	return id, nil
+}
+
+func (r *BackupPostgres) RestoreTag(ctx context.Context, userId int, tag models.Tag) (int, error) {
+	var id int
+	err := r.transactor.GetExecutor(ctx).QueryRowxContext(ctx, restoreTagQuery, userId, tag.Name, tag.Color).Scan(&id)
+	if isUniqueViolation(err) {
+		return 0, fmt.Errorf("[BackupPostgres.RestoreTag] tag %q: %w", tag.Name, ErrDuplicate)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("[BackupPostgres.RestoreTag] failed restoring tag: %w", err)
+	}
+	return id, nil
+}
+
+func (r *BackupPostgres) RestoreWallet(ctx context.Context, userId int, wallet models.Wallet) (int, error) {
+	var id int
+	err := r.transactor.GetExecutor(ctx).QueryRowxContext(ctx, restoreWalletQuery,
+		userId,                     //$1
+		wallet.Name,                //$2
+		wallet.Currency,            //$3
+		wallet.CreatedAt).Scan(&id) //$4
+	if err != nil {
+		return 0, fmt.Errorf("[BackupPostgres.RestoreWallet] failed restoring wallet: %w", err)
+	}
+	return id, nil
+}
+
+func (r *BackupPostgres) RestoreTransfer(ctx context.Context, userId int, transfer models.Transfer) (int, error) {
+	var id int
+	err := r.transactor.GetExecutor(ctx).QueryRowxContext(ctx, restoreTransferQuery,
+		userId,                       //$1
+		transfer.FromWalletID,        //$2
+		transfer.ToWalletID,          //$3
+		transfer.FromAmount,          //$4
+		transfer.ToAmount,            //$5
+		transfer.FromCurrency,        //$6
+		transfer.ToCurrency,          //$7
+		transfer.Rate,                //$8
+		transfer.Description,         //$9
+		transfer.Date,                //$10
+		transfer.CreatedAt).Scan(&id) //$11
+	if err != nil {
+		return 0, fmt.Errorf("[BackupPostgres.RestoreTransfer] failed restoring transfer: %w", err)
+	}
+	return id, nil
+}
+
+func (r *BackupPostgres) RestoreMovement(ctx context.Context, userId int, movement models.Movement) (int, error) {
+	var id int
+	err := r.transactor.GetExecutor(ctx).QueryRowxContext(ctx, restoreMovementQuery,
+		movement.WalletID,            //$1
+		userId,                       //$2
+		movement.Type,                //$3
+		movement.Amount,              //$4
+		movement.CategoryID,          //$5
+		movement.Description,         //$6
+		movement.Date,                //$7
+		movement.TransferID,          //$8
+		movement.ExternalID,          //$9
+		movement.CreatedAt).Scan(&id) //$10
+	if isUniqueViolation(err) {
+		return 0, fmt.Errorf("[BackupPostgres.RestoreMovement] movement with external id already exists: %w", ErrDuplicate)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("[BackupPostgres.RestoreMovement] failed restoring movement: %w", err)
+	}
+	return id, nil
+}
+
+func (r *BackupPostgres) RestoreBudget(ctx context.Context, userId int, budget models.Budget) error {
+	_, err := r.transactor.GetExecutor(ctx).ExecContext(ctx, restoreBudgetQuery,
+		userId,            //$1
+		budget.CategoryID, //$2
+		budget.Amount,     //$3
+		budget.Currency,   //$4
+		budget.Rollover,   //$5
+		budget.StartMonth) //$6
+	if isUniqueViolation(err) {
+		return fmt.Errorf("[BackupPostgres.RestoreBudget] budget for category %d: %w", budget.CategoryID, ErrDuplicate)
+	}
+	if err != nil {
+		return fmt.Errorf("[BackupPostgres.RestoreBudget] failed restoring budget: %w", err)
+	}
+	return nil
+}
+
+func (r *BackupPostgres) RestoreRecurring(ctx context.Context, userId int, recurring models.RecurringMovement) error {
+	_, err := r.transactor.GetExecutor(ctx).ExecContext(ctx, restoreRecurringQuery,
+		userId,                //$1
+		recurring.WalletID,    //$2
+		recurring.Type,        //$3
+		recurring.Amount,      //$4
+		recurring.CategoryID,  //$5
+		recurring.Description, //$6
+		recurring.Frequency,   //$7
+		recurring.Interval,    //$8
+		recurring.StartDate,   //$9
+		recurring.EndDate,     //$10
+		recurring.NextDate,    //$11
+		recurring.Paused)      //$12
+	if err != nil {
+		return fmt.Errorf("[BackupPostgres.RestoreRecurring] failed restoring recurring movement: %w", err)
+	}
+	return nil
+}
+
+func (r *BackupPostgres) SetBaseCurrency(ctx context.Context, userId int, code string) error {
+	_, err := r.transactor.GetExecutor(ctx).ExecContext(ctx, setBaseCurrencyQuery, code, userId)
+	if err != nil {
+		return fmt.Errorf("[BackupPostgres.SetBaseCurrency] failed updating base currency: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/goonsorrow/finance-tracker-api/internal/models"
+	"github.com/jmoiron/sqlx"
+)
+
+type BudgetPostgres struct {
+	db         *sqlx.DB
+	transactor Transactor
+}
+
+func NewBudgetPostgres(db *sqlx.DB, transactor Transactor) *BudgetPostgres {
+	return &BudgetPostgres{db: db, transactor: transactor}
+}
+
+const (
+	createBudgetQuery = `INSERT
+							INTO budgets (user_id, category_id, amount, currency, rollover, start_month, created_at, updated_at)
+							VALUES ($1, $2, $3, $4, $5, $6, NOW(), NOW())
+							RETURNING id`
+
+	selectBudgetColumns = `SELECT b.id, b.user_id, b.category_id, c.name AS category, b.amount, b.currency, b.rollover, b.start_month, b.created_at, b.updated_at
+							FROM budgets b
+							JOIN categories c ON c.id = b.category_id`
+
+	getAllBudgetsQuery = selectBudgetColumns + `
+							WHERE b.user_id = $1 AND c.deleted_at IS NULL
+							ORDER BY c.name`
+
+	getBudgetByIdQuery = selectBudgetColumns + `
+							WHERE b.user_id = $1 AND b.id = $2 AND c.deleted_at IS NULL`
+
+	updateBudgetByIdQuery = `UPDATE budgets
+								SET amount = COALESCE($1, amount),
+									rollover = COALESCE($2, rollover),
+									updated_at = NOW()
+								WHERE user_id = $3 AND id = $4`
+
+	deleteBudgetByIdQuery = `DELETE
+								FROM budgets
+								WHERE user_id = $1 AND id = $2`
+
+	// расходы по категории помесячно, в валюте кошельков; у разбитых операций считается только своя часть
+	categorySpendQuery = `SELECT date_trunc('month', m.date) AS month, w.currency, SUM(COALESCE(s.amount, m.amount)) AS spent
+							FROM movements m
+							JOIN wallets w ON w.id = m.wallet_id
+							LEFT JOIN movement_splits s ON s.movement_id = m.id
+							WHERE m.user_id = $1 AND COALESCE(s.category_id, m.category_id) = $2 AND m.type = 'expense'
+							AND m.deleted_at IS NULL AND w.deleted_at IS NULL
+							AND m.date >= $3 AND m.date < $4
+							GROUP BY month, w.currency
+							ORDER BY month`
+)
+
+func (r *BudgetPostgres) Create(ctx context.Context, userId int, budget models.Budget) (int, error) {
+	var id int
+	err := r.transactor.GetExecutor(ctx).QueryRowxContext(ctx, createBudgetQuery,
+		userId,                      //$1
+		budget.CategoryID,           //$2
+		budget.Amount,               //$3
+		budget.Currency,             //$4
+		budget.Rollover,             //$5
+		budget.StartMonth).Scan(&id) //$6
+	if err != nil {
+		if isUniqueViolation(err) {
+			return 0, fmt.Errorf("[BudgetPostgres.Create] budget for category %d: %w", budget.CategoryID, ErrDuplicate)
+		}
+		return 0, fmt.Errorf("[BudgetPostgres.Create] failed to create budget: %w", err)
+	}
+	return id, nil
+}
+
+func (r *BudgetPostgres) GetAll(ctx context.Context, userId int) ([]models.Budget, error) {
+	var budgets []models.Budget
+	err := sqlx.SelectContext(ctx, r.transactor.GetExecutor(ctx), &budgets, getAllBudgetsQuery, userId)
+	if err != nil {
+		return nil, fmt.Errorf("[BudgetPostgres.GetAll] failed getting budgets: %w", err)
+	}
+	return budgets, nil
+}
+
+func (r *BudgetPostgres) GetById(ctx context.Context, userId, budgetId int) (models.Budget, error) {
+	var budget models.Budget
+	err := sqlx.GetContext(ctx, r.transactor.GetExecutor(ctx), &budget, getBudgetByIdQuery,
+		userId,   //$1
+		budgetId) //$2
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Budget{}, ErrRecordNotFound
+		}
+		return models.Budget{}, fmt.Errorf("[BudgetPostgres.GetById] failed getting budget: %w", err)
+	}
+	return budget, nil
+}
+
+func (r *BudgetPostgres) Update(ctx context.Context, userId, budgetId int, input models.UpdateBudgetData) error {
+	res, err := r.transactor.GetExecutor(ctx).ExecContext(ctx, updateBudgetByIdQuery,
+		input.Amount,   //$1
+		input.Rollover, //$2
+		userId,         //$3
+		budgetId)       //$4
+	if err != nil {
+		return fmt.Errorf("[BudgetPostgres.Update] failed to update budget: %w", err)
+	}
+	rows, _ := res.RowsAffected()
+	if rows == 0 {
+		return ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *BudgetPostgres) Delete(ctx context.Context, userId, budgetId int) error {
+	res, err := r.transactor.GetExecutor(ctx).ExecContext(ctx, deleteBudgetByIdQuery, userId, budgetId)
+	if err != nil {
+		return fmt.Errorf("[BudgetPostgres.Delete] failed to delete budget: %w", err)
+	}
+	rows, _ := res.RowsAffected()
+	if rows == 0 {
+		return ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *BudgetPostgres) SpentByMonth(ctx context.Context, userId, categoryId int, from, to time.Time) ([]models.CategorySpend, error) {
+	var spend []models.CategorySpend
+	err := sqlx.SelectContext(ctx, r.transactor.GetExecutor(ctx), &spend, categorySpendQuery,
+		userId,     //$1
+		categoryId, //$2
+		from,       //$3
+		to)         //$4
+	if err != nil {
+		return nil, fmt.Errorf("[BudgetPostgres.SpentByMonth] failed aggregating category spend: %w", err)
+	}
+	return spend, nil
+}
@@ -26,23 +26,38 @@ const (
 
 	getAllCategoriesQuery = `SELECT * 
 								FROM categories 
-								WHERE user_id = $1 OR user_id IS NULL
+								WHERE (user_id = $1 OR user_id IS NULL) AND deleted_at IS NULL
 								ORDER BY CASE WHEN user_id = $1 THEN 0 ELSE 1 END,
 								usage_count DESC, name`
 
 	getCategoryByIdQuery = `SELECT *
 								FROM categories 
 								WHERE id = $1 
-								AND (user_id = $2 OR user_id IS NULL)`
+								AND (user_id = $2 OR user_id IS NULL)
+								AND deleted_at IS NULL`
 
+	getDeletedCategoriesQuery = `SELECT *
+								FROM categories
+								WHERE user_id = $1 AND deleted_at IS NOT NULL
+								ORDER BY deleted_at DESC`
+
+	// $5 — ожидаемая версия, NULL отключает проверку
 	updateCategoryById = `UPDATE categories
 							SET name = COALESCE($1,name),
-								icon = COALESCE($2,icon)
-							WHERE id = $3 AND (user_id = $4 OR user_id IS NULL)`
-
-	deleteCategoryById = `DELETE
-							FROM categories
-							WHERE id = $1 AND user_id = $2`
+								icon = COALESCE($2,icon),
+								version = version + 1
+							WHERE id = $3 AND (user_id = $4 OR user_id IS NULL) AND deleted_at IS NULL
+							AND ($5::int IS NULL OR version = $5)`
+
+	// операции и бюджеты сохраняют ссылку на категорию из корзины
+	deleteCategoryById = `UPDATE categories
+							SET deleted_at = NOW(), version = version + 1
+							WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL
+							AND ($3::int IS NULL OR version = $3)`
+
+	restoreCategoryById = `UPDATE categories
+							SET deleted_at = NULL, version = version + 1
+							WHERE id = $1 AND user_id = $2 AND deleted_at IS NOT NULL`
 )
 
 func (r CategoryPostgres) Create(ctx context.Context, userId int, input models.Category) (int, error) {
@@ -82,24 +97,55 @@ func (r CategoryPostgres) GetById(ctx context.Context, userId int, categoryId in
 	return category, nil
 }
 
-func (r CategoryPostgres) Update(ctx context.Context, userId, categoryId int, input models.UpdateCategoryInput) error {
-	res, err := r.transactor.GetExecutor(ctx).ExecContext(ctx, updateCategoryById, input.Name, input.Icon, categoryId, userId)
+func (r CategoryPostgres) Update(ctx context.Context, userId, categoryId int, input models.UpdateCategoryInput, version *int) error {
+	res, err := r.transactor.GetExecutor(ctx).ExecContext(ctx, updateCategoryById, input.Name, input.Icon, categoryId, userId, version)
 	if err != nil {
 		return fmt.Errorf("[CategoryPostgres.Update] failed to update category:%w", err)
 	}
 	rows, _ := res.RowsAffected()
 	if rows == 0 {
+		if version != nil {
+			return ErrVersionConflict
+		}
 		return ErrRecordNotFound
 	}
 	return nil
 }
 
-func (r CategoryPostgres) Delete(ctx context.Context, userId, categoryId int) error {
-	res, err := r.transactor.GetExecutor(ctx).ExecContext(ctx, deleteCategoryById, categoryId, userId)
+func (r CategoryPostgres) Delete(ctx context.Context, userId, categoryId int, version *int) error {
+	res, err := r.transactor.GetExecutor(ctx).ExecContext(ctx, deleteCategoryById, categoryId, userId, version)
 	if err != nil {
 		return fmt.Errorf("[CategoryPostgres.Delete] failed deleting category:%w", err)
 	}
 	rows, _ := res.RowsAffected()
+	if rows == 0 {
+		if version != nil {
+			return ErrVersionConflict
+		}
+		return ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r CategoryPostgres) GetDeleted(ctx context.Context, userId int) ([]models.Category, error) {
+	var categories []models.Category
+	err := sqlx.SelectContext(ctx, r.transactor.GetExecutor(ctx), &categories, getDeletedCategoriesQuery, userId)
+	if err != nil {
+		return nil, fmt.Errorf("[CategoryPostgres.GetDeleted] failed getting deleted categories: %w", err)
+	}
+	return categories, nil
+}
+
+// Restore возвращает категорию из корзины; если за это время появилась такая же, вернётся ErrDuplicate
+func (r CategoryPostgres) Restore(ctx context.Context, userId, categoryId int) error {
+	res, err := r.transactor.GetExecutor(ctx).ExecContext(ctx, restoreCategoryById, categoryId, userId)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return fmt.Errorf("[CategoryPostgres.Restore] category with this name already exists: %w", ErrDuplicate)
+		}
+		return fmt.Errorf("[CategoryPostgres.Restore] failed restoring category:%w", err)
+	}
+	rows, _ := res.RowsAffected()
 	if rows == 0 {
 		return ErrRecordNotFound
 	}
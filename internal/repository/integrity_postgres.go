@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/goonsorrow/finance-tracker-api/internal/models"
+	"github.com/jmoiron/sqlx"
+)
+
+// Баланс кошелька по проводкам журнала должен совпадать с суммой его живых операций
+// со знаком, как в balanceDelta. Кошельки в корзине тоже проверяются: их проводки остаются
+const (
+	movementSumExpr = `COALESCE((SELECT SUM(CASE WHEN m.type IN ('expense', 'transfer_out') THEN -m.amount ELSE m.amount END)
+							FROM movements m
+							WHERE m.wallet_id = w.id AND m.deleted_at IS NULL), 0)`
+
+	balanceMismatchesQuery = `SELECT w.id AS wallet_id, w.user_id, w.currency, COALESCE(b.balance, 0) AS stored, s.computed
+								FROM wallets w
+								LEFT JOIN wallet_balances b ON b.wallet_id = w.id
+								CROSS JOIN LATERAL (SELECT ` + movementSumExpr + ` AS computed) s
+								WHERE COALESCE(b.balance, 0) <> s.computed
+								ORDER BY w.id`
+
+	lockWalletQuery = `SELECT id FROM wallets WHERE id = $1 FOR UPDATE`
+
+	walletBalanceQuery = `SELECT w.id AS wallet_id, w.user_id, w.currency, COALESCE(b.balance, 0) AS stored, ` + movementSumExpr + ` AS computed
+								FROM wallets w
+								LEFT JOIN wallet_balances b ON b.wallet_id = w.id
+								WHERE w.id = $1`
+)
+
+type IntegrityPostgres struct {
+	db         *sqlx.DB
+	transactor Transactor
+}
+
+func NewIntegrityPostgres(db *sqlx.DB, transactor Transactor) *IntegrityPostgres {
+	return &IntegrityPostgres{db: db, transactor: transactor}
+}
+
+func (r *IntegrityPostgres) BalanceMismatches(ctx context.Context) ([]models.BalanceMismatch, error) {
+	var mismatches []models.BalanceMismatch
+	if err := sqlx.SelectContext(ctx, r.transactor.GetExecutor(ctx), &mismatches, balanceMismatchesQuery); err != nil {
+		return nil, fmt.Errorf("[IntegrityPostgres.BalanceMismatches] failed checking balances: %w", err)
+	}
+	return mismatches, nil
+}
+
+// LockedBalance блокирует кошелёк, чтобы его не убрали в корзину и не сменили валюту,
+// пока расхождение исправляется, и только потом считает суммы. Вызывать внутри транзакции
+func (r *IntegrityPostgres) LockedBalance(ctx context.Context, walletId int) (models.BalanceMismatch, error) {
+	var balance models.BalanceMismatch
+	exc := r.transactor.GetExecutor(ctx)
+
+	var id int
+	if err := sqlx.GetContext(ctx, exc, &id, lockWalletQuery, walletId); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return balance, ErrRecordNotFound
+		}
+		return balance, fmt.Errorf("[IntegrityPostgres.LockedBalance] failed locking wallet: %w", err)
+	}
+	if err := sqlx.GetContext(ctx, exc, &balance, walletBalanceQuery, walletId); err != nil {
+		return balance, fmt.Errorf("[IntegrityPostgres.LockedBalance] failed computing balance: %w", err)
+	}
+	return balance, nil
+}
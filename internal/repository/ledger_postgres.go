@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/goonsorrow/finance-tracker-api/internal/models"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+var ErrUnbalancedEntry = errors.New("journal entry does not balance")
+
+const (
+	// DO UPDATE нужен, чтобы RETURNING отдал id уже существующего счёта
+	walletAccountQuery = `INSERT INTO ledger_accounts (user_id, kind, wallet_id, created_at)
+							VALUES ($1, 'wallet', $2, NOW())
+							ON CONFLICT (wallet_id) WHERE wallet_id IS NOT NULL DO UPDATE SET kind = EXCLUDED.kind
+							RETURNING id`
+
+	userAccountQuery = `INSERT INTO ledger_accounts (user_id, kind, currency, created_at)
+							VALUES ($1, $2, $3, NOW())
+							ON CONFLICT (user_id, kind, currency) WHERE wallet_id IS NULL DO UPDATE SET kind = EXCLUDED.kind
+							RETURNING id`
+
+	createEntryQuery = `INSERT INTO journal_entries (user_id, movement_id, created_at)
+							VALUES ($1, $2, NOW())
+							RETURNING id`
+
+	createPostingsQuery = `INSERT INTO postings (entry_id, account_id, amount, currency)
+							SELECT $1, p.account_id, p.amount, p.currency
+							FROM unnest($2::int[], $3::bigint[], $4::text[]) AS p(account_id, amount, currency)`
+
+	// проводки удаляются каскадом вместе с записью
+	deleteEntriesQuery = `DELETE FROM journal_entries WHERE movement_id = ANY($1)`
+
+	// записи всех операций кошелька, включая оставшиеся от операций из корзины
+	deleteWalletEntriesQuery = `DELETE FROM journal_entries e
+							USING movements m
+							WHERE m.id = e.movement_id AND m.wallet_id = $1`
+
+	getWalletMovementsQuery = `SELECT m.id, m.wallet_id, m.user_id, m.type, m.amount, w.currency
+							FROM movements m
+							JOIN wallets w ON w.id = m.wallet_id
+							WHERE m.wallet_id = $1 AND m.deleted_at IS NULL
+							ORDER BY m.id`
+)
+
+type LedgerPostgres struct {
+	db         *sqlx.DB
+	transactor Transactor
+}
+
+func NewLedgerPostgres(db *sqlx.DB, transactor Transactor) *LedgerPostgres {
+	return &LedgerPostgres{db: db, transactor: transactor}
+}
+
+// WalletAccount возвращает счёт кошелька, заводя его при первой проводке
+func (r *LedgerPostgres) WalletAccount(ctx context.Context, userId, walletId int) (int, error) {
+	var id int
+	if err := r.transactor.GetExecutor(ctx).QueryRowxContext(ctx, walletAccountQuery, userId, walletId).Scan(&id); err != nil {
+		return 0, fmt.Errorf("[LedgerPostgres.WalletAccount] failed getting wallet account: %w", err)
+	}
+	return id, nil
+}
+
+// Account возвращает счёт пользователя заданного вида в валюте, заводя его при первой проводке
+func (r *LedgerPostgres) Account(ctx context.Context, userId int, kind, code string) (int, error) {
+	var id int
+	if err := r.transactor.GetExecutor(ctx).QueryRowxContext(ctx, userAccountQuery, userId, kind, code).Scan(&id); err != nil {
+		return 0, fmt.Errorf("[LedgerPostgres.Account] failed getting %s account: %w", kind, err)
+	}
+	return id, nil
+}
+
+// Post записывает запись с проводками. Несбалансированная запись отклоняется здесь,
+// триггер в базе проверяет то же самое при фиксации транзакции
+func (r *LedgerPostgres) Post(ctx context.Context, entry models.JournalEntry) (int64, error) {
+	if !entry.Balanced() {
+		return 0, fmt.Errorf("[LedgerPostgres.Post] movement %d: %w", entry.MovementID, ErrUnbalancedEntry)
+	}
+	exc := r.transactor.GetExecutor(ctx)
+
+	var id int64
+	if err := exc.QueryRowxContext(ctx, createEntryQuery, entry.UserID, entry.MovementID).Scan(&id); err != nil {
+		return 0, fmt.Errorf("[LedgerPostgres.Post] failed creating journal entry: %w", err)
+	}
+
+	accounts := make([]int64, len(entry.Postings))
+	amounts := make([]int64, len(entry.Postings))
+	currencies := make([]string, len(entry.Postings))
+	for i, p := range entry.Postings {
+		accounts[i] = int64(p.AccountID)
+		amounts[i] = p.Amount
+		currencies[i] = p.Currency
+	}
+	if _, err := exc.ExecContext(ctx, createPostingsQuery, id, pq.Array(accounts), pq.Array(amounts), pq.Array(currencies)); err != nil {
+		return 0, fmt.Errorf("[LedgerPostgres.Post] failed creating postings: %w", err)
+	}
+	return id, nil
+}
+
+func (r *LedgerPostgres) Unpost(ctx context.Context, movementIds []int) error {
+	if len(movementIds) == 0 {
+		return nil
+	}
+	if _, err := r.transactor.GetExecutor(ctx).ExecContext(ctx, deleteEntriesQuery, pq.Array(movementIds)); err != nil {
+		return fmt.Errorf("[LedgerPostgres.Unpost] failed deleting journal entries: %w", err)
+	}
+	return nil
+}
+
+func (r *LedgerPostgres) UnpostWallet(ctx context.Context, walletId int) error {
+	if _, err := r.transactor.GetExecutor(ctx).ExecContext(ctx, deleteWalletEntriesQuery, walletId); err != nil {
+		return fmt.Errorf("[LedgerPostgres.UnpostWallet] failed deleting journal entries: %w", err)
+	}
+	return nil
+}
+
+// WalletMovements — живые операции кошелька с полями, нужными для проводки
+func (r *LedgerPostgres) WalletMovements(ctx context.Context, walletId int) ([]models.Movement, error) {
+	var movements []models.Movement
+	if err := sqlx.SelectContext(ctx, r.transactor.GetExecutor(ctx), &movements, getWalletMovementsQuery, walletId); err != nil {
+		return nil, fmt.Errorf("[LedgerPostgres.WalletMovements] failed getting movements: %w", err)
+	}
+	return movements, nil
+}
@@ -2,31 +2,111 @@ package repository
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"fmt"
+	"html"
+	"strings"
+	"time"
 
 	"github.com/goonsorrow/finance-tracker-api/internal/models"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 )
 
 const (
 	createMQuery = `INSERT 
-						INTO movements (wallet_id, user_id, type, amount, category_id, description, date, created_at, updated_at) 
-						VALUES ($1, $2, $3, $4, $5, $6, $7, NOW(), NOW()) 
+						INTO movements (wallet_id, user_id, type, amount, category_id, description, date, transfer_id, external_id, created_at, updated_at) 
+						VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, NOW(), NOW()) 
 						RETURNING id`
 
-	getAllMQuery = `SELECT id, wallet_id, user_id, type, amount, category_id, description, date, created_at, updated_at  
-						FROM movements
-						WHERE user_id = $1 AND wallet_id = $2
-						ORDER BY date`
+	// валюта берётся из кошелька, чтобы сумму можно было отдать в десятичном виде
+	selectMColumns = `SELECT m.id, m.wallet_id, m.user_id, m.type, m.amount, w.currency, m.category_id, m.description, m.date, m.transfer_id, m.external_id, m.created_at, m.updated_at, m.version
+						FROM movements m
+						JOIN wallets w ON w.id = m.wallet_id`
 
-	getMByIdQuery = `SELECT id, wallet_id, user_id, type, amount, category_id, description, date, created_at, updated_at  
-						 FROM movements
-						 WHERE user_id = $1 AND wallet_id = $2 AND id = $3`
+	// для выгрузки: названия кошелька и категории вместо одних id
+	selectMExportColumns = `SELECT m.id, m.wallet_id, m.user_id, m.type, m.amount, w.currency, m.category_id, m.description, m.date, m.transfer_id, m.external_id, m.created_at, m.updated_at,
+							w.name AS wallet_name, c.name AS category_name
+						FROM movements m
+						JOIN wallets w ON w.id = m.wallet_id
+						LEFT JOIN categories c ON c.id = m.category_id`
 
-	deleteMByIdQuery = `DELETE 
-							FROM movements 
-        					WHERE user_id = $1	AND wallet_id = $2 AND id = $3`
+	// совпавшие слова в snippet обрамляются управляющими символами, их в <b></b> превращает
+	// snippetHTML после экранирования описания; длинное описание режется до двух фрагментов
+	searchMColumns = `SELECT m.id, m.wallet_id, m.user_id, m.type, m.amount, w.currency, m.category_id, m.description, m.date, m.transfer_id, m.external_id, m.created_at, m.updated_at,
+							ts_headline('simple', m.description, q.query,
+								'StartSel=' || chr(2) || ', StopSel=' || chr(3) || ', MaxFragments=2, MaxWords=20, MinWords=5') AS snippet,
+							ts_rank(m.search_vector, q.query) AS rank
+						FROM movements m
+						JOIN wallets w ON w.id = m.wallet_id`
+
+	countMQuery = `SELECT COUNT(*) 
+						FROM movements m
+						JOIN wallets w ON w.id = m.wallet_id`
+
+	getMByTransferIdQuery = selectMColumns + `
+						 WHERE m.user_id = $1 AND m.transfer_id = $2 AND m.deleted_at IS NULL
+						 ORDER BY m.id`
+
+	getMByIdQuery = selectMColumns + `
+						 WHERE m.user_id = $1 AND m.wallet_id = $2 AND m.id = $3
+						 AND m.deleted_at IS NULL AND w.deleted_at IS NULL`
+
+	// $4 — ожидаемая версия, NULL отключает проверку
+	deleteMByIdQuery = `UPDATE movements
+							SET deleted_at = NOW(), version = version + 1
+        					WHERE user_id = $1	AND wallet_id = $2 AND id = $3 AND deleted_at IS NULL
+							AND ($4::int IS NULL OR version = $4)`
+
+	// корзина операций: ноги переводов сюда не попадают, они уходят и возвращаются вместе с кошельком
+	selectMDeletedColumns = `SELECT m.id, m.wallet_id, m.user_id, m.type, m.amount, w.currency, m.category_id, m.description, m.date, m.transfer_id, m.external_id, m.created_at, m.updated_at, m.deleted_at, m.version
+						FROM movements m
+						JOIN wallets w ON w.id = m.wallet_id`
+
+	getDeletedMQuery = selectMDeletedColumns + `
+						 WHERE m.user_id = $1 AND m.deleted_at IS NOT NULL AND m.transfer_id IS NULL AND w.deleted_at IS NULL
+						 ORDER BY m.deleted_at DESC, m.id DESC`
+
+	getDeletedMByIdQuery = selectMDeletedColumns + `
+						 WHERE m.user_id = $1 AND m.id = $2 AND m.deleted_at IS NOT NULL AND m.transfer_id IS NULL`
+
+	restoreMByIdQuery = `UPDATE movements
+							SET deleted_at = NULL, version = version + 1
+							WHERE user_id = $1 AND id = $2 AND deleted_at IS NOT NULL AND transfer_id IS NULL`
+
+	// ноги переводов в других кошельках помечаются тем же временем, что и удалённый кошелёк,
+	// чтобы при восстановлении вернуть ровно их
+	trashMTransferLegsQuery = `UPDATE movements m
+							SET deleted_at = w.deleted_at, version = m.version + 1
+							FROM transfers t, wallets w, wallets lw
+							WHERE w.id = $1 AND w.user_id = $2 AND w.deleted_at IS NOT NULL
+							AND m.transfer_id = t.id AND $1 IN (t.from_wallet_id, t.to_wallet_id)
+							AND m.wallet_id <> $1 AND m.deleted_at IS NULL AND lw.id = m.wallet_id
+							RETURNING m.id, m.wallet_id, m.user_id, m.type, m.amount, lw.currency, m.transfer_id`
+
+	restoreMTransferLegsQuery = `UPDATE movements m
+							SET deleted_at = NULL, version = m.version + 1
+							FROM transfers t, wallets w, wallets lw
+							WHERE w.id = $1 AND w.user_id = $2 AND w.deleted_at IS NOT NULL
+							AND m.transfer_id = t.id AND $1 IN (t.from_wallet_id, t.to_wallet_id)
+							AND m.wallet_id <> $1 AND m.deleted_at = w.deleted_at AND lw.id = m.wallet_id
+							RETURNING m.id, m.wallet_id, m.user_id, m.type, m.amount, lw.currency, m.transfer_id`
+
+	// операции из корзины тоже учитываются: иначе повторный импорт выписки
+	// упрётся в уникальный индекс при восстановлении удалённой операции
+	getMExternalIdsQuery = `SELECT external_id
+							FROM movements
+							WHERE wallet_id = $1 AND external_id = ANY($2)`
+
+	// проверяемые операции передаются массивами, совпадения ищутся одним запросом
+	findMDuplicatesQuery = `SELECT p.idx - 1 AS idx, m.id, m.wallet_id, m.user_id, m.type, m.amount, w.currency, m.category_id, m.description, m.date, m.transfer_id, m.external_id, m.created_at, m.updated_at
+							FROM unnest($3::text[], $4::bigint[], $5::timestamp[]) WITH ORDINALITY AS p(type, amount, date, idx)
+							JOIN movements m ON m.user_id = $1 AND m.wallet_id = $2 AND m.deleted_at IS NULL
+								AND m.type = p.type AND m.amount = p.amount
+								AND m.date BETWEEN p.date - $6::float8 * INTERVAL '1 second' AND p.date + $6::float8 * INTERVAL '1 second'
+							JOIN wallets w ON w.id = m.wallet_id
+							ORDER BY p.idx, m.date, m.id`
 
 	updateMByIdQuery = `UPDATE movements 
 							SET type = COALESCE($1,type),
@@ -34,8 +114,27 @@ const (
 							category_id = COALESCE($3,category_id),
 							description = COALESCE($4,description),
 							date = COALESCE($5,date),
-							updated_at = NOW()
-							WHERE user_id = $6 AND wallet_id = $7 AND id = $8`
+							updated_at = NOW(),
+							version = version + 1
+							WHERE user_id = $6 AND wallet_id = $7 AND id = $8 AND deleted_at IS NULL
+							AND ($9::int IS NULL OR version = $9)`
+
+	getMSplitsQuery = `SELECT s.id, s.movement_id, s.category_id, s.amount, w.currency, s.note
+							FROM movement_splits s
+							JOIN movements m ON m.id = s.movement_id
+							JOIN wallets w ON w.id = m.wallet_id
+							WHERE m.user_id = $1 AND s.movement_id = ANY($2)
+							ORDER BY s.movement_id, s.id`
+
+	deleteMSplitsQuery = `DELETE
+							FROM movement_splits
+							WHERE movement_id = $1`
+
+	// строки разбивки передаются массивами и вставляются одним запросом в исходном порядке
+	insertMSplitsQuery = `INSERT INTO movement_splits (movement_id, category_id, amount, note)
+							SELECT $1, s.category_id, s.amount, s.note
+							FROM unnest($2::int[], $3::bigint[], $4::text[]) WITH ORDINALITY AS s(category_id, amount, note, idx)
+							ORDER BY s.idx`
 )
 
 type MovementPostgres struct {
@@ -53,34 +152,293 @@ func (r *MovementPostgres) Create(ctx context.Context, userId, walletId int, inp
 	exc := r.transactor.GetExecutor(ctx)
 
 	err := exc.QueryRowxContext(ctx, createMQuery,
-		walletId,              //$1
-		userId,                //$2
-		input.Type,            //$3
-		input.Amount,          //$4
-		input.CategoryID,      //$5
-		input.Description,     //$6
-		input.Date).Scan(&mId) //$7
+		walletId,                    //$1
+		userId,                      //$2
+		input.Type,                  //$3
+		input.Amount,                //$4
+		input.CategoryID,            //$5
+		input.Description,           //$6
+		input.Date,                  //$7
+		input.TransferID,            //$8
+		input.ExternalID).Scan(&mId) //$9
 	if err != nil {
+		if isUniqueViolation(err) {
+			return 0, fmt.Errorf("[MovementPostgres.Create] movement with external id already exists: %w", ErrDuplicate)
+		}
 		return 0, fmt.Errorf("[MovementPostgres.Create] failed to write down movement: %w", err)
 	}
 
 	return mId, nil
 }
 
-func (r *MovementPostgres) GetAll(ctx context.Context, userId, walletId int) ([]models.Movement, error) {
+func (r *MovementPostgres) GetAll(ctx context.Context, userId int, filter models.MovementFilter) ([]models.Movement, error) {
+	var movements []models.Movement
+
+	exc := r.transactor.GetExecutor(ctx)
+
+	where, args := buildMovementWhere(userId, filter)
+	query := selectMColumns + where + buildMovementOrder(filter) +
+		fmt.Sprintf(" LIMIT $%d OFFSET $%d", len(args)+1, len(args)+2)
+	args = append(args, filter.Limit, filter.Offset)
+
+	err := sqlx.SelectContext(ctx, exc, &movements, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("[MovementPostgres.GetAll] failed getting movements: %w", err)
+	}
+	return movements, nil
+}
+
+func (r *MovementPostgres) Count(ctx context.Context, userId int, filter models.MovementFilter) (int, error) {
+	var total int
+
+	exc := r.transactor.GetExecutor(ctx)
+
+	where, args := buildMovementWhere(userId, filter)
+	err := exc.QueryRowxContext(ctx, countMQuery+where, args...).Scan(&total)
+	if err != nil {
+		return 0, fmt.Errorf("[MovementPostgres.Count] failed counting movements: %w", err)
+	}
+	return total, nil
+}
+
+// GetAfterCursor — keyset-выборка по (date, id), опирается на индекс movements(user_id, date, id)
+func (r *MovementPostgres) GetAfterCursor(ctx context.Context, userId int, filter models.MovementFilter, after *models.MovementCursor) ([]models.Movement, error) {
 	var movements []models.Movement
 
 	exc := r.transactor.GetExecutor(ctx)
 
-	err := sqlx.SelectContext(ctx, exc, &movements, getAllMQuery,
-		userId,   //$1
-		walletId) //$2
+	direction, cmp := "ASC", ">"
+	if filter.SortOrder == "desc" {
+		direction, cmp = "DESC", "<"
+	}
+
+	where, args := buildMovementWhere(userId, filter)
+	if after != nil {
+		where += fmt.Sprintf(" AND (m.date, m.id) %s ($%d, $%d)", cmp, len(args)+1, len(args)+2)
+		args = append(args, after.Date, after.ID)
+	}
+	query := selectMColumns + where +
+		fmt.Sprintf(" ORDER BY m.date %s, m.id %s LIMIT $%d", direction, direction, len(args)+1)
+	args = append(args, filter.Limit)
+
+	err := sqlx.SelectContext(ctx, exc, &movements, query, args...)
 	if err != nil {
-		return nil, fmt.Errorf("failed gettin all movements: %w", err)
+		return nil, fmt.Errorf("[MovementPostgres.GetAfterCursor] failed getting movements page: %w", err)
 	}
 	return movements, nil
 }
 
+// ExistingExternalIDs — какие из идентификаторов банка уже есть в кошельке
+func (r *MovementPostgres) ExistingExternalIDs(ctx context.Context, walletId int, externalIds []string) ([]string, error) {
+	var existing []string
+
+	exc := r.transactor.GetExecutor(ctx)
+
+	err := sqlx.SelectContext(ctx, exc, &existing, getMExternalIdsQuery, walletId, pq.Array(externalIds))
+	if err != nil {
+		return nil, fmt.Errorf("[MovementPostgres.ExistingExternalIDs] failed getting external ids: %w", err)
+	}
+	return existing, nil
+}
+
+// FindDuplicates ищет в кошельке операции того же типа и суммы, что и probes,
+// с датой не дальше window от даты проверяемой операции
+func (r *MovementPostgres) FindDuplicates(ctx context.Context, userId, walletId int, probes []models.Movement, window time.Duration) ([]models.DuplicateMatch, error) {
+	var matches []models.DuplicateMatch
+
+	exc := r.transactor.GetExecutor(ctx)
+
+	types := make([]string, len(probes))
+	amounts := make([]int64, len(probes))
+	dates := make([]string, len(probes))
+	for i, p := range probes {
+		types[i], amounts[i] = p.Type, p.Amount
+		dates[i] = p.Date.UTC().Format("2006-01-02 15:04:05.999999")
+	}
+
+	err := sqlx.SelectContext(ctx, exc, &matches, findMDuplicatesQuery,
+		userId,                  //$1
+		walletId,                //$2
+		pq.Array(types),         //$3
+		pq.Array(amounts),       //$4
+		pq.Array(dates),         //$5
+		int64(window.Seconds())) //$6
+	if err != nil {
+		return nil, fmt.Errorf("[MovementPostgres.FindDuplicates] failed searching duplicates: %w", err)
+	}
+	return matches, nil
+}
+
+// Stream построчно отдаёт все операции по фильтру в fn, не загружая их в память целиком.
+// Limit и Offset фильтра не учитываются.
+func (r *MovementPostgres) Stream(ctx context.Context, userId int, filter models.MovementFilter, fn func(models.ExportMovement) error) error {
+	exc := r.transactor.GetExecutor(ctx)
+
+	where, args := buildMovementWhere(userId, filter)
+	query := selectMExportColumns + where + buildMovementOrder(filter)
+
+	rows, err := exc.QueryxContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("[MovementPostgres.Stream] failed querying movements: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var movement models.ExportMovement
+		if err := rows.StructScan(&movement); err != nil {
+			return fmt.Errorf("[MovementPostgres.Stream] failed scanning movement: %w", err)
+		}
+		if err := fn(movement); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("[MovementPostgres.Stream] failed reading movements: %w", err)
+	}
+	return nil
+}
+
+// Колонки сортировки берутся только из белого списка, значения фильтра уходят плейсхолдерами
+var movementSortColumns = map[string]string{
+	"date":       "m.date",
+	"amount":     "m.amount",
+	"created_at": "m.created_at",
+}
+
+// Search ищет операции по словам описания: каждое слово должно совпасть хотя бы с началом слова в описании.
+// Запрос собирается только из букв и цифр, поэтому пользовательский ввод не может сломать tsquery.
+func (r *MovementPostgres) Search(ctx context.Context, userId int, filter models.MovementFilter, terms []string) ([]models.MovementSearchHit, error) {
+	var hits []models.MovementSearchHit
+
+	prefixes := make([]string, len(terms))
+	for i, term := range terms {
+		prefixes[i] = term + ":*"
+	}
+
+	where, args := buildMovementWhere(userId, filter)
+	args = append(args, strings.Join(prefixes, " & "))
+	from := fmt.Sprintf(" CROSS JOIN to_tsquery('simple', $%d) AS q(query)", len(args))
+	query := searchMColumns + from + where + " AND m.search_vector @@ q.query" +
+		fmt.Sprintf(" ORDER BY rank DESC, m.date DESC, m.id DESC LIMIT $%d OFFSET $%d", len(args)+1, len(args)+2)
+	args = append(args, filter.Limit, filter.Offset)
+
+	err := sqlx.SelectContext(ctx, r.transactor.GetExecutor(ctx), &hits, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("[MovementPostgres.Search] failed searching movements: %w", err)
+	}
+	for i := range hits {
+		hits[i].Snippet = snippetHTML(hits[i].Snippet)
+	}
+	return hits, nil
+}
+
+// метки совпадений, которые ts_headline ставит вместо разметки
+const (
+	snippetStartSel = "\x02"
+	snippetStopSel  = "\x03"
+)
+
+var snippetMarks = strings.NewReplacer(snippetStartSel, "<b>", snippetStopSel, "</b>")
+
+// snippetHTML экранирует описание, введённое пользователем, и только потом выделяет совпадения
+func snippetHTML(headline string) string {
+	return snippetMarks.Replace(html.EscapeString(headline))
+}
+
+// GetSplits отдаёт строки разбивки указанных операций пользователя
+func (r *MovementPostgres) GetSplits(ctx context.Context, userId int, movementIds []int) ([]models.MovementSplit, error) {
+	var splits []models.MovementSplit
+	if len(movementIds) == 0 {
+		return splits, nil
+	}
+	err := sqlx.SelectContext(ctx, r.transactor.GetExecutor(ctx), &splits, getMSplitsQuery, userId, pq.Array(movementIds))
+	if err != nil {
+		return nil, fmt.Errorf("[MovementPostgres.GetSplits] failed getting splits: %w", err)
+	}
+	return splits, nil
+}
+
+// ReplaceSplits заменяет разбивку операции целиком; пустой список её убирает
+func (r *MovementPostgres) ReplaceSplits(ctx context.Context, movementId int, splits []models.MovementSplit) error {
+	exc := r.transactor.GetExecutor(ctx)
+
+	if _, err := exc.ExecContext(ctx, deleteMSplitsQuery, movementId); err != nil {
+		return fmt.Errorf("[MovementPostgres.ReplaceSplits] failed deleting splits: %w", err)
+	}
+	if len(splits) == 0 {
+		return nil
+	}
+
+	categoryIds := make([]int64, len(splits))
+	amounts := make([]int64, len(splits))
+	notes := make([]string, len(splits))
+	for i, split := range splits {
+		categoryIds[i] = int64(split.CategoryID)
+		amounts[i] = split.Amount
+		notes[i] = split.Note
+	}
+	_, err := exc.ExecContext(ctx, insertMSplitsQuery, movementId, pq.Array(categoryIds), pq.Array(amounts), pq.Array(notes))
+	if err != nil {
+		return fmt.Errorf("[MovementPostgres.ReplaceSplits] failed inserting splits: %w", err)
+	}
+	return nil
+}
+
+func buildMovementWhere(userId int, filter models.MovementFilter) (string, []interface{}) {
+	conds := []string{"m.user_id = $1", "m.deleted_at IS NULL", "w.deleted_at IS NULL"}
+	args := []interface{}{userId}
+
+	add := func(cond string, arg interface{}) {
+		args = append(args, arg)
+		conds = append(conds, fmt.Sprintf(cond, len(args)))
+	}
+
+	if filter.WalletID != 0 {
+		add("m.wallet_id = $%d", filter.WalletID)
+	}
+	if filter.Currency != "" {
+		add("w.currency = $%d", filter.Currency)
+	}
+	if filter.Type == "transfer" {
+		conds = append(conds, "m.transfer_id IS NOT NULL")
+	} else if filter.Type != "" {
+		add("m.type = $%d", filter.Type)
+	}
+	if filter.CategoryID != nil {
+		// разбитая операция находится и по категориям своих строк
+		add("(m.category_id = $%[1]d OR EXISTS (SELECT 1 FROM movement_splits s WHERE s.movement_id = m.id AND s.category_id = $%[1]d))", *filter.CategoryID)
+	}
+	if filter.TagID != nil {
+		add("EXISTS (SELECT 1 FROM movement_tags mt WHERE mt.movement_id = m.id AND mt.tag_id = $%d)", *filter.TagID)
+	}
+	if !filter.StartDate.IsZero() {
+		add("m.date >= $%d", filter.StartDate)
+	}
+	if !filter.EndDate.IsZero() {
+		add("m.date < $%d", filter.EndDate)
+	}
+	if filter.MinAmount != nil {
+		add("m.amount >= $%d", *filter.MinAmount)
+	}
+	if filter.MaxAmount != nil {
+		add("m.amount <= $%d", *filter.MaxAmount)
+	}
+
+	return " WHERE " + strings.Join(conds, " AND "), args
+}
+
+func buildMovementOrder(filter models.MovementFilter) string {
+	column, ok := movementSortColumns[filter.SortBy]
+	if !ok {
+		column = "m.date"
+	}
+	direction := "ASC"
+	if filter.SortOrder == "desc" {
+		direction = "DESC"
+	}
+	return fmt.Sprintf(" ORDER BY %s %s, m.id %s", column, direction, direction)
+}
+
 func (r *MovementPostgres) GetById(ctx context.Context, user_id, walletId, movementId int) (models.Movement, error) {
 	var movement models.Movement
 	exc := r.transactor.GetExecutor(ctx)
@@ -95,13 +453,27 @@ func (r *MovementPostgres) GetById(ctx context.Context, user_id, walletId, movem
 	return movement, nil
 }
 
-func (r *MovementPostgres) Delete(ctx context.Context, userId, walletId, movementId int) error {
+func (r *MovementPostgres) GetByTransferId(ctx context.Context, userId, transferId int) ([]models.Movement, error) {
+	var movements []models.Movement
+	exc := r.transactor.GetExecutor(ctx)
+
+	err := sqlx.SelectContext(ctx, exc, &movements, getMByTransferIdQuery,
+		userId,     //$1
+		transferId) //$2
+	if err != nil {
+		return nil, fmt.Errorf("[MovementPostgres.GetByTransferId] failed getting transfer movements: %w", err)
+	}
+	return movements, nil
+}
+
+func (r *MovementPostgres) Delete(ctx context.Context, userId, walletId, movementId int, version *int) error {
 	exc := r.transactor.GetExecutor(ctx)
 
 	res, err := exc.ExecContext(ctx, deleteMByIdQuery,
 		userId,     //$1
 		walletId,   //$2
-		movementId) //$3
+		movementId, //$3
+		version)    //$4
 
 	if err != nil {
 		return fmt.Errorf("[MovementPostgres.Delete] failed to delete movement: %w", err)
@@ -113,6 +485,9 @@ func (r *MovementPostgres) Delete(ctx context.Context, userId, walletId, movemen
 	}
 
 	if rowsAffected == 0 {
+		if version != nil {
+			return ErrVersionConflict
+		}
 		return errors.New("[MovementPostgres.Delete] movement not found")
 	}
 	return nil
@@ -120,7 +495,7 @@ func (r *MovementPostgres) Delete(ctx context.Context, userId, walletId, movemen
 func (r *MovementPostgres) Update(ctx context.Context, userId, walletId, movementId int, input models.UpdateMovementData) error {
 	exc := r.transactor.GetExecutor(ctx)
 
-	_, err := exc.ExecContext(ctx, updateMByIdQuery,
+	res, err := exc.ExecContext(ctx, updateMByIdQuery,
 		input.Type,        // $1
 		input.Amount,      // $2
 		input.CategoryID,  // $3
@@ -128,9 +503,69 @@ func (r *MovementPostgres) Update(ctx context.Context, userId, walletId, movemen
 		input.Date,        // $5
 		userId,            // $6
 		walletId,          // $7
-		movementId)        // $8
+		movementId,        // $8
+		input.Version)     // $9
 	if err != nil {
 		return fmt.Errorf("[MovementPostgres.Update] failed to update movement: %w", err)
 	}
+	if input.Version != nil {
+		if rows, _ := res.RowsAffected(); rows == 0 {
+			return ErrVersionConflict
+		}
+	}
+	return nil
+}
+
+func (r *MovementPostgres) GetDeleted(ctx context.Context, userId int) ([]models.Movement, error) {
+	var movements []models.Movement
+	err := sqlx.SelectContext(ctx, r.transactor.GetExecutor(ctx), &movements, getDeletedMQuery, userId)
+	if err != nil {
+		return nil, fmt.Errorf("[MovementPostgres.GetDeleted] failed getting deleted movements: %w", err)
+	}
+	return movements, nil
+}
+
+func (r *MovementPostgres) GetDeletedById(ctx context.Context, userId, movementId int) (models.Movement, error) {
+	var movement models.Movement
+	err := sqlx.GetContext(ctx, r.transactor.GetExecutor(ctx), &movement, getDeletedMByIdQuery, userId, movementId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Movement{}, ErrRecordNotFound
+		}
+		return models.Movement{}, fmt.Errorf("[MovementPostgres.GetDeletedById] failed getting deleted movement: %w", err)
+	}
+	return movement, nil
+}
+
+func (r *MovementPostgres) Restore(ctx context.Context, userId, movementId int) error {
+	res, err := r.transactor.GetExecutor(ctx).ExecContext(ctx, restoreMByIdQuery, userId, movementId)
+	if err != nil {
+		return fmt.Errorf("[MovementPostgres.Restore] failed restoring movement: %w", err)
+	}
+	rows, _ := res.RowsAffected()
+	if rows == 0 {
+		return ErrRecordNotFound
+	}
 	return nil
 }
+
+// TrashTransferLegs убирает в корзину ноги переводов удалённого кошелька, лежащие в других кошельках,
+// и возвращает их, чтобы сервис снял их с балансов
+func (r *MovementPostgres) TrashTransferLegs(ctx context.Context, userId, walletId int) ([]models.Movement, error) {
+	var legs []models.Movement
+	err := sqlx.SelectContext(ctx, r.transactor.GetExecutor(ctx), &legs, trashMTransferLegsQuery, walletId, userId)
+	if err != nil {
+		return nil, fmt.Errorf("[MovementPostgres.TrashTransferLegs] failed trashing transfer legs: %w", err)
+	}
+	return legs, nil
+}
+
+// RestoreTransferLegs возвращает ноги, убранные вместе с кошельком; вызывается до восстановления самого кошелька
+func (r *MovementPostgres) RestoreTransferLegs(ctx context.Context, userId, walletId int) ([]models.Movement, error) {
+	var legs []models.Movement
+	err := sqlx.SelectContext(ctx, r.transactor.GetExecutor(ctx), &legs, restoreMTransferLegsQuery, walletId, userId)
+	if err != nil {
+		return nil, fmt.Errorf("[MovementPostgres.RestoreTransferLegs] failed restoring transfer legs: %w", err)
+	}
+	return legs, nil
+}
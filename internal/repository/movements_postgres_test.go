@@ -0,0 +1,23 @@
+package repository
+
+import "testing"
+
+func TestSnippetHTML(t *testing.T) {
+	tests := []struct {
+		name     string
+		headline string
+		want     string
+	}{
+		{"plain", "оплата \x02сантехнику\x03 за смеситель", "оплата <b>сантехнику</b> за смеситель"},
+		{"markup in description", "\x02<script>\x03alert(1)</script>", "<b>&lt;script&gt;</b>alert(1)&lt;/script&gt;"},
+		{"entities", "Tom & \"Jerry\"", "Tom &amp; &#34;Jerry&#34;"},
+		{"no match", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := snippetHTML(tt.headline); got != tt.want {
+				t.Errorf("snippetHTML(%q) = %q, want %q", tt.headline, got, tt.want)
+			}
+		})
+	}
+}
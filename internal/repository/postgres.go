@@ -5,10 +5,21 @@ import (
 	"fmt"
 
 	"github.com/jmoiron/sqlx"
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 )
 
-var ErrRecordNotFound = errors.New("record not found")
+var (
+	ErrRecordNotFound = errors.New("record not found")
+	ErrDuplicate      = errors.New("record already exists")
+	// запись изменилась после того, как клиент получил её версию
+	ErrVersionConflict = errors.New("record was changed by another request")
+)
+
+// isUniqueViolation — нарушение UNIQUE-ограничения (SQLSTATE 23505)
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
 
 type Config struct {
 	Host     string
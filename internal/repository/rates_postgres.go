@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/goonsorrow/finance-tracker-api/internal/currency"
+	"github.com/jmoiron/sqlx"
+)
+
+type RatesPostgres struct {
+	db         *sqlx.DB
+	transactor Transactor
+}
+
+func NewRatesPostgres(db *sqlx.DB, transactor Transactor) *RatesPostgres {
+	return &RatesPostgres{db: db, transactor: transactor}
+}
+
+const (
+	upsertRateQuery = `INSERT 
+						INTO exchange_rates (base, quote, date, rate, source)
+						VALUES ($1, $2, $3, $4, $5)
+						ON CONFLICT (base, quote, date) 
+						DO UPDATE SET rate = EXCLUDED.rate, source = EXCLUDED.source`
+
+	getRateQuery = `SELECT base, quote, date, rate, source
+						FROM exchange_rates
+						WHERE base = $1 AND quote = $2 AND date <= $3
+						ORDER BY date DESC
+						LIMIT 1`
+)
+
+func (r *RatesPostgres) SaveRates(ctx context.Context, rates []currency.Rate) error {
+	return r.transactor.WithinTransaction(ctx, func(txCtx context.Context) error {
+		exc := r.transactor.GetExecutor(txCtx)
+		for _, rate := range rates {
+			_, err := exc.ExecContext(txCtx, upsertRateQuery,
+				strings.ToUpper(rate.Base),  //$1
+				strings.ToUpper(rate.Quote), //$2
+				rate.Date,                   //$3
+				rate.Rate,                   //$4
+				rate.Source)                 //$5
+			if err != nil {
+				return fmt.Errorf("[RatesPostgres.SaveRates] failed saving %s/%s: %w", rate.Base, rate.Quote, err)
+			}
+		}
+		return nil
+	})
+}
+
+func (r *RatesPostgres) GetRate(ctx context.Context, base, quote string, date time.Time) (currency.Rate, error) {
+	var rate currency.Rate
+	err := sqlx.GetContext(ctx, r.transactor.GetExecutor(ctx), &rate, getRateQuery,
+		strings.ToUpper(base),  //$1
+		strings.ToUpper(quote), //$2
+		date)                   //$3
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return currency.Rate{}, currency.ErrRateNotFound
+		}
+		return currency.Rate{}, fmt.Errorf("[RatesPostgres.GetRate] failed getting rate: %w", err)
+	}
+	return rate, nil
+}
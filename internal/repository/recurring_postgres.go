@@ -0,0 +1,198 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/goonsorrow/finance-tracker-api/internal/models"
+	"github.com/jmoiron/sqlx"
+)
+
+type RecurringPostgres struct {
+	db         *sqlx.DB
+	transactor Transactor
+}
+
+func NewRecurringPostgres(db *sqlx.DB, transactor Transactor) *RecurringPostgres {
+	return &RecurringPostgres{db: db, transactor: transactor}
+}
+
+const (
+	createRecurringQuery = `INSERT
+							INTO recurring_movements (user_id, wallet_id, type, amount, category_id, description, frequency, interval_count, start_date, end_date, next_date, created_at, updated_at)
+							VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, NOW(), NOW())
+							RETURNING id`
+
+	selectRecurringColumns = `SELECT r.id, r.user_id, r.wallet_id, r.type, r.amount, w.currency, r.category_id, r.description,
+								r.frequency, r.interval_count, r.start_date, r.end_date, r.next_date, r.paused, r.created_at, r.updated_at
+							FROM recurring_movements r
+							JOIN wallets w ON w.id = r.wallet_id`
+
+	getAllRecurringQuery = selectRecurringColumns + `
+							WHERE r.user_id = $1 AND w.deleted_at IS NULL
+							ORDER BY r.next_date, r.id`
+
+	getRecurringByIdQuery = selectRecurringColumns + `
+							WHERE r.user_id = $1 AND r.id = $2 AND w.deleted_at IS NULL`
+
+	// шаблоны всех пользователей, у которых подошло время следующего повторения
+	getDueRecurringQuery = selectRecurringColumns + `
+							WHERE NOT r.paused AND r.next_date <= $1 AND w.deleted_at IS NULL
+							AND (r.end_date IS NULL OR r.next_date <= r.end_date)
+							ORDER BY r.next_date
+							LIMIT $2`
+
+	setRecurringPausedQuery = `UPDATE recurring_movements
+								SET paused = $1, next_date = $2, updated_at = NOW()
+								WHERE user_id = $3 AND id = $4`
+
+	setRecurringNextDateQuery = `UPDATE recurring_movements
+								SET next_date = $1, updated_at = NOW()
+								WHERE id = $2`
+
+	deleteRecurringQuery = `DELETE
+							FROM recurring_movements
+							WHERE user_id = $1 AND id = $2`
+
+	claimOccurrenceQuery = `INSERT
+							INTO recurring_occurrences (recurring_id, occurrence_date, skipped)
+							VALUES ($1, $2, $3)
+							ON CONFLICT (recurring_id, occurrence_date) DO NOTHING`
+
+	setOccurrenceMovementQuery = `UPDATE recurring_occurrences
+									SET movement_id = $1
+									WHERE recurring_id = $2 AND occurrence_date = $3`
+
+	getOccurrencesQuery = `SELECT recurring_id, occurrence_date, movement_id, skipped
+							FROM recurring_occurrences
+							WHERE recurring_id = $1 AND occurrence_date >= $2
+							ORDER BY occurrence_date`
+)
+
+func (r *RecurringPostgres) Create(ctx context.Context, userId int, recurring models.RecurringMovement) (int, error) {
+	var id int
+	err := r.transactor.GetExecutor(ctx).QueryRowxContext(ctx, createRecurringQuery,
+		userId,                       //$1
+		recurring.WalletID,           //$2
+		recurring.Type,               //$3
+		recurring.Amount,             //$4
+		recurring.CategoryID,         //$5
+		recurring.Description,        //$6
+		recurring.Frequency,          //$7
+		recurring.Interval,           //$8
+		recurring.StartDate,          //$9
+		recurring.EndDate,            //$10
+		recurring.NextDate).Scan(&id) //$11
+	if err != nil {
+		return 0, fmt.Errorf("[RecurringPostgres.Create] failed to create recurring movement: %w", err)
+	}
+	return id, nil
+}
+
+func (r *RecurringPostgres) GetAll(ctx context.Context, userId int) ([]models.RecurringMovement, error) {
+	var items []models.RecurringMovement
+	err := sqlx.SelectContext(ctx, r.transactor.GetExecutor(ctx), &items, getAllRecurringQuery, userId)
+	if err != nil {
+		return nil, fmt.Errorf("[RecurringPostgres.GetAll] failed getting recurring movements: %w", err)
+	}
+	return items, nil
+}
+
+func (r *RecurringPostgres) GetById(ctx context.Context, userId, recurringId int) (models.RecurringMovement, error) {
+	var item models.RecurringMovement
+	err := sqlx.GetContext(ctx, r.transactor.GetExecutor(ctx), &item, getRecurringByIdQuery,
+		userId,      //$1
+		recurringId) //$2
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.RecurringMovement{}, ErrRecordNotFound
+		}
+		return models.RecurringMovement{}, fmt.Errorf("[RecurringPostgres.GetById] failed getting recurring movement: %w", err)
+	}
+	return item, nil
+}
+
+func (r *RecurringPostgres) GetDue(ctx context.Context, now time.Time, limit int) ([]models.RecurringMovement, error) {
+	var items []models.RecurringMovement
+	err := sqlx.SelectContext(ctx, r.transactor.GetExecutor(ctx), &items, getDueRecurringQuery,
+		now,   //$1
+		limit) //$2
+	if err != nil {
+		return nil, fmt.Errorf("[RecurringPostgres.GetDue] failed getting due recurring movements: %w", err)
+	}
+	return items, nil
+}
+
+func (r *RecurringPostgres) SetPaused(ctx context.Context, userId, recurringId int, paused bool, nextDate time.Time) error {
+	res, err := r.transactor.GetExecutor(ctx).ExecContext(ctx, setRecurringPausedQuery,
+		paused,      //$1
+		nextDate,    //$2
+		userId,      //$3
+		recurringId) //$4
+	if err != nil {
+		return fmt.Errorf("[RecurringPostgres.SetPaused] failed to update recurring movement: %w", err)
+	}
+	rows, _ := res.RowsAffected()
+	if rows == 0 {
+		return ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *RecurringPostgres) SetNextDate(ctx context.Context, recurringId int, nextDate time.Time) error {
+	_, err := r.transactor.GetExecutor(ctx).ExecContext(ctx, setRecurringNextDateQuery, nextDate, recurringId)
+	if err != nil {
+		return fmt.Errorf("[RecurringPostgres.SetNextDate] failed to move next date: %w", err)
+	}
+	return nil
+}
+
+func (r *RecurringPostgres) Delete(ctx context.Context, userId, recurringId int) error {
+	res, err := r.transactor.GetExecutor(ctx).ExecContext(ctx, deleteRecurringQuery, userId, recurringId)
+	if err != nil {
+		return fmt.Errorf("[RecurringPostgres.Delete] failed to delete recurring movement: %w", err)
+	}
+	rows, _ := res.RowsAffected()
+	if rows == 0 {
+		return ErrRecordNotFound
+	}
+	return nil
+}
+
+// ClaimOccurrence занимает дату повторения. false — дата уже создана или пропущена раньше.
+func (r *RecurringPostgres) ClaimOccurrence(ctx context.Context, recurringId int, date time.Time, skipped bool) (bool, error) {
+	res, err := r.transactor.GetExecutor(ctx).ExecContext(ctx, claimOccurrenceQuery,
+		recurringId, //$1
+		date,        //$2
+		skipped)     //$3
+	if err != nil {
+		return false, fmt.Errorf("[RecurringPostgres.ClaimOccurrence] failed to record occurrence: %w", err)
+	}
+	rows, _ := res.RowsAffected()
+	return rows > 0, nil
+}
+
+func (r *RecurringPostgres) SetOccurrenceMovement(ctx context.Context, recurringId int, date time.Time, movementId int) error {
+	_, err := r.transactor.GetExecutor(ctx).ExecContext(ctx, setOccurrenceMovementQuery,
+		movementId,  //$1
+		recurringId, //$2
+		date)        //$3
+	if err != nil {
+		return fmt.Errorf("[RecurringPostgres.SetOccurrenceMovement] failed to link movement: %w", err)
+	}
+	return nil
+}
+
+func (r *RecurringPostgres) GetOccurrences(ctx context.Context, recurringId int, from time.Time) ([]models.RecurringOccurrence, error) {
+	var occurrences []models.RecurringOccurrence
+	err := sqlx.SelectContext(ctx, r.transactor.GetExecutor(ctx), &occurrences, getOccurrencesQuery,
+		recurringId, //$1
+		from)        //$2
+	if err != nil {
+		return nil, fmt.Errorf("[RecurringPostgres.GetOccurrences] failed getting occurrences: %w", err)
+	}
+	return occurrences, nil
+}
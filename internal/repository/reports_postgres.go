@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/goonsorrow/finance-tracker-api/internal/models"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type ReportPostgres struct {
+	db         *sqlx.DB
+	transactor Transactor
+}
+
+func NewReportPostgres(db *sqlx.DB, transactor Transactor) *ReportPostgres {
+	return &ReportPostgres{db: db, transactor: transactor}
+}
+
+// Суммы группируются по валюте кошелька: складывать разные валюты в SQL нельзя,
+// пересчёт в базовую валюту делает сервис
+const (
+	reportTotalsQuery = `SELECT w.currency,
+							COALESCE(SUM(m.amount) FILTER (WHERE m.type = 'income'), 0) AS income,
+							COALESCE(SUM(m.amount) FILTER (WHERE m.type = 'expense'), 0) AS expense,
+							COUNT(*) AS count,
+							MAX(m.date) AS last_movement
+						FROM movements m
+						JOIN wallets w ON w.id = m.wallet_id`
+
+	// разбитая операция даёт по строке на каждую часть, неразбитая — одну строку со своей категорией
+	reportCategoriesQuery = `SELECT COALESCE(s.category_id, m.category_id) AS category_id, COALESCE(c.name, '') AS name, w.currency,
+								SUM(COALESCE(s.amount, m.amount)) AS total, COUNT(DISTINCT m.id) AS count
+							FROM movements m
+							JOIN wallets w ON w.id = m.wallet_id
+							LEFT JOIN movement_splits s ON s.movement_id = m.id
+							LEFT JOIN categories c ON c.id = COALESCE(s.category_id, m.category_id)`
+
+	// операция с несколькими метками попадает в строку каждой из них
+	reportTagsQuery = `SELECT t.id AS tag_id, t.name, w.currency,
+							SUM(m.amount) AS total, COUNT(*) AS count
+						FROM movements m
+						JOIN wallets w ON w.id = m.wallet_id
+						JOIN movement_tags mt ON mt.movement_id = m.id
+						JOIN tags t ON t.id = mt.tag_id`
+
+	reportMonthlyQuery = `SELECT date_trunc('month', m.date) AS month, w.currency,
+							COALESCE(SUM(m.amount) FILTER (WHERE m.type = 'income'), 0) AS income,
+							COALESCE(SUM(m.amount) FILTER (WHERE m.type = 'expense'), 0) AS expense
+						FROM movements m
+						JOIN wallets w ON w.id = m.wallet_id`
+)
+
+func (r *ReportPostgres) TotalsByCurrency(ctx context.Context, userId int, filter models.ReportFilter) ([]models.CurrencyTotals, error) {
+	var totals []models.CurrencyTotals
+
+	where, args := buildReportWhere(userId, filter)
+	query := reportTotalsQuery + where + " GROUP BY w.currency ORDER BY w.currency"
+
+	err := sqlx.SelectContext(ctx, r.transactor.GetExecutor(ctx), &totals, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("[ReportPostgres.TotalsByCurrency] failed aggregating movements: %w", err)
+	}
+	return totals, nil
+}
+
+func (r *ReportPostgres) TotalsByCategory(ctx context.Context, userId int, movementType string, filter models.ReportFilter) ([]models.CategoryTotals, error) {
+	var totals []models.CategoryTotals
+
+	where, args := buildReportWhere(userId, filter)
+	args = append(args, movementType)
+	query := reportCategoriesQuery + where + fmt.Sprintf(" AND m.type = $%d", len(args)) +
+		" GROUP BY COALESCE(s.category_id, m.category_id), c.name, w.currency ORDER BY total DESC"
+
+	err := sqlx.SelectContext(ctx, r.transactor.GetExecutor(ctx), &totals, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("[ReportPostgres.TotalsByCategory] failed aggregating movements: %w", err)
+	}
+	return totals, nil
+}
+
+func (r *ReportPostgres) TotalsByTag(ctx context.Context, userId int, movementType string, filter models.ReportFilter) ([]models.TagTotals, error) {
+	var totals []models.TagTotals
+
+	where, args := buildReportWhere(userId, filter)
+	args = append(args, movementType)
+	query := reportTagsQuery + where + fmt.Sprintf(" AND m.type = $%d", len(args)) +
+		" GROUP BY t.id, t.name, w.currency ORDER BY total DESC"
+
+	err := sqlx.SelectContext(ctx, r.transactor.GetExecutor(ctx), &totals, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("[ReportPostgres.TotalsByTag] failed aggregating movements: %w", err)
+	}
+	return totals, nil
+}
+
+func (r *ReportPostgres) TotalsByMonth(ctx context.Context, userId int, filter models.ReportFilter) ([]models.MonthlyTotals, error) {
+	var totals []models.MonthlyTotals
+
+	where, args := buildReportWhere(userId, filter)
+	query := reportMonthlyQuery + where + " GROUP BY month, w.currency ORDER BY month, w.currency"
+
+	err := sqlx.SelectContext(ctx, r.transactor.GetExecutor(ctx), &totals, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("[ReportPostgres.TotalsByMonth] failed aggregating movements: %w", err)
+	}
+	return totals, nil
+}
+
+// В отчёты попадают только доходы и расходы: ноги переводов, начальные остатки и корзина исключены
+func buildReportWhere(userId int, filter models.ReportFilter) (string, []interface{}) {
+	conds := []string{"m.user_id = $1", "m.type IN ('income', 'expense')", "m.deleted_at IS NULL", "w.deleted_at IS NULL"}
+	args := []interface{}{userId}
+
+	add := func(cond string, arg interface{}) {
+		args = append(args, arg)
+		conds = append(conds, fmt.Sprintf(cond, len(args)))
+	}
+
+	if len(filter.WalletIDs) > 0 {
+		add("m.wallet_id = ANY($%d)", pq.Array(filter.WalletIDs))
+	}
+	if !filter.StartDate.IsZero() {
+		add("m.date >= $%d", filter.StartDate)
+	}
+	if !filter.EndDate.IsZero() {
+		add("m.date < $%d", filter.EndDate)
+	}
+
+	return " WHERE " + strings.Join(conds, " AND "), args
+}
@@ -2,7 +2,9 @@ package repository
 
 import (
 	"context"
+	"time"
 
+	"github.com/goonsorrow/finance-tracker-api/internal/currency"
 	"github.com/goonsorrow/finance-tracker-api/internal/models"
 	"github.com/jmoiron/sqlx"
 )
@@ -24,24 +26,147 @@ type Wallet interface {
 	Create(ctx context.Context, userId int, wallet models.Wallet) (int, error)
 	GetAll(ctx context.Context, userId int) ([]models.Wallet, error)
 	GetById(ctx context.Context, userId, walletId int) (models.Wallet, error)
-	Update(ctx context.Context, userId, walletId int, input models.UpdateWalletInput) error
-	AddToBalance(ctx context.Context, walletId int, deltaCents int64) error
-	Delete(ctx context.Context, userId, walletId int) error
+	HasHistory(ctx context.Context, walletId int) (bool, error)
+	Update(ctx context.Context, userId, walletId int, input models.UpdateWalletInput, version *int) error
+	Delete(ctx context.Context, userId, walletId int, version *int) error
+	GetDeleted(ctx context.Context, userId int) ([]models.Wallet, error)
+	Restore(ctx context.Context, userId, walletId int) error
 }
 type Movement interface {
 	Create(ctx context.Context, userId, walletId int, movement models.Movement) (int, error)
-	GetAll(ctx context.Context, userId, walletId int) ([]models.Movement, error)
+	GetAll(ctx context.Context, userId int, filter models.MovementFilter) ([]models.Movement, error)
+	Count(ctx context.Context, userId int, filter models.MovementFilter) (int, error)
+	GetAfterCursor(ctx context.Context, userId int, filter models.MovementFilter, after *models.MovementCursor) ([]models.Movement, error)
 	GetById(ctx context.Context, userId, walletId, movementId int) (models.Movement, error)
+	GetByTransferId(ctx context.Context, userId, transferId int) ([]models.Movement, error)
+	ExistingExternalIDs(ctx context.Context, walletId int, externalIds []string) ([]string, error)
+	FindDuplicates(ctx context.Context, userId, walletId int, probes []models.Movement, window time.Duration) ([]models.DuplicateMatch, error)
+	Stream(ctx context.Context, userId int, filter models.MovementFilter, fn func(models.ExportMovement) error) error
+	Search(ctx context.Context, userId int, filter models.MovementFilter, terms []string) ([]models.MovementSearchHit, error)
+	GetSplits(ctx context.Context, userId int, movementIds []int) ([]models.MovementSplit, error)
+	ReplaceSplits(ctx context.Context, movementId int, splits []models.MovementSplit) error
 	Update(ctx context.Context, userId, walletId, movementId int, input models.UpdateMovementData) error
-	Delete(ctx context.Context, userId, walletId, movementId int) error
+	Delete(ctx context.Context, userId, walletId, movementId int, version *int) error
+	GetDeleted(ctx context.Context, userId int) ([]models.Movement, error)
+	GetDeletedById(ctx context.Context, userId, movementId int) (models.Movement, error)
+	Restore(ctx context.Context, userId, movementId int) error
+	TrashTransferLegs(ctx context.Context, userId, walletId int) ([]models.Movement, error)
+	RestoreTransferLegs(ctx context.Context, userId, walletId int) ([]models.Movement, error)
+}
+
+type Transfer interface {
+	Create(ctx context.Context, userId int, transfer models.Transfer) (int, error)
+	GetAll(ctx context.Context, userId int) ([]models.Transfer, error)
+	GetById(ctx context.Context, userId, transferId int) (models.Transfer, error)
+	Update(ctx context.Context, userId, transferId int, input models.UpdateTransferData) error
+	Delete(ctx context.Context, userId, transferId int) error
+	FXTransfers(ctx context.Context, userId int, from, to *time.Time) ([]models.Transfer, error)
+}
+
+type Report interface {
+	TotalsByCurrency(ctx context.Context, userId int, filter models.ReportFilter) ([]models.CurrencyTotals, error)
+	TotalsByCategory(ctx context.Context, userId int, movementType string, filter models.ReportFilter) ([]models.CategoryTotals, error)
+	TotalsByMonth(ctx context.Context, userId int, filter models.ReportFilter) ([]models.MonthlyTotals, error)
+	TotalsByTag(ctx context.Context, userId int, movementType string, filter models.ReportFilter) ([]models.TagTotals, error)
+}
+
+type Budget interface {
+	Create(ctx context.Context, userId int, budget models.Budget) (int, error)
+	GetAll(ctx context.Context, userId int) ([]models.Budget, error)
+	GetById(ctx context.Context, userId, budgetId int) (models.Budget, error)
+	Update(ctx context.Context, userId, budgetId int, input models.UpdateBudgetData) error
+	Delete(ctx context.Context, userId, budgetId int) error
+	SpentByMonth(ctx context.Context, userId, categoryId int, from, to time.Time) ([]models.CategorySpend, error)
+}
+
+type Recurring interface {
+	Create(ctx context.Context, userId int, recurring models.RecurringMovement) (int, error)
+	GetAll(ctx context.Context, userId int) ([]models.RecurringMovement, error)
+	GetById(ctx context.Context, userId, recurringId int) (models.RecurringMovement, error)
+	GetDue(ctx context.Context, now time.Time, limit int) ([]models.RecurringMovement, error)
+	SetPaused(ctx context.Context, userId, recurringId int, paused bool, nextDate time.Time) error
+	SetNextDate(ctx context.Context, recurringId int, nextDate time.Time) error
+	Delete(ctx context.Context, userId, recurringId int) error
+	ClaimOccurrence(ctx context.Context, recurringId int, date time.Time, skipped bool) (bool, error)
+	SetOccurrenceMovement(ctx context.Context, recurringId int, date time.Time, movementId int) error
+	GetOccurrences(ctx context.Context, recurringId int, from time.Time) ([]models.RecurringOccurrence, error)
+}
+
+// Восстановление данных пользователя из архива
+type Backup interface {
+	IsAccountEmpty(ctx context.Context, userId int) (bool, error)
+	GetCategories(ctx context.Context, userId int) ([]models.Category, error)
+	RestoreCategory(ctx context.Context, userId int, category models.Category) (int, error)
+	RestoreTag(ctx context.Context, userId int, tag models.Tag) (int, error)
+	RestoreWallet(ctx context.Context, userId int, wallet models.Wallet) (int, error)
+	RestoreTransfer(ctx context.Context, userId int, transfer models.Transfer) (int, error)
+	RestoreMovement(ctx context.Context, userId int, movement models.Movement) (int, error)
+	RestoreBudget(ctx context.Context, userId int, budget models.Budget) error
+	RestoreRecurring(ctx context.Context, userId int, recurring models.RecurringMovement) error
+	SetBaseCurrency(ctx context.Context, userId int, code string) error
+}
+
+type Rates interface {
+	SaveRates(ctx context.Context, rates []currency.Rate) error
+	GetRate(ctx context.Context, base, quote string, date time.Time) (currency.Rate, error)
 }
 
 type Category interface {
 	Create(ctx context.Context, userId int, category models.Category) (int, error)
 	GetAll(ctx context.Context, userId int) ([]models.Category, error)
 	GetById(ctx context.Context, userId, categoryId int) (models.Category, error)
-	Update(ctx context.Context, userId, categoryId int, input models.UpdateCategoryInput) error
-	Delete(ctx context.Context, userId, categoryId int) error
+	Update(ctx context.Context, userId, categoryId int, input models.UpdateCategoryInput, version *int) error
+	Delete(ctx context.Context, userId, categoryId int, version *int) error
+	GetDeleted(ctx context.Context, userId int) ([]models.Category, error)
+	Restore(ctx context.Context, userId, categoryId int) error
+}
+
+type Tag interface {
+	Create(ctx context.Context, userId int, tag models.Tag) (int, error)
+	GetAll(ctx context.Context, userId int) ([]models.Tag, error)
+	GetById(ctx context.Context, userId, tagId int) (models.Tag, error)
+	Update(ctx context.Context, userId, tagId int, input models.UpdateTagInput) error
+	Delete(ctx context.Context, userId, tagId int) error
+	OwnedIDs(ctx context.Context, userId int, ids []int) ([]int, error)
+	GetByMovements(ctx context.Context, userId int, movementIds []int) ([]models.MovementTag, error)
+	SetMovementTags(ctx context.Context, movementId int, tagIds []int) error
+}
+
+type Attachment interface {
+	Create(ctx context.Context, attachment *models.Attachment) error
+	GetAll(ctx context.Context, userId, movementId int) ([]models.Attachment, error)
+	GetById(ctx context.Context, userId, movementId, attachmentId int) (models.Attachment, error)
+	Delete(ctx context.Context, userId, attachmentId int) error
+	KeysByMovements(ctx context.Context, movementIds []int) ([]string, error)
+	ExpiredKeys(ctx context.Context, retention time.Duration) ([]string, error)
+}
+
+// Журнал изменений только пополняется
+type Audit interface {
+	Create(ctx context.Context, entry models.AuditEntry) error
+	GetByEntity(ctx context.Context, userId int, entity string, entityId, limit, offset int) ([]models.AuditEntry, error)
+}
+
+// Сверка сохранённых балансов кошельков с суммами операций
+type Integrity interface {
+	BalanceMismatches(ctx context.Context) ([]models.BalanceMismatch, error)
+	LockedBalance(ctx context.Context, walletId int) (models.BalanceMismatch, error)
+}
+
+// Журнал двойной записи: каждая живая операция проведена одной записью,
+// баланс кошелька — сумма проводок по его счёту
+type Ledger interface {
+	WalletAccount(ctx context.Context, userId, walletId int) (int, error)
+	Account(ctx context.Context, userId int, kind, code string) (int, error)
+	Post(ctx context.Context, entry models.JournalEntry) (int64, error)
+	Unpost(ctx context.Context, movementIds []int) error
+	UnpostWallet(ctx context.Context, walletId int) error
+	WalletMovements(ctx context.Context, walletId int) ([]models.Movement, error)
+}
+
+// Окончательное удаление записей, пролежавших в корзине дольше срока хранения
+type Trash interface {
+	Purge(ctx context.Context, retention time.Duration) (models.PurgeResult, error)
 }
 
 type Repository struct {
@@ -49,7 +174,19 @@ type Repository struct {
 	Authorization
 	Wallet
 	Movement
+	Transfer
 	Category
+	Tag
+	Attachment
+	Rates
+	Report
+	Budget
+	Recurring
+	Backup
+	Trash
+	Audit
+	Integrity
+	Ledger
 }
 
 func NewRepository(db *sqlx.DB) *Repository {
@@ -59,6 +196,18 @@ func NewRepository(db *sqlx.DB) *Repository {
 		Authorization: NewAuthPostgres(db),
 		Wallet:        NewWalletPostgres(db, transactor),
 		Movement:      NewMovementPostgres(db, transactor),
+		Transfer:      NewTransferPostgres(db, transactor),
 		Category:      NewCategoryPostgres(db, transactor),
+		Tag:           NewTagPostgres(db, transactor),
+		Attachment:    NewAttachmentPostgres(db, transactor),
+		Rates:         NewRatesPostgres(db, transactor),
+		Report:        NewReportPostgres(db, transactor),
+		Budget:        NewBudgetPostgres(db, transactor),
+		Recurring:     NewRecurringPostgres(db, transactor),
+		Backup:        NewBackupPostgres(db, transactor),
+		Trash:         NewTrashPostgres(db, transactor),
+		Audit:         NewAuditPostgres(db, transactor),
+		Integrity:     NewIntegrityPostgres(db, transactor),
+		Ledger:        NewLedgerPostgres(db, transactor),
 	}
 }
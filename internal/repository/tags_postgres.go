@@ -0,0 +1,173 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/goonsorrow/finance-tracker-api/internal/models"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+const (
+	createTagQuery = `INSERT INTO tags (user_id, name, color, created_at, updated_at)
+							VALUES ($1, $2, $3, NOW(), NOW())
+							RETURNING id`
+
+	selectTagColumns = `SELECT t.id, t.user_id, t.name, t.color, t.created_at, t.updated_at,
+							(SELECT COUNT(*) FROM movement_tags mt
+								JOIN movements m ON m.id = mt.movement_id
+								JOIN wallets w ON w.id = m.wallet_id
+								WHERE mt.tag_id = t.id AND m.deleted_at IS NULL AND w.deleted_at IS NULL) AS movement_count
+						FROM tags t`
+
+	getAllTagsQuery = selectTagColumns + `
+						WHERE t.user_id = $1
+						ORDER BY t.name`
+
+	getTagByIdQuery = selectTagColumns + `
+						WHERE t.user_id = $1 AND t.id = $2`
+
+	updateTagByIdQuery = `UPDATE tags
+							SET name = COALESCE($1, name),
+								color = COALESCE($2, color),
+								updated_at = NOW()
+							WHERE user_id = $3 AND id = $4`
+
+	deleteTagByIdQuery = `DELETE
+							FROM tags
+							WHERE user_id = $1 AND id = $2`
+
+	getOwnedTagIdsQuery = `SELECT id
+							FROM tags
+							WHERE user_id = $1 AND id = ANY($2)`
+
+	getMovementTagsQuery = `SELECT mt.movement_id, t.id, t.user_id, t.name, t.color, t.created_at, t.updated_at
+							FROM movement_tags mt
+							JOIN tags t ON t.id = mt.tag_id
+							WHERE t.user_id = $1 AND mt.movement_id = ANY($2)
+							ORDER BY mt.movement_id, t.name`
+
+	deleteMovementTagsQuery = `DELETE
+							FROM movement_tags
+							WHERE movement_id = $1`
+
+	insertMovementTagsQuery = `INSERT INTO movement_tags (movement_id, tag_id)
+							SELECT $1, unnest($2::int[])
+							ON CONFLICT DO NOTHING`
+)
+
+type TagPostgres struct {
+	db         *sqlx.DB
+	transactor Transactor
+}
+
+func NewTagPostgres(db *sqlx.DB, transactor Transactor) *TagPostgres {
+	return &TagPostgres{db: db, transactor: transactor}
+}
+
+func (r *TagPostgres) Create(ctx context.Context, userId int, tag models.Tag) (int, error) {
+	var id int
+	err := r.transactor.GetExecutor(ctx).QueryRowxContext(ctx, createTagQuery,
+		userId,              //$1
+		tag.Name,            //$2
+		tag.Color).Scan(&id) //$3
+	if isUniqueViolation(err) {
+		return 0, fmt.Errorf("[TagPostgres.Create] tag %q: %w", tag.Name, ErrDuplicate)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("[TagPostgres.Create] failed creating tag: %w", err)
+	}
+	return id, nil
+}
+
+func (r *TagPostgres) GetAll(ctx context.Context, userId int) ([]models.Tag, error) {
+	var tags []models.Tag
+	if err := sqlx.SelectContext(ctx, r.transactor.GetExecutor(ctx), &tags, getAllTagsQuery, userId); err != nil {
+		return nil, fmt.Errorf("[TagPostgres.GetAll] failed getting tags: %w", err)
+	}
+	return tags, nil
+}
+
+func (r *TagPostgres) GetById(ctx context.Context, userId, tagId int) (models.Tag, error) {
+	var tag models.Tag
+	err := sqlx.GetContext(ctx, r.transactor.GetExecutor(ctx), &tag, getTagByIdQuery, userId, tagId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Tag{}, ErrRecordNotFound
+		}
+		return models.Tag{}, fmt.Errorf("[TagPostgres.GetById] failed getting tag: %w", err)
+	}
+	return tag, nil
+}
+
+func (r *TagPostgres) Update(ctx context.Context, userId, tagId int, input models.UpdateTagInput) error {
+	res, err := r.transactor.GetExecutor(ctx).ExecContext(ctx, updateTagByIdQuery, input.Name, input.Color, userId, tagId)
+	if isUniqueViolation(err) {
+		return fmt.Errorf("[TagPostgres.Update] tag %q: %w", *input.Name, ErrDuplicate)
+	}
+	if err != nil {
+		return fmt.Errorf("[TagPostgres.Update] failed updating tag: %w", err)
+	}
+	rows, _ := res.RowsAffected()
+	if rows == 0 {
+		return ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *TagPostgres) Delete(ctx context.Context, userId, tagId int) error {
+	res, err := r.transactor.GetExecutor(ctx).ExecContext(ctx, deleteTagByIdQuery, userId, tagId)
+	if err != nil {
+		return fmt.Errorf("[TagPostgres.Delete] failed deleting tag: %w", err)
+	}
+	rows, _ := res.RowsAffected()
+	if rows == 0 {
+		return ErrRecordNotFound
+	}
+	return nil
+}
+
+// OwnedIDs отдаёт те из ids, что принадлежат пользователю
+func (r *TagPostgres) OwnedIDs(ctx context.Context, userId int, ids []int) ([]int, error) {
+	var owned []int
+	if len(ids) == 0 {
+		return owned, nil
+	}
+	err := sqlx.SelectContext(ctx, r.transactor.GetExecutor(ctx), &owned, getOwnedTagIdsQuery, userId, pq.Array(ids))
+	if err != nil {
+		return nil, fmt.Errorf("[TagPostgres.OwnedIDs] failed checking tags: %w", err)
+	}
+	return owned, nil
+}
+
+// GetByMovements отдаёт метки указанных операций пользователя
+func (r *TagPostgres) GetByMovements(ctx context.Context, userId int, movementIds []int) ([]models.MovementTag, error) {
+	var tags []models.MovementTag
+	if len(movementIds) == 0 {
+		return tags, nil
+	}
+	err := sqlx.SelectContext(ctx, r.transactor.GetExecutor(ctx), &tags, getMovementTagsQuery, userId, pq.Array(movementIds))
+	if err != nil {
+		return nil, fmt.Errorf("[TagPostgres.GetByMovements] failed getting movement tags: %w", err)
+	}
+	return tags, nil
+}
+
+// SetMovementTags заменяет метки операции целиком; пустой список их снимает
+func (r *TagPostgres) SetMovementTags(ctx context.Context, movementId int, tagIds []int) error {
+	exc := r.transactor.GetExecutor(ctx)
+
+	if _, err := exc.ExecContext(ctx, deleteMovementTagsQuery, movementId); err != nil {
+		return fmt.Errorf("[TagPostgres.SetMovementTags] failed deleting movement tags: %w", err)
+	}
+	if len(tagIds) == 0 {
+		return nil
+	}
+	if _, err := exc.ExecContext(ctx, insertMovementTagsQuery, movementId, pq.Array(tagIds)); err != nil {
+		return fmt.Errorf("[TagPostgres.SetMovementTags] failed inserting movement tags: %w", err)
+	}
+	return nil
+}
@@ -24,7 +24,12 @@ func (t *TransactorPostgres) GetExecutor(ctx context.Context) sqlx.ExtContext {
 	return t.db
 }
 
+// WithinTransaction выполняет fn в транзакции. Если в ctx транзакция уже открыта,
+// fn выполняется в ней: так сервисы можно вызывать друг из друга внутри одной транзакции.
 func (t *TransactorPostgres) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if GetTxFromContext(ctx) != nil {
+		return fn(ctx)
+	}
 
 	tx, err := t.db.BeginTxx(ctx, nil)
 	if err != nil {
@@ -0,0 +1,147 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/goonsorrow/finance-tracker-api/internal/models"
+	"github.com/jmoiron/sqlx"
+)
+
+type TransferPostgres struct {
+	db         *sqlx.DB
+	transactor Transactor
+}
+
+func NewTransferPostgres(db *sqlx.DB, transactor Transactor) *TransferPostgres {
+	return &TransferPostgres{db: db, transactor: transactor}
+}
+
+// перевод, у которого один из кошельков в корзине, скрыт вместе с кошельком
+const liveTransferCond = `NOT EXISTS (SELECT 1 FROM wallets w
+							WHERE w.id IN (transfers.from_wallet_id, transfers.to_wallet_id) AND w.deleted_at IS NOT NULL)`
+
+const (
+	createTransferQuery = `INSERT 
+							INTO transfers (user_id, from_wallet_id, to_wallet_id, from_amount, to_amount, from_currency, to_currency, rate, description, date, created_at, updated_at)
+							VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, NOW(), NOW())
+							RETURNING id`
+
+	getAllTransfersQuery = `SELECT id, user_id, from_wallet_id, to_wallet_id, from_amount, to_amount, from_currency, to_currency, rate, description, date, created_at, updated_at
+							FROM transfers
+							WHERE user_id = $1 AND ` + liveTransferCond + `
+							ORDER BY date DESC, id DESC`
+
+	getTransferByIdQuery = `SELECT id, user_id, from_wallet_id, to_wallet_id, from_amount, to_amount, from_currency, to_currency, rate, description, date, created_at, updated_at
+							FROM transfers
+							WHERE user_id = $1 AND id = $2 AND ` + liveTransferCond
+
+	updateTransferByIdQuery = `UPDATE transfers
+								SET from_amount = COALESCE($1, from_amount),
+									to_amount = COALESCE($2, to_amount),
+									rate = COALESCE($3, rate),
+									description = COALESCE($4, description),
+									date = COALESCE($5, date),
+									updated_at = NOW()
+								WHERE user_id = $6 AND id = $7 AND ` + liveTransferCond
+
+	// обмены за период по парам валют: каждый перевод оценивается по курсу на свою дату
+	fxTransfersQuery = `SELECT id, user_id, from_wallet_id, to_wallet_id, from_amount, to_amount, from_currency, to_currency, rate, description, date, created_at, updated_at
+						FROM transfers
+						WHERE user_id = $1 AND from_currency <> to_currency AND ` + liveTransferCond + `
+						AND ($2::timestamp IS NULL OR date >= $2)
+						AND ($3::timestamp IS NULL OR date < $3)
+						ORDER BY from_currency, to_currency, date, id`
+
+	deleteTransferByIdQuery = `DELETE
+								FROM transfers
+								WHERE user_id = $1 AND id = $2 AND ` + liveTransferCond
+)
+
+func (r *TransferPostgres) Create(ctx context.Context, userId int, transfer models.Transfer) (int, error) {
+	var id int
+	err := r.transactor.GetExecutor(ctx).QueryRowxContext(ctx, createTransferQuery,
+		userId,                  //$1
+		transfer.FromWalletID,   //$2
+		transfer.ToWalletID,     //$3
+		transfer.FromAmount,     //$4
+		transfer.ToAmount,       //$5
+		transfer.FromCurrency,   //$6
+		transfer.ToCurrency,     //$7
+		transfer.Rate,           //$8
+		transfer.Description,    //$9
+		transfer.Date).Scan(&id) //$10
+	if err != nil {
+		return 0, fmt.Errorf("[TransferPostgres.Create] failed to create transfer: %w", err)
+	}
+	return id, nil
+}
+
+func (r *TransferPostgres) GetAll(ctx context.Context, userId int) ([]models.Transfer, error) {
+	var transfers []models.Transfer
+	err := sqlx.SelectContext(ctx, r.transactor.GetExecutor(ctx), &transfers, getAllTransfersQuery, userId)
+	if err != nil {
+		return nil, fmt.Errorf("[TransferPostgres.GetAll] failed getting transfers: %w", err)
+	}
+	return transfers, nil
+}
+
+func (r *TransferPostgres) GetById(ctx context.Context, userId, transferId int) (models.Transfer, error) {
+	var transfer models.Transfer
+	err := sqlx.GetContext(ctx, r.transactor.GetExecutor(ctx), &transfer, getTransferByIdQuery,
+		userId,     //$1
+		transferId) //$2
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Transfer{}, ErrRecordNotFound
+		}
+		return models.Transfer{}, fmt.Errorf("[TransferPostgres.GetById] failed getting transfer: %w", err)
+	}
+	return transfer, nil
+}
+
+func (r *TransferPostgres) Update(ctx context.Context, userId, transferId int, input models.UpdateTransferData) error {
+	res, err := r.transactor.GetExecutor(ctx).ExecContext(ctx, updateTransferByIdQuery,
+		input.FromAmount,  //$1
+		input.ToAmount,    //$2
+		input.Rate,        //$3
+		input.Description, //$4
+		input.Date,        //$5
+		userId,            //$6
+		transferId)        //$7
+	if err != nil {
+		return fmt.Errorf("[TransferPostgres.Update] failed to update transfer: %w", err)
+	}
+	rows, _ := res.RowsAffected()
+	if rows == 0 {
+		return ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *TransferPostgres) Delete(ctx context.Context, userId, transferId int) error {
+	res, err := r.transactor.GetExecutor(ctx).ExecContext(ctx, deleteTransferByIdQuery, userId, transferId)
+	if err != nil {
+		return fmt.Errorf("[TransferPostgres.Delete] failed to delete transfer: %w", err)
+	}
+	rows, _ := res.RowsAffected()
+	if rows == 0 {
+		return ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *TransferPostgres) FXTransfers(ctx context.Context, userId int, from, to *time.Time) ([]models.Transfer, error) {
+	var transfers []models.Transfer
+	err := sqlx.SelectContext(ctx, r.transactor.GetExecutor(ctx), &transfers, fxTransfersQuery,
+		userId, //$1
+		from,   //$2
+		to)     //$3
+	if err != nil {
+		return nil, fmt.Errorf("[TransferPostgres.FXTransfers] failed getting transfers: %w", err)
+	}
+	return transfers, nil
+}
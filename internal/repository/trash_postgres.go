@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/goonsorrow/finance-tracker-api/internal/models"
+	"github.com/jmoiron/sqlx"
+)
+
+// Все запросы считают срок от NOW(), который внутри транзакции не меняется,
+// поэтому ключи вложений, собранные до очистки, совпадают с удалёнными строками
+const (
+	// ноги переводов удаляются вместе с кошельком, а не по своему deleted_at
+	purgeMovementsQuery = `DELETE
+							FROM movements
+							WHERE deleted_at < NOW() - $1::float8 * INTERVAL '1 second' AND transfer_id IS NULL`
+
+	// переводы не удаляются каскадом вместе с кошельком, их убирают заранее; ноги
+	// в другом кошельке сняты с журнала ещё при удалении кошелька в корзину
+	purgeTransfersQuery = `DELETE
+							FROM transfers t
+							USING wallets w
+							WHERE w.id IN (t.from_wallet_id, t.to_wallet_id)
+							AND w.deleted_at < NOW() - $1::float8 * INTERVAL '1 second'`
+
+	// операции кошелька удаляются каскадом
+	purgeWalletsQuery = `DELETE
+							FROM wallets
+							WHERE deleted_at < NOW() - $1::float8 * INTERVAL '1 second'`
+
+	// категорию, на которую ещё ссылаются операции, оставляем в корзине
+	purgeCategoriesQuery = `DELETE
+							FROM categories c
+							WHERE c.deleted_at < NOW() - $1::float8 * INTERVAL '1 second'
+							AND NOT EXISTS (SELECT 1 FROM movements m WHERE m.category_id = c.id)
+							AND NOT EXISTS (SELECT 1 FROM movement_splits s WHERE s.category_id = c.id)`
+)
+
+type TrashPostgres struct {
+	db         *sqlx.DB
+	transactor Transactor
+}
+
+func NewTrashPostgres(db *sqlx.DB, transactor Transactor) *TrashPostgres {
+	return &TrashPostgres{db: db, transactor: transactor}
+}
+
+func (r *TrashPostgres) Purge(ctx context.Context, retention time.Duration) (models.PurgeResult, error) {
+	var result models.PurgeResult
+	exc := r.transactor.GetExecutor(ctx)
+
+	steps := []struct {
+		name  string
+		query string
+		count *int
+	}{
+		{"movements", purgeMovementsQuery, &result.Movements},
+		{"transfers", purgeTransfersQuery, &result.Transfers},
+		{"wallets", purgeWalletsQuery, &result.Wallets},
+		{"categories", purgeCategoriesQuery, &result.Categories},
+	}
+	for _, step := range steps {
+		res, err := exc.ExecContext(ctx, step.query, retention.Seconds())
+		if err != nil {
+			return result, fmt.Errorf("[TrashPostgres.Purge] failed purging %s: %w", step.name, err)
+		}
+		rows, _ := res.RowsAffected()
+		*step.count = int(rows)
+	}
+	return result, nil
+}
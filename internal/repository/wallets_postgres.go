@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"fmt"
 
@@ -19,49 +20,64 @@ func NewWalletPostgres(db *sqlx.DB, transactor Transactor) *WalletPostgres {
 }
 
 const (
-	getAllQuery = `
+	// баланс считается по проводкам журнала; у кошелька без проводок его нет в wallet_balances
+	selectWalletColumns = `
         SELECT 
-            id, user_id, name, currency, balance, created_at, updated_at
-        FROM wallets
-        WHERE user_id = $1
-        ORDER BY created_at DESC`
+            w.id, w.user_id, w.name, w.currency, COALESCE(b.balance, 0) AS balance, w.created_at, w.updated_at, w.deleted_at, w.version
+        FROM wallets w
+        LEFT JOIN wallet_balances b ON b.wallet_id = w.id`
 
-	getByIdQuery = `
-        SELECT 
-            id, user_id, name, currency, balance, created_at, updated_at
-        FROM wallets
-        WHERE user_id = $1 AND id = $2`
+	getAllQuery = selectWalletColumns + `
+        WHERE w.user_id = $1 AND w.deleted_at IS NULL
+        ORDER BY w.created_at DESC`
+
+	getByIdQuery = selectWalletColumns + `
+        WHERE w.user_id = $1 AND w.id = $2 AND w.deleted_at IS NULL`
+
+	getDeletedWalletsQuery = selectWalletColumns + `
+        WHERE w.user_id = $1 AND w.deleted_at IS NOT NULL
+        ORDER BY w.deleted_at DESC`
 
 	createQuery = `
-        INSERT INTO wallets (user_id, name, currency, balance, created_at, updated_at)
-        VALUES ($1, $2, $3, $4, NOW(), NOW())
+        INSERT INTO wallets (user_id, name, currency, created_at, updated_at)
+        VALUES ($1, $2, $3, NOW(), NOW())
         RETURNING id`
 
 	createInitialTrQuery = `
         INSERT INTO movements (wallet_id, user_id, type, amount, category_id, description, date, created_at, updated_at)
         VALUES ($1, $2, $3, $4, $5, $6, $7, NOW(), NOW())`
 
-	addToBalanceQuery = `
-        UPDATE wallets
-        SET balance = balance + $1,
-            updated_at = NOW()
-        WHERE id = $2`
-
+	// $5 — ожидаемая версия, NULL отключает проверку
 	updateQuery = `
         UPDATE wallets
         SET name = COALESCE($1, name),
 			currency = COALESCE($2, currency),
-            updated_at = NOW()
-        WHERE id = $3 AND user_id = $4`
+            updated_at = NOW(),
+            version = version + 1
+        WHERE id = $3 AND user_id = $4 AND deleted_at IS NULL
+        AND ($5::int IS NULL OR version = $5)`
 
+	// кошелёк уходит в корзину вместе с операциями; их проводки остаются, баланс не меняется
 	deleteQuery = `
-        DELETE FROM wallets
-        WHERE id = $1 AND user_id = $2`
+        UPDATE wallets
+        SET deleted_at = NOW(), version = version + 1
+        WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL
+        AND ($3::int IS NULL OR version = $3)`
+
+	restoreWalletByIdQuery = `
+        UPDATE wallets
+        SET deleted_at = NULL, version = version + 1
+        WHERE id = $1 AND user_id = $2 AND deleted_at IS NOT NULL`
+
+	// операции в корзине тоже считаются: после восстановления они вернутся в кошелёк
+	hasHistoryQuery = `
+        SELECT EXISTS (SELECT 1 FROM movements WHERE wallet_id = $1)
+            OR EXISTS (SELECT 1 FROM transfers WHERE from_wallet_id = $1 OR to_wallet_id = $1)`
 )
 
 func (r *WalletPostgres) Create(ctx context.Context, userId int, wallet models.Wallet) (int, error) {
 	var id int
-	err := r.transactor.GetExecutor(ctx).QueryRowxContext(ctx, createQuery, userId, wallet.Name, wallet.Currency, wallet.Balance).Scan(&id)
+	err := r.transactor.GetExecutor(ctx).QueryRowxContext(ctx, createQuery, userId, wallet.Name, wallet.Currency).Scan(&id)
 	if err != nil {
 		return 0, fmt.Errorf("[WalletPostgres.Create] failed to create empty wallet: %w", err)
 	}
@@ -81,12 +97,24 @@ func (r *WalletPostgres) GetById(ctx context.Context, userId, walletId int) (mod
 	var wallet models.Wallet
 	err := sqlx.GetContext(ctx, r.transactor.GetExecutor(ctx), &wallet, getByIdQuery, userId, walletId)
 	if err != nil {
-		return models.Wallet{}, fmt.Errorf("[WalletPostgres.GetById] wallet not found: %w", err)
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Wallet{}, ErrRecordNotFound
+		}
+		return models.Wallet{}, fmt.Errorf("[WalletPostgres.GetById] failed getting wallet: %w", err)
 	}
 	return wallet, nil
 }
 
-func (r *WalletPostgres) Update(ctx context.Context, userId, walletId int, input models.UpdateWalletInput) error {
+// HasHistory сообщает, есть ли у кошелька операции или переводы, в том числе удалённые
+func (r *WalletPostgres) HasHistory(ctx context.Context, walletId int) (bool, error) {
+	var exists bool
+	if err := r.transactor.GetExecutor(ctx).QueryRowxContext(ctx, hasHistoryQuery, walletId).Scan(&exists); err != nil {
+		return false, fmt.Errorf("[WalletPostgres.HasHistory] failed to check wallet history: %w", err)
+	}
+	return exists, nil
+}
+
+func (r *WalletPostgres) Update(ctx context.Context, userId, walletId int, input models.UpdateWalletInput, version *int) error {
 	var name, currency interface{} = nil, nil
 	if input.Name != nil {
 		name = *input.Name
@@ -94,7 +122,7 @@ func (r *WalletPostgres) Update(ctx context.Context, userId, walletId int, input
 	if input.Currency != nil {
 		currency = *input.Currency
 	}
-	result, err := r.transactor.GetExecutor(ctx).ExecContext(ctx, updateQuery, name, currency, walletId, userId)
+	result, err := r.transactor.GetExecutor(ctx).ExecContext(ctx, updateQuery, name, currency, walletId, userId, version)
 	if err != nil {
 		return fmt.Errorf("[WalletPostgres.Update] failed to update wallet: %w", err)
 	}
@@ -104,39 +132,50 @@ func (r *WalletPostgres) Update(ctx context.Context, userId, walletId int, input
 		return fmt.Errorf("[WalletPostgres.Update] failed to check rows affected: %w", err)
 	}
 	if rowsAffected == 0 {
+		if version != nil {
+			return ErrVersionConflict
+		}
 		return errors.New("[WalletPostgres.Update] wallet not found")
 	}
 	return nil
 }
 
-func (r *WalletPostgres) AddToBalance(ctx context.Context, walletId int, deltaCents int64) error {
-	result, err := r.transactor.GetExecutor(ctx).ExecContext(ctx, addToBalanceQuery, deltaCents, walletId)
+func (r *WalletPostgres) Delete(ctx context.Context, userId, walletId int, version *int) error {
+	result, err := r.transactor.GetExecutor(ctx).ExecContext(ctx, deleteQuery, walletId, userId, version)
 	if err != nil {
-		return fmt.Errorf("[WalletPostgres.AddToBalance] failed to update balance: %w", err)
+		return fmt.Errorf("[WalletPostgres.Delete] failed to delete wallet: %w", err)
 	}
 
 	rowsAffected, err := result.RowsAffected()
 	if err != nil {
-		return fmt.Errorf("[WalletPostgres.AddToBalance] failed to check rows affected: %w", err)
+		return fmt.Errorf("[WalletPostgres.Delete] failed to check rows affected: %w", err)
 	}
 	if rowsAffected == 0 {
-		return errors.New("[WalletPostgres.AddToBalance] wallet not found")
+		if version != nil {
+			return ErrVersionConflict
+		}
+		return errors.New("[WalletPostgres.Delete] wallet not found")
 	}
 	return nil
 }
 
-func (r *WalletPostgres) Delete(ctx context.Context, userId, walletId int) error {
-	result, err := r.transactor.GetExecutor(ctx).ExecContext(ctx, deleteQuery, walletId, userId)
+func (r *WalletPostgres) GetDeleted(ctx context.Context, userId int) ([]models.Wallet, error) {
+	var wallets []models.Wallet
+	err := sqlx.SelectContext(ctx, r.transactor.GetExecutor(ctx), &wallets, getDeletedWalletsQuery, userId)
 	if err != nil {
-		return fmt.Errorf("[WalletPostgres.Delete] failed to delete wallet: %w", err)
+		return nil, fmt.Errorf("[WalletPostgres.GetDeleted] failed to get deleted wallets: %w", err)
 	}
+	return wallets, nil
+}
 
-	rowsAffected, err := result.RowsAffected()
+func (r *WalletPostgres) Restore(ctx context.Context, userId, walletId int) error {
+	result, err := r.transactor.GetExecutor(ctx).ExecContext(ctx, restoreWalletByIdQuery, walletId, userId)
 	if err != nil {
-		return fmt.Errorf("[WalletPostgres.Delete] failed to check rows affected: %w", err)
+		return fmt.Errorf("[WalletPostgres.Restore] failed to restore wallet: %w", err)
 	}
-	if rowsAffected == 0 {
-		return errors.New("[WalletPostgres.Delete] wallet not found")
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return ErrRecordNotFound
 	}
 	return nil
 }
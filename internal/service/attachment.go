@@ -0,0 +1,218 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"github.com/goonsorrow/finance-tracker-api/configs"
+	"github.com/goonsorrow/finance-tracker-api/internal/models"
+	"github.com/goonsorrow/finance-tracker-api/internal/repository"
+	"github.com/goonsorrow/finance-tracker-api/internal/storage"
+)
+
+var (
+	ErrAttachmentTooLarge = errors.New("attachment is too large")
+	ErrAttachmentType     = errors.New("unsupported attachment type")
+)
+
+// Разрешённые типы определяются по содержимому файла, а не по заголовку клиента
+var allowedAttachmentTypes = map[string]bool{
+	"image/jpeg":      true,
+	"image/png":       true,
+	"image/gif":       true,
+	"image/webp":      true,
+	"application/pdf": true,
+}
+
+const maxAttachmentFilename = 255
+
+type AttachmentService struct {
+	attachmentRepo repository.Attachment
+	movementRepo   repository.Movement
+	blobs          storage.BlobStore
+	maxSize        int64
+	logger         *slog.Logger
+}
+
+func NewAttachmentService(attachmentRepo repository.Attachment, movementRepo repository.Movement, blobs storage.BlobStore, cfg configs.StorageConfig, logger *slog.Logger) *AttachmentService {
+	return &AttachmentService{attachmentRepo: attachmentRepo, movementRepo: movementRepo, blobs: blobs, maxSize: cfg.MaxSize, logger: logger}
+}
+
+func (s *AttachmentService) Upload(ctx context.Context, userId, walletId, movementId int, upload models.AttachmentUpload) (models.Attachment, error) {
+	if upload.Size <= 0 {
+		return models.Attachment{}, fmt.Errorf("%w: empty file", ErrAttachmentType)
+	}
+	if s.maxSize > 0 && upload.Size > s.maxSize {
+		return models.Attachment{}, fmt.Errorf("%w: limit is %d bytes", ErrAttachmentTooLarge, s.maxSize)
+	}
+	if err := s.checkMovement(ctx, userId, walletId, movementId); err != nil {
+		return models.Attachment{}, err
+	}
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(upload.Body, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return models.Attachment{}, fmt.Errorf("failed to read attachment: %w", err)
+	}
+	head = head[:n]
+	contentType := http.DetectContentType(head)
+	if i := strings.IndexByte(contentType, ';'); i >= 0 {
+		contentType = contentType[:i]
+	}
+	if !allowedAttachmentTypes[contentType] {
+		return models.Attachment{}, fmt.Errorf("%w: %s", ErrAttachmentType, contentType)
+	}
+
+	key, err := newAttachmentKey(userId)
+	if err != nil {
+		return models.Attachment{}, err
+	}
+	body := io.MultiReader(bytes.NewReader(head), upload.Body)
+	if err := s.blobs.Put(ctx, key, body, upload.Size, contentType); err != nil {
+		return models.Attachment{}, fmt.Errorf("failed to store attachment: %w", err)
+	}
+
+	attachment := models.Attachment{
+		UserID:      userId,
+		MovementID:  movementId,
+		StorageKey:  key,
+		Filename:    cleanFilename(upload.Filename),
+		ContentType: contentType,
+		Size:        upload.Size,
+	}
+	if err := s.attachmentRepo.Create(ctx, &attachment); err != nil {
+		// запись не сохранилась — файл никому не нужен
+		if delErr := s.blobs.Delete(context.WithoutCancel(ctx), key); delErr != nil {
+			s.logger.Warn("failed to remove orphaned attachment blob", "key", key, "err", delErr)
+		}
+		return models.Attachment{}, err
+	}
+	return attachment, nil
+}
+
+func (s *AttachmentService) GetAll(ctx context.Context, userId, walletId, movementId int) ([]models.Attachment, error) {
+	if err := s.checkMovement(ctx, userId, walletId, movementId); err != nil {
+		return nil, err
+	}
+	attachments, err := s.attachmentRepo.GetAll(ctx, userId, movementId)
+	if err != nil {
+		return nil, err
+	}
+	if attachments == nil {
+		attachments = []models.Attachment{}
+	}
+	return attachments, nil
+}
+
+// Open возвращает метаданные и содержимое файла; тело закрывает вызывающий
+func (s *AttachmentService) Open(ctx context.Context, userId, walletId, movementId, attachmentId int) (models.Attachment, io.ReadCloser, error) {
+	if err := s.checkMovement(ctx, userId, walletId, movementId); err != nil {
+		return models.Attachment{}, nil, err
+	}
+	attachment, err := s.attachmentRepo.GetById(ctx, userId, movementId, attachmentId)
+	if err != nil {
+		return models.Attachment{}, nil, err
+	}
+	body, err := s.blobs.Get(ctx, attachment.StorageKey)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			s.logger.Error("attachment blob is missing", "attachment_id", attachment.ID, "key", attachment.StorageKey)
+			return models.Attachment{}, nil, repository.ErrRecordNotFound
+		}
+		return models.Attachment{}, nil, fmt.Errorf("failed to read attachment: %w", err)
+	}
+	return attachment, body, nil
+}
+
+func (s *AttachmentService) Delete(ctx context.Context, userId, walletId, movementId, attachmentId int) error {
+	if err := s.checkMovement(ctx, userId, walletId, movementId); err != nil {
+		return err
+	}
+	attachment, err := s.attachmentRepo.GetById(ctx, userId, movementId, attachmentId)
+	if err != nil {
+		return err
+	}
+	if err := s.attachmentRepo.Delete(ctx, userId, attachment.ID); err != nil {
+		return err
+	}
+	if err := s.blobs.Delete(ctx, attachment.StorageKey); err != nil {
+		s.logger.Warn("failed to remove attachment blob", "key", attachment.StorageKey, "err", err)
+	}
+	return nil
+}
+
+// checkMovement проверяет, что операция принадлежит пользователю и кошельку
+func (s *AttachmentService) checkMovement(ctx context.Context, userId, walletId, movementId int) error {
+	if _, err := s.movementRepo.GetById(ctx, userId, walletId, movementId); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return repository.ErrRecordNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+func newAttachmentKey(userId int) (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate attachment key: %w", err)
+	}
+	return fmt.Sprintf("u%d/%s", userId, hex.EncodeToString(buf)), nil
+}
+
+// cleanFilename оставляет только имя файла без пути и управляющих символов
+func cleanFilename(name string) string {
+	name = filepath.Base(strings.ReplaceAll(name, "\\", "/"))
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) || r == '"' {
+			return -1
+		}
+		return r
+	}, name)
+	name = strings.TrimSpace(name)
+	if name == "" || name == "." || name == "/" {
+		return "attachment"
+	}
+	if r := []rune(name); len(r) > maxAttachmentFilename {
+		name = string(r[:maxAttachmentFilename])
+	}
+	return name
+}
+
+// AttachmentCleaner удаляет файлы вложений, строки которых уходят из базы каскадом
+// (при удалении перевода или очистке корзины).
+// Ключи собираются до удаления, а файлы стираются уже после коммита.
+type AttachmentCleaner struct {
+	attachmentRepo repository.Attachment
+	blobs          storage.BlobStore
+	logger         *slog.Logger
+}
+
+func NewAttachmentCleaner(attachmentRepo repository.Attachment, blobs storage.BlobStore, logger *slog.Logger) *AttachmentCleaner {
+	return &AttachmentCleaner{attachmentRepo: attachmentRepo, blobs: blobs, logger: logger}
+}
+
+func (c *AttachmentCleaner) MovementKeys(ctx context.Context, movementIds ...int) ([]string, error) {
+	return c.attachmentRepo.KeysByMovements(ctx, movementIds)
+}
+
+// Remove не возвращает ошибку: запись уже удалена, оставшийся файл только занимает место
+func (c *AttachmentCleaner) Remove(ctx context.Context, keys []string) {
+	ctx = context.WithoutCancel(ctx)
+	for _, key := range keys {
+		if err := c.blobs.Delete(ctx, key); err != nil {
+			c.logger.Warn("failed to remove attachment blob", "key", key, "err", err)
+		}
+	}
+}
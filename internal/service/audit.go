@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/goonsorrow/finance-tracker-api/internal/audit"
+	"github.com/goonsorrow/finance-tracker-api/internal/models"
+	"github.com/goonsorrow/finance-tracker-api/internal/repository"
+)
+
+var ErrInvalidAuditEntity = errors.New("history is kept only for wallet, movement and category")
+
+// Auditor записывает изменения в журнал. Вызывается внутри транзакции изменения:
+// если запись в журнал не удалась, откатывается и само изменение
+type Auditor struct {
+	auditRepo repository.Audit
+}
+
+func NewAuditor(auditRepo repository.Audit) *Auditor {
+	return &Auditor{auditRepo: auditRepo}
+}
+
+// Record сохраняет состояние сущности до и после изменения; nil означает, что состояния нет
+func (a *Auditor) Record(ctx context.Context, userId int, entity string, entityId int, action string, before, after any) error {
+	entry := models.AuditEntry{
+		UserID:   userId,
+		Entity:   entity,
+		EntityID: entityId,
+		Action:   action,
+	}
+	var err error
+	if entry.Before, err = snapshotJSON(before); err != nil {
+		return err
+	}
+	if entry.After, err = snapshotJSON(after); err != nil {
+		return err
+	}
+
+	meta := audit.MetaFrom(ctx)
+	if meta.RequestID != "" {
+		entry.RequestID = &meta.RequestID
+	}
+	if meta.IP != "" {
+		entry.IP = &meta.IP
+	}
+	return a.auditRepo.Create(ctx, entry)
+}
+
+func snapshotJSON(v any) (*string, error) {
+	if v == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode audit snapshot: %w", err)
+	}
+	s := string(data)
+	return &s, nil
+}
+
+type AuditService struct {
+	auditRepo repository.Audit
+	logger    *slog.Logger
+}
+
+func NewAuditService(auditRepo repository.Audit, logger *slog.Logger) *AuditService {
+	return &AuditService{auditRepo: auditRepo, logger: logger}
+}
+
+// History отдаёт записи журнала по сущности пользователя, новые первыми.
+// История остаётся доступной и после окончательного удаления сущности
+func (s *AuditService) History(ctx context.Context, userId int, entity string, entityId int, input models.AuditHistoryInput) ([]models.AuditEntry, error) {
+	if !models.ValidAuditEntity(entity) {
+		return nil, ErrInvalidAuditEntity
+	}
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+	entries, err := s.auditRepo.GetByEntity(ctx, userId, entity, entityId, input.Limit, input.Offset)
+	if err != nil {
+		return nil, err
+	}
+	if entries == nil {
+		entries = []models.AuditEntry{}
+	}
+	return entries, nil
+}
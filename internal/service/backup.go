@@ -0,0 +1,623 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/goonsorrow/finance-tracker-api/internal/currency"
+	"github.com/goonsorrow/finance-tracker-api/internal/models"
+	"github.com/goonsorrow/finance-tracker-api/internal/repository"
+)
+
+var (
+	ErrInvalidBackup   = errors.New("invalid backup")
+	ErrAccountNotEmpty = errors.New("backup can only be restored into an empty account")
+)
+
+type BackupService struct {
+	backupRepo    repository.Backup
+	authRepo      repository.Authorization
+	tagRepo       repository.Tag
+	walletRepo    repository.Wallet
+	transferRepo  repository.Transfer
+	movementRepo  repository.Movement
+	budgetRepo    repository.Budget
+	recurringRepo repository.Recurring
+	auditor       *Auditor
+	ledger        *Ledger
+	transactor    repository.Transactor
+	logger        *slog.Logger
+}
+
+func NewBackupService(backupRepo repository.Backup, authRepo repository.Authorization, tagRepo repository.Tag, walletRepo repository.Wallet, transferRepo repository.Transfer, movementRepo repository.Movement, budgetRepo repository.Budget, recurringRepo repository.Recurring, auditor *Auditor, ledger *Ledger, transactor repository.Transactor, logger *slog.Logger) *BackupService {
+	return &BackupService{
+		backupRepo:    backupRepo,
+		authRepo:      authRepo,
+		tagRepo:       tagRepo,
+		walletRepo:    walletRepo,
+		transferRepo:  transferRepo,
+		movementRepo:  movementRepo,
+		budgetRepo:    budgetRepo,
+		recurringRepo: recurringRepo,
+		auditor:       auditor,
+		ledger:        ledger,
+		transactor:    transactor,
+		logger:        logger,
+	}
+}
+
+// Export собирает архив всех данных пользователя. Общие категории попадают в архив,
+// только если на них что-то ссылается.
+func (s *BackupService) Export(ctx context.Context, userId int) (models.Backup, error) {
+	user, err := s.authRepo.GetUserById(ctx, userId)
+	if err != nil {
+		return models.Backup{}, err
+	}
+	backup := models.Backup{
+		Version:    models.BackupVersion,
+		ExportedAt: time.Now().UTC(),
+		Settings:   models.BackupSettings{BaseCurrency: user.BaseCurrency},
+		Categories: []models.BackupCategory{},
+		Tags:       []models.BackupTag{},
+		Wallets:    []models.BackupWallet{},
+		Transfers:  []models.BackupTransfer{},
+		Movements:  []models.BackupMovement{},
+		Budgets:    []models.BackupBudget{},
+		Recurring:  []models.BackupRecurring{},
+	}
+
+	used := make(map[int]bool)
+	useCategory := func(id *int) {
+		if id != nil {
+			used[*id] = true
+		}
+	}
+
+	tags, err := s.tagRepo.GetAll(ctx, userId)
+	if err != nil {
+		return models.Backup{}, err
+	}
+	for _, t := range tags {
+		backup.Tags = append(backup.Tags, models.BackupTag{ID: t.ID, Name: t.Name, Color: t.Color})
+	}
+
+	wallets, err := s.walletRepo.GetAll(ctx, userId)
+	if err != nil {
+		return models.Backup{}, err
+	}
+	exported := make(map[int]bool, len(wallets))
+	for _, w := range wallets {
+		exported[w.ID] = true
+		backup.Wallets = append(backup.Wallets, models.BackupWallet{
+			ID:        w.ID,
+			Name:      w.Name,
+			Currency:  w.Currency,
+			Balance:   currency.FormatAmount(w.Balance, w.Currency),
+			CreatedAt: w.CreatedAt,
+		})
+	}
+
+	transfers, err := s.transferRepo.GetAll(ctx, userId)
+	if err != nil {
+		return models.Backup{}, err
+	}
+	for _, t := range transfers {
+		backup.Transfers = append(backup.Transfers, models.BackupTransfer{
+			ID:           t.ID,
+			FromWalletID: t.FromWalletID,
+			ToWalletID:   t.ToWalletID,
+			FromAmount:   currency.FormatAmount(t.FromAmount, t.FromCurrency),
+			ToAmount:     currency.FormatAmount(t.ToAmount, t.ToCurrency),
+			Rate:         t.Rate,
+			Description:  t.Description,
+			Date:         t.Date,
+			CreatedAt:    t.CreatedAt,
+		})
+	}
+
+	err = s.movementRepo.Stream(ctx, userId, models.MovementFilter{SortBy: "date", SortOrder: "asc"}, func(m models.ExportMovement) error {
+		useCategory(m.CategoryID)
+		backup.Movements = append(backup.Movements, models.BackupMovement{
+			ID:          m.ID,
+			WalletID:    m.WalletID,
+			Type:        m.Type,
+			Amount:      currency.FormatAmount(m.Amount, m.Currency),
+			CategoryID:  m.CategoryID,
+			Description: m.Description,
+			Date:        m.Date,
+			TransferID:  m.TransferID,
+			ExternalID:  m.ExternalID,
+			CreatedAt:   m.CreatedAt,
+		})
+		return nil
+	})
+	if err != nil {
+		return models.Backup{}, err
+	}
+	if err := s.exportSplits(ctx, userId, backup.Movements, useCategory); err != nil {
+		return models.Backup{}, err
+	}
+	if err := s.exportMovementTags(ctx, userId, backup.Movements); err != nil {
+		return models.Backup{}, err
+	}
+
+	budgets, err := s.budgetRepo.GetAll(ctx, userId)
+	if err != nil {
+		return models.Backup{}, err
+	}
+	for _, b := range budgets {
+		used[b.CategoryID] = true
+		backup.Budgets = append(backup.Budgets, models.BackupBudget{
+			CategoryID: b.CategoryID,
+			Amount:     currency.FormatAmount(b.Amount, b.Currency),
+			Currency:   b.Currency,
+			Rollover:   b.Rollover,
+			StartMonth: b.StartMonth.Format(models.BudgetMonthLayout),
+		})
+	}
+
+	recurring, err := s.recurringRepo.GetAll(ctx, userId)
+	if err != nil {
+		return models.Backup{}, err
+	}
+	for _, r := range recurring {
+		// шаблон кошелька из корзины не восстановить: самого кошелька в архиве нет
+		if !exported[r.WalletID] {
+			continue
+		}
+		useCategory(r.CategoryID)
+		backup.Recurring = append(backup.Recurring, models.BackupRecurring{
+			WalletID:    r.WalletID,
+			Type:        r.Type,
+			Amount:      currency.FormatAmount(r.Amount, r.Currency),
+			CategoryID:  r.CategoryID,
+			Description: r.Description,
+			Frequency:   r.Frequency,
+			Interval:    r.Interval,
+			StartDate:   r.StartDate,
+			EndDate:     r.EndDate,
+			NextDate:    r.NextDate,
+			Paused:      r.Paused,
+		})
+	}
+
+	categories, err := s.backupRepo.GetCategories(ctx, userId)
+	if err != nil {
+		return models.Backup{}, err
+	}
+	for _, c := range categories {
+		builtin := c.UserID == nil
+		if builtin && !used[c.ID] {
+			continue
+		}
+		backup.Categories = append(backup.Categories, models.BackupCategory{
+			ID:      c.ID,
+			Name:    c.Name,
+			Type:    c.Type,
+			Icon:    c.Icon,
+			Builtin: builtin,
+		})
+	}
+
+	return backup, nil
+}
+
+// exportSplits дописывает к операциям архива их разбивку
+func (s *BackupService) exportSplits(ctx context.Context, userId int, movements []models.BackupMovement, useCategory func(*int)) error {
+	ids := make([]int, len(movements))
+	index := make(map[int]int, len(movements))
+	for i, m := range movements {
+		ids[i] = m.ID
+		index[m.ID] = i
+	}
+	splits, err := s.movementRepo.GetSplits(ctx, userId, ids)
+	if err != nil {
+		return err
+	}
+	for _, split := range splits {
+		useCategory(&split.CategoryID)
+		m := &movements[index[split.MovementID]]
+		m.Splits = append(m.Splits, models.BackupSplit{
+			CategoryID: split.CategoryID,
+			Amount:     currency.FormatAmount(split.Amount, split.Currency),
+			Note:       split.Note,
+		})
+	}
+	return nil
+}
+
+func (s *BackupService) exportMovementTags(ctx context.Context, userId int, movements []models.BackupMovement) error {
+	ids := make([]int, len(movements))
+	index := make(map[int]int, len(movements))
+	for i, m := range movements {
+		ids[i] = m.ID
+		index[m.ID] = i
+	}
+	tags, err := s.tagRepo.GetByMovements(ctx, userId, ids)
+	if err != nil {
+		return err
+	}
+	for _, t := range tags {
+		m := &movements[index[t.MovementID]]
+		m.Tags = append(m.Tags, t.ID)
+	}
+	return nil
+}
+
+// Restore переносит архив в пустой аккаунт одной транзакцией. Записи получают новые ID,
+// ссылки между ними пересчитываются; при любой ошибке не остаётся ничего.
+func (s *BackupService) Restore(ctx context.Context, userId int, backup models.Backup) (models.RestoreResult, error) {
+	if backup.Version != models.BackupVersion {
+		return models.RestoreResult{}, fmt.Errorf("%w: unsupported version %d, expected %d", ErrInvalidBackup, backup.Version, models.BackupVersion)
+	}
+	if code := backup.Settings.BaseCurrency; code != "" && !currency.IsSupported(code) {
+		return models.RestoreResult{}, fmt.Errorf("%w: unsupported base currency %s", ErrInvalidBackup, code)
+	}
+
+	var result models.RestoreResult
+	err := s.transactor.WithinTransaction(ctx, func(txCtx context.Context) error {
+		empty, err := s.backupRepo.IsAccountEmpty(txCtx, userId)
+		if err != nil {
+			return err
+		}
+		if !empty {
+			return ErrAccountNotEmpty
+		}
+
+		r := &restorer{
+			repo:       s.backupRepo,
+			movements:  s.movementRepo,
+			tagRepo:    s.tagRepo,
+			auditor:    s.auditor,
+			ledger:     s.ledger,
+			userId:     userId,
+			categories: make(map[int]int),
+			tags:       make(map[int]int),
+			wallets:    make(map[int]models.Wallet),
+			transfers:  make(map[int]int),
+		}
+		result, err = r.restore(txCtx, backup)
+		return err
+	})
+	if err != nil {
+		return models.RestoreResult{}, err
+	}
+
+	s.logger.Info("backup restored",
+		slog.Int("user_id", userId),
+		slog.Int("wallets", result.Wallets),
+		slog.Int("movements", result.Movements))
+	return result, nil
+}
+
+// restorer хранит соответствие ID из архива новым ID
+type restorer struct {
+	repo       repository.Backup
+	movements  repository.Movement
+	tagRepo    repository.Tag
+	auditor    *Auditor
+	ledger     *Ledger
+	userId     int
+	categories map[int]int
+	tags       map[int]int
+	wallets    map[int]models.Wallet
+	transfers  map[int]int
+}
+
+func (r *restorer) restore(ctx context.Context, backup models.Backup) (models.RestoreResult, error) {
+	var result models.RestoreResult
+
+	if err := r.restoreCategories(ctx, backup.Categories); err != nil {
+		return result, err
+	}
+	for _, c := range backup.Categories {
+		if !c.Builtin {
+			result.Categories++
+		}
+	}
+
+	for _, t := range backup.Tags {
+		if _, ok := r.tags[t.ID]; ok {
+			return result, fmt.Errorf("%w: tag %d is listed twice", ErrInvalidBackup, t.ID)
+		}
+		id, err := r.repo.RestoreTag(ctx, r.userId, models.Tag{Name: t.Name, Color: t.Color})
+		if err != nil {
+			return result, err
+		}
+		r.tags[t.ID] = id
+		result.Tags++
+	}
+
+	for _, w := range backup.Wallets {
+		if !currency.IsSupported(w.Currency) {
+			return result, fmt.Errorf("%w: wallet %d: unsupported currency %s", ErrInvalidBackup, w.ID, w.Currency)
+		}
+		if _, ok := r.wallets[w.ID]; ok {
+			return result, fmt.Errorf("%w: wallet %d is listed twice", ErrInvalidBackup, w.ID)
+		}
+		// баланс из архива только проверяется: после восстановления он складывается из проводок операций
+		if _, err := r.amount(w.Balance, w.Currency, "wallet", w.ID); err != nil {
+			return result, err
+		}
+		wallet := models.Wallet{UserID: r.userId, Name: w.Name, Currency: w.Currency, CreatedAt: w.CreatedAt}
+		var err error
+		if wallet.ID, err = r.repo.RestoreWallet(ctx, r.userId, wallet); err != nil {
+			return result, err
+		}
+		if err := r.auditor.Record(ctx, r.userId, models.AuditWallet, wallet.ID, models.AuditCreate, nil, wallet); err != nil {
+			return result, err
+		}
+		r.wallets[w.ID] = wallet
+		result.Wallets++
+	}
+
+	for _, t := range backup.Transfers {
+		from, err := r.wallet(t.FromWalletID, "transfer", t.ID)
+		if err != nil {
+			return result, err
+		}
+		to, err := r.wallet(t.ToWalletID, "transfer", t.ID)
+		if err != nil {
+			return result, err
+		}
+		fromAmount, err := r.amount(t.FromAmount, from.Currency, "transfer", t.ID)
+		if err != nil {
+			return result, err
+		}
+		toAmount, err := r.amount(t.ToAmount, to.Currency, "transfer", t.ID)
+		if err != nil {
+			return result, err
+		}
+		id, err := r.repo.RestoreTransfer(ctx, r.userId, models.Transfer{
+			FromWalletID: from.ID,
+			ToWalletID:   to.ID,
+			FromAmount:   fromAmount,
+			ToAmount:     toAmount,
+			FromCurrency: from.Currency,
+			ToCurrency:   to.Currency,
+			Rate:         t.Rate,
+			Description:  t.Description,
+			Date:         t.Date,
+			CreatedAt:    t.CreatedAt,
+		})
+		if err != nil {
+			return result, err
+		}
+		r.transfers[t.ID] = id
+		result.Transfers++
+	}
+
+	for _, m := range backup.Movements {
+		wallet, err := r.wallet(m.WalletID, "movement", m.ID)
+		if err != nil {
+			return result, err
+		}
+		amount, err := r.amount(m.Amount, wallet.Currency, "movement", m.ID)
+		if err != nil {
+			return result, err
+		}
+		categoryId, err := r.category(m.CategoryID, "movement", m.ID)
+		if err != nil {
+			return result, err
+		}
+		var transferId *int
+		if m.TransferID != nil {
+			id, ok := r.transfers[*m.TransferID]
+			if !ok {
+				return result, fmt.Errorf("%w: movement %d refers to unknown transfer %d", ErrInvalidBackup, m.ID, *m.TransferID)
+			}
+			transferId = &id
+		}
+		movement := models.Movement{
+			WalletID:    wallet.ID,
+			UserId:      r.userId,
+			Type:        m.Type,
+			Amount:      amount,
+			Currency:    wallet.Currency,
+			CategoryID:  categoryId,
+			Description: m.Description,
+			Date:        m.Date,
+			TransferID:  transferId,
+			ExternalID:  m.ExternalID,
+			CreatedAt:   m.CreatedAt,
+		}
+		id, err := r.repo.RestoreMovement(ctx, r.userId, movement)
+		if err != nil {
+			return result, err
+		}
+		movement.ID = id
+		if err := r.ledger.Post(ctx, movement); err != nil {
+			return result, err
+		}
+		if err := r.restoreSplits(ctx, id, amount, wallet.Currency, m); err != nil {
+			return result, err
+		}
+		if err := r.restoreMovementTags(ctx, id, m); err != nil {
+			return result, err
+		}
+		if err := r.auditor.Record(ctx, r.userId, models.AuditMovement, id, models.AuditCreate, nil, movement); err != nil {
+			return result, err
+		}
+		result.Movements++
+	}
+
+	for i, b := range backup.Budgets {
+		if !currency.IsSupported(b.Currency) {
+			return result, fmt.Errorf("%w: budget %d: unsupported currency %s", ErrInvalidBackup, i+1, b.Currency)
+		}
+		amount, err := r.amount(b.Amount, b.Currency, "budget", i+1)
+		if err != nil {
+			return result, err
+		}
+		month, err := time.Parse(models.BudgetMonthLayout, b.StartMonth)
+		if err != nil {
+			return result, fmt.Errorf("%w: budget %d: start_month must be YYYY-MM", ErrInvalidBackup, i+1)
+		}
+		categoryId, err := r.category(&b.CategoryID, "budget", i+1)
+		if err != nil {
+			return result, err
+		}
+		err = r.repo.RestoreBudget(ctx, r.userId, models.Budget{
+			CategoryID: *categoryId,
+			Amount:     amount,
+			Currency:   b.Currency,
+			Rollover:   b.Rollover,
+			StartMonth: month,
+		})
+		if err != nil {
+			return result, err
+		}
+		result.Budgets++
+	}
+
+	for i, rec := range backup.Recurring {
+		wallet, err := r.wallet(rec.WalletID, "recurring movement", i+1)
+		if err != nil {
+			return result, err
+		}
+		amount, err := r.amount(rec.Amount, wallet.Currency, "recurring movement", i+1)
+		if err != nil {
+			return result, err
+		}
+		categoryId, err := r.category(rec.CategoryID, "recurring movement", i+1)
+		if err != nil {
+			return result, err
+		}
+		err = r.repo.RestoreRecurring(ctx, r.userId, models.RecurringMovement{
+			WalletID:    wallet.ID,
+			Type:        rec.Type,
+			Amount:      amount,
+			CategoryID:  categoryId,
+			Description: rec.Description,
+			Frequency:   rec.Frequency,
+			Interval:    rec.Interval,
+			StartDate:   rec.StartDate,
+			EndDate:     rec.EndDate,
+			NextDate:    rec.NextDate,
+			Paused:      rec.Paused,
+		})
+		if err != nil {
+			return result, err
+		}
+		result.Recurring++
+	}
+
+	if code := backup.Settings.BaseCurrency; code != "" {
+		if err := r.repo.SetBaseCurrency(ctx, r.userId, code); err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+func (r *restorer) restoreSplits(ctx context.Context, movementId int, amount int64, code string, m models.BackupMovement) error {
+	if len(m.Splits) == 0 {
+		return nil
+	}
+	splits := make([]models.MovementSplit, len(m.Splits))
+	var total int64
+	for i, line := range m.Splits {
+		lineAmount, err := r.amount(line.Amount, code, "movement", m.ID)
+		if err != nil {
+			return err
+		}
+		categoryId, err := r.category(&line.CategoryID, "movement", m.ID)
+		if err != nil {
+			return err
+		}
+		splits[i] = models.MovementSplit{CategoryID: *categoryId, Amount: lineAmount, Note: line.Note}
+		total += lineAmount
+	}
+	if total != amount {
+		return fmt.Errorf("%w: movement %d: splits do not add up to the amount", ErrInvalidBackup, m.ID)
+	}
+	return r.movements.ReplaceSplits(ctx, movementId, splits)
+}
+
+func (r *restorer) restoreMovementTags(ctx context.Context, movementId int, m models.BackupMovement) error {
+	if len(m.Tags) == 0 {
+		return nil
+	}
+	ids := make([]int, len(m.Tags))
+	for i, tagId := range m.Tags {
+		id, ok := r.tags[tagId]
+		if !ok {
+			return fmt.Errorf("%w: movement %d refers to unknown tag %d", ErrInvalidBackup, m.ID, tagId)
+		}
+		ids[i] = id
+	}
+	return r.tagRepo.SetMovementTags(ctx, movementId, ids)
+}
+
+// restoreCategories создаёт собственные категории, а общие сопоставляет по названию и типу
+func (r *restorer) restoreCategories(ctx context.Context, categories []models.BackupCategory) error {
+	existing, err := r.repo.GetCategories(ctx, r.userId)
+	if err != nil {
+		return err
+	}
+	builtin := make(map[string]int, len(existing))
+	for _, c := range existing {
+		builtin[categoryKey(c.Name, c.Type)] = c.ID
+	}
+
+	for _, c := range categories {
+		if _, ok := r.categories[c.ID]; ok {
+			return fmt.Errorf("%w: category %d is listed twice", ErrInvalidBackup, c.ID)
+		}
+		if c.Type != "income" && c.Type != "expense" {
+			return fmt.Errorf("%w: category %d: type must be income or expense", ErrInvalidBackup, c.ID)
+		}
+		if c.Builtin {
+			id, ok := builtin[categoryKey(c.Name, c.Type)]
+			if !ok {
+				return fmt.Errorf("%w: built-in category %q (%s) does not exist", ErrInvalidBackup, c.Name, c.Type)
+			}
+			r.categories[c.ID] = id
+			continue
+		}
+		category := models.Category{UserID: &r.userId, Name: c.Name, Type: c.Type, Icon: c.Icon}
+		if category.ID, err = r.repo.RestoreCategory(ctx, r.userId, category); err != nil {
+			return err
+		}
+		if err := r.auditor.Record(ctx, r.userId, models.AuditCategory, category.ID, models.AuditCreate, nil, category); err != nil {
+			return err
+		}
+		r.categories[c.ID] = category.ID
+	}
+	return nil
+}
+
+func categoryKey(name, kind string) string {
+	return strings.ToLower(name) + "\x00" + kind
+}
+
+func (r *restorer) wallet(id int, entity string, ref int) (models.Wallet, error) {
+	wallet, ok := r.wallets[id]
+	if !ok {
+		return models.Wallet{}, fmt.Errorf("%w: %s %d refers to unknown wallet %d", ErrInvalidBackup, entity, ref, id)
+	}
+	return wallet, nil
+}
+
+func (r *restorer) category(id *int, entity string, ref int) (*int, error) {
+	if id == nil {
+		return nil, nil
+	}
+	newId, ok := r.categories[*id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s %d refers to unknown category %d", ErrInvalidBackup, entity, ref, *id)
+	}
+	return &newId, nil
+}
+
+func (r *restorer) amount(value, code, entity string, ref int) (int64, error) {
+	amount, err := currency.ParseAmount(value, code)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %s %d: %v", ErrInvalidBackup, entity, ref, err)
+	}
+	return amount, nil
+}
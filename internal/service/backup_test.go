@@ -0,0 +1,253 @@
+package service
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/goonsorrow/finance-tracker-api/internal/models"
+	"github.com/goonsorrow/finance-tracker-api/internal/repository"
+	"github.com/jmoiron/sqlx"
+)
+
+// memoryAccount — данные одного пользователя. Как и в Postgres, wallets хранит только живые
+// кошельки, а recurring — все шаблоны, в том числе на кошельках из корзины
+type memoryAccount struct {
+	user       models.User
+	categories []models.Category
+	wallets    []models.Wallet
+	movements  []models.Movement
+	recurring  []models.RecurringMovement
+	audit      []models.AuditEntry
+	nextId     int
+}
+
+func (a *memoryAccount) id() int {
+	a.nextId++
+	return a.nextId
+}
+
+func (a *memoryAccount) currency(walletId int) string {
+	for _, w := range a.wallets {
+		if w.ID == walletId {
+			return w.Currency
+		}
+	}
+	return ""
+}
+
+// Фейки реализуют только то, что нужно экспорту и восстановлению; остальные методы
+// достаются от пустых встроенных интерфейсов
+type memoryAuth struct {
+	repository.Authorization
+	*memoryAccount
+}
+
+func (m memoryAuth) GetUserById(_ context.Context, _ int) (models.User, error) {
+	return m.user, nil
+}
+
+type memoryTags struct {
+	repository.Tag
+	*memoryAccount
+}
+
+func (m memoryTags) GetAll(_ context.Context, _ int) ([]models.Tag, error) { return nil, nil }
+
+func (m memoryTags) GetByMovements(_ context.Context, _ int, _ []int) ([]models.MovementTag, error) {
+	return nil, nil
+}
+
+type memoryWallets struct {
+	repository.Wallet
+	*memoryAccount
+}
+
+func (m memoryWallets) GetAll(_ context.Context, _ int) ([]models.Wallet, error) {
+	return m.wallets, nil
+}
+
+type memoryTransfers struct {
+	repository.Transfer
+	*memoryAccount
+}
+
+func (m memoryTransfers) GetAll(_ context.Context, _ int) ([]models.Transfer, error) { return nil, nil }
+
+type memoryMovements struct {
+	repository.Movement
+	*memoryAccount
+}
+
+func (m memoryMovements) Stream(_ context.Context, _ int, _ models.MovementFilter, fn func(models.ExportMovement) error) error {
+	for _, movement := range m.movements {
+		movement.Currency = m.currency(movement.WalletID)
+		if err := fn(models.ExportMovement{Movement: movement}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m memoryMovements) GetSplits(_ context.Context, _ int, _ []int) ([]models.MovementSplit, error) {
+	return nil, nil
+}
+
+type memoryBudgets struct {
+	repository.Budget
+	*memoryAccount
+}
+
+func (m memoryBudgets) GetAll(_ context.Context, _ int) ([]models.Budget, error) { return nil, nil }
+
+type memoryRecurring struct {
+	repository.Recurring
+	*memoryAccount
+}
+
+func (m memoryRecurring) GetAll(_ context.Context, _ int) ([]models.RecurringMovement, error) {
+	return m.recurring, nil
+}
+
+type memoryBackup struct {
+	repository.Backup
+	*memoryAccount
+}
+
+func (m memoryBackup) IsAccountEmpty(_ context.Context, _ int) (bool, error) {
+	return len(m.wallets) == 0 && len(m.movements) == 0, nil
+}
+
+func (m memoryBackup) GetCategories(_ context.Context, _ int) ([]models.Category, error) {
+	return m.categories, nil
+}
+
+func (m memoryBackup) RestoreCategory(_ context.Context, userId int, category models.Category) (int, error) {
+	category.ID, category.UserID = m.id(), &userId
+	m.categories = append(m.categories, category)
+	return category.ID, nil
+}
+
+func (m memoryBackup) RestoreWallet(_ context.Context, _ int, wallet models.Wallet) (int, error) {
+	wallet.ID = m.id()
+	m.wallets = append(m.wallets, wallet)
+	return wallet.ID, nil
+}
+
+func (m memoryBackup) RestoreMovement(_ context.Context, _ int, movement models.Movement) (int, error) {
+	movement.ID = m.id()
+	m.movements = append(m.movements, movement)
+	return movement.ID, nil
+}
+
+func (m memoryBackup) RestoreRecurring(_ context.Context, _ int, recurring models.RecurringMovement) error {
+	recurring.ID = m.id()
+	m.recurring = append(m.recurring, recurring)
+	return nil
+}
+
+func (m memoryBackup) SetBaseCurrency(_ context.Context, _ int, code string) error {
+	m.user.BaseCurrency = code
+	return nil
+}
+
+type memoryAudit struct {
+	repository.Audit
+	*memoryAccount
+}
+
+func (m memoryAudit) Create(_ context.Context, entry models.AuditEntry) error {
+	m.audit = append(m.audit, entry)
+	return nil
+}
+
+type memoryLedger struct {
+	repository.Ledger
+}
+
+func (memoryLedger) WalletAccount(_ context.Context, _, walletId int) (int, error) {
+	return walletId, nil
+}
+
+func (memoryLedger) Account(_ context.Context, _ int, _, _ string) (int, error) { return 0, nil }
+
+func (memoryLedger) Post(_ context.Context, _ models.JournalEntry) (int64, error) { return 0, nil }
+
+func (memoryLedger) Unpost(_ context.Context, _ []int) error { return nil }
+
+type memoryTransactor struct{}
+
+func (memoryTransactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func (memoryTransactor) GetExecutor(_ context.Context) sqlx.ExtContext { return nil }
+
+func newTestBackupService(account *memoryAccount) *BackupService {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	return NewBackupService(memoryBackup{memoryAccount: account}, memoryAuth{memoryAccount: account},
+		memoryTags{memoryAccount: account}, memoryWallets{memoryAccount: account},
+		memoryTransfers{memoryAccount: account}, memoryMovements{memoryAccount: account},
+		memoryBudgets{memoryAccount: account}, memoryRecurring{memoryAccount: account},
+		NewAuditor(memoryAudit{memoryAccount: account}), NewLedger(memoryLedger{}), memoryTransactor{}, logger)
+}
+
+func TestBackupRoundTripWithTrashedWallet(t *testing.T) {
+	ctx := context.Background()
+	userId := 1
+	start := time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC)
+	rent := 10
+
+	// кошелёк 3 лежит в корзине: его нет среди кошельков, но шаблон на нём остался
+	source := &memoryAccount{
+		user:       models.User{ID: userId, BaseCurrency: "RUB"},
+		categories: []models.Category{{ID: rent, UserID: &userId, Name: "Rent", Type: "expense"}},
+		wallets:    []models.Wallet{{ID: 2, UserID: userId, Name: "Card", Currency: "RUB"}},
+		movements: []models.Movement{
+			{ID: 20, WalletID: 2, UserId: userId, Type: "income", Amount: 500000, Date: start},
+		},
+		recurring: []models.RecurringMovement{
+			{ID: 30, WalletID: 2, Type: "expense", Amount: 4500000, Currency: "RUB", CategoryID: &rent,
+				Frequency: "monthly", Interval: 1, StartDate: start, NextDate: start},
+			{ID: 31, WalletID: 3, Type: "expense", Amount: 100000, Currency: "RUB", CategoryID: &rent,
+				Frequency: "weekly", Interval: 1, StartDate: start, NextDate: start},
+		},
+	}
+
+	backup, err := newTestBackupService(source).Export(ctx, userId)
+	if err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	if len(backup.Recurring) != 1 || backup.Recurring[0].WalletID != 2 {
+		t.Fatalf("exported recurring = %+v, want only the template of wallet 2", backup.Recurring)
+	}
+
+	target := &memoryAccount{user: models.User{ID: userId}, nextId: 100}
+	result, err := newTestBackupService(target).Restore(ctx, userId, backup)
+	if err != nil {
+		t.Fatalf("Restore of own export: %v", err)
+	}
+
+	want := models.RestoreResult{Categories: 1, Wallets: 1, Movements: 1, Recurring: 1}
+	if result != want {
+		t.Errorf("Restore = %+v, want %+v", result, want)
+	}
+	if len(target.recurring) != 1 || target.recurring[0].WalletID != target.wallets[0].ID {
+		t.Errorf("restored recurring = %+v, want it on the restored wallet %d", target.recurring, target.wallets[0].ID)
+	}
+	// каждая восстановленная категория, кошелёк и операция попадают в журнал
+	created := map[string]int{}
+	for _, entry := range target.audit {
+		if entry.Action == models.AuditCreate {
+			created[entry.Entity]++
+		}
+	}
+	if created[models.AuditCategory] != 1 || created[models.AuditWallet] != 1 || created[models.AuditMovement] != 1 {
+		t.Errorf("audit create entries = %v, want one category, wallet and movement", created)
+	}
+	if target.user.BaseCurrency != "RUB" {
+		t.Errorf("base currency = %q, want RUB", target.user.BaseCurrency)
+	}
+}
@@ -0,0 +1,230 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/goonsorrow/finance-tracker-api/internal/currency"
+	"github.com/goonsorrow/finance-tracker-api/internal/models"
+	"github.com/goonsorrow/finance-tracker-api/internal/repository"
+)
+
+var ErrInvalidBudget = errors.New("invalid budget")
+
+type BudgetService struct {
+	budgetRepo   repository.Budget
+	categoryRepo repository.Category
+	authRepo     repository.Authorization
+	converter    *currency.Converter
+	logger       *slog.Logger
+}
+
+func NewBudgetService(budgetRepo repository.Budget, categoryRepo repository.Category, authRepo repository.Authorization, converter *currency.Converter, logger *slog.Logger) *BudgetService {
+	return &BudgetService{budgetRepo: budgetRepo, categoryRepo: categoryRepo, authRepo: authRepo, converter: converter, logger: logger}
+}
+
+func monthStart(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// parseMonth разбирает "YYYY-MM", пустая строка — текущий месяц
+func parseMonth(value string) (time.Time, error) {
+	if value == "" {
+		return monthStart(time.Now().UTC()), nil
+	}
+	return time.Parse(models.BudgetMonthLayout, value)
+}
+
+func (s *BudgetService) Create(ctx context.Context, userId int, input models.CreateBudgetInput) (int, error) {
+	if err := input.Validate(); err != nil {
+		return 0, err
+	}
+
+	category, err := s.categoryRepo.GetById(ctx, userId, input.CategoryID)
+	if err != nil {
+		return 0, err
+	}
+	if category.Type != "expense" {
+		return 0, fmt.Errorf("%w: budgets can only be set for expense categories", ErrInvalidBudget)
+	}
+
+	code := input.Currency
+	if code == "" {
+		user, err := s.authRepo.GetUserById(ctx, userId)
+		if err != nil {
+			return 0, err
+		}
+		code = user.BaseCurrency
+	}
+	if !currency.IsSupported(code) {
+		return 0, fmt.Errorf("%w: unsupported currency %s, supported: %v", ErrInvalidBudget, code, currency.SupportedCurrencies)
+	}
+
+	amount, err := input.Amount.Minor(code)
+	if err != nil {
+		return 0, err
+	}
+	start, err := parseMonth(input.StartMonth)
+	if err != nil {
+		return 0, err
+	}
+
+	return s.budgetRepo.Create(ctx, userId, models.Budget{
+		CategoryID: input.CategoryID,
+		Amount:     amount,
+		Currency:   code,
+		Rollover:   input.Rollover,
+		StartMonth: start,
+	})
+}
+
+func (s *BudgetService) GetAll(ctx context.Context, userId int) ([]models.Budget, error) {
+	return s.budgetRepo.GetAll(ctx, userId)
+}
+
+func (s *BudgetService) GetById(ctx context.Context, userId, budgetId int) (models.Budget, error) {
+	return s.budgetRepo.GetById(ctx, userId, budgetId)
+}
+
+func (s *BudgetService) Update(ctx context.Context, userId, budgetId int, input models.UpdateBudgetInput) error {
+	if err := input.Validate(); err != nil {
+		return err
+	}
+
+	data := models.UpdateBudgetData{Rollover: input.Rollover}
+	if input.Amount != nil {
+		budget, err := s.budgetRepo.GetById(ctx, userId, budgetId)
+		if err != nil {
+			return err
+		}
+		amount, err := input.Amount.Minor(budget.Currency)
+		if err != nil {
+			return err
+		}
+		data.Amount = &amount
+	}
+	return s.budgetRepo.Update(ctx, userId, budgetId, data)
+}
+
+func (s *BudgetService) Delete(ctx context.Context, userId, budgetId int) error {
+	return s.budgetRepo.Delete(ctx, userId, budgetId)
+}
+
+// Status — исполнение всех бюджетов за месяц. Бюджеты, начинающиеся позже, пропускаются.
+func (s *BudgetService) Status(ctx context.Context, userId int, input models.BudgetPeriodInput) ([]models.BudgetStatus, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+	month, err := parseMonth(input.Month)
+	if err != nil {
+		return nil, err
+	}
+
+	budgets, err := s.budgetRepo.GetAll(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]models.BudgetStatus, 0, len(budgets))
+	for _, budget := range budgets {
+		if budget.StartMonth.After(month) {
+			continue
+		}
+		periods, err := s.periods(ctx, userId, budget, month)
+		if err != nil {
+			return nil, err
+		}
+		statuses = append(statuses, periods[len(periods)-1])
+	}
+	return statuses, nil
+}
+
+// History — исполнение бюджета по месяцам от начала действия до указанного месяца
+func (s *BudgetService) History(ctx context.Context, userId, budgetId int, input models.BudgetPeriodInput) ([]models.BudgetStatus, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+	month, err := parseMonth(input.Month)
+	if err != nil {
+		return nil, err
+	}
+
+	budget, err := s.budgetRepo.GetById(ctx, userId, budgetId)
+	if err != nil {
+		return nil, err
+	}
+	if budget.StartMonth.After(month) {
+		return []models.BudgetStatus{}, nil
+	}
+	return s.periods(ctx, userId, budget, month)
+}
+
+// periods считает исполнение бюджета помесячно до месяца until включительно.
+// Без переноса остатка достаточно одного месяца, с переносом — вся история с StartMonth.
+func (s *BudgetService) periods(ctx context.Context, userId int, budget models.Budget, until time.Time) ([]models.BudgetStatus, error) {
+	from := budget.StartMonth
+	if !budget.Rollover && until.After(from) {
+		from = until
+	}
+	end := until.AddDate(0, 1, 0)
+
+	spend, err := s.budgetRepo.SpentByMonth(ctx, userId, budget.CategoryID, from, end)
+	if err != nil {
+		return nil, err
+	}
+
+	// расходы из кошельков в других валютах пересчитываются по курсу на конец месяца
+	conv := newBaseConversion(s.converter, budget.Currency)
+	now := time.Now().UTC()
+	spentByMonth := map[string]int64{}
+	for _, row := range spend {
+		date := row.Month.AddDate(0, 1, -1)
+		if date.After(now) {
+			date = now
+		}
+		converted, ok, err := conv.convert(ctx, row.Spent, row.Currency, date)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			spentByMonth[row.Month.Format(models.BudgetMonthLayout)] += converted
+		}
+	}
+	missing := conv.missingRates()
+
+	var periods []models.BudgetStatus
+	var carry int64
+	for month := from; month.Before(end); month = month.AddDate(0, 1, 0) {
+		key := month.Format(models.BudgetMonthLayout)
+		spent := spentByMonth[key]
+		available := budget.Amount + carry
+		remaining := available - spent
+
+		status := models.BudgetStatus{
+			BudgetID:     budget.ID,
+			CategoryID:   budget.CategoryID,
+			Category:     budget.Category,
+			Month:        key,
+			Limit:        currency.NewMoney(budget.Amount, budget.Currency),
+			CarriedOver:  currency.NewMoney(carry, budget.Currency),
+			Available:    currency.NewMoney(available, budget.Currency),
+			Spent:        currency.NewMoney(spent, budget.Currency),
+			Remaining:    currency.NewMoney(remaining, budget.Currency),
+			Overspent:    remaining < 0,
+			MissingRates: missing,
+		}
+		if available > 0 {
+			status.Percent = roundPercent(float64(spent) / float64(available) * 100)
+		}
+		periods = append(periods, status)
+
+		// переносится только неизрасходованный остаток, перерасход следующий месяц не уменьшает
+		if budget.Rollover {
+			carry = max(remaining, 0)
+		}
+	}
+	return periods, nil
+}
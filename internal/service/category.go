@@ -10,13 +10,14 @@ import (
 
 type CategoryService struct {
 	repo           repository.Category
+	auditor        *Auditor
 	transactorRepo repository.Transactor
 	logger         *slog.Logger
 }
 
-func NewCategoryService(categoryRepo repository.Category, transactorRepo repository.Transactor, logger *slog.Logger) *CategoryService {
+func NewCategoryService(categoryRepo repository.Category, auditor *Auditor, transactorRepo repository.Transactor, logger *slog.Logger) *CategoryService {
 
-	return &CategoryService{repo: categoryRepo, transactorRepo: transactorRepo, logger: logger}
+	return &CategoryService{repo: categoryRepo, auditor: auditor, transactorRepo: transactorRepo, logger: logger}
 }
 
 func (s *CategoryService) Create(ctx context.Context, userId int, input models.CreateCategoryInput) (int, error) {
@@ -27,7 +28,22 @@ func (s *CategoryService) Create(ctx context.Context, userId int, input models.C
 		UserID: &userId,
 	}
 
-	return s.repo.Create(ctx, userId, category)
+	var categoryId int
+	err := s.transactorRepo.WithinTransaction(ctx, func(txCtx context.Context) error {
+		var err error
+		if categoryId, err = s.repo.Create(txCtx, userId, category); err != nil {
+			return err
+		}
+		created, err := s.repo.GetById(txCtx, userId, categoryId)
+		if err != nil {
+			return err
+		}
+		return s.auditor.Record(txCtx, userId, models.AuditCategory, categoryId, models.AuditCreate, nil, created)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return categoryId, nil
 }
 
 func (s *CategoryService) GetAll(ctx context.Context, userId int) ([]models.Category, error) {
@@ -38,14 +54,42 @@ func (s *CategoryService) GetById(ctx context.Context, userId, categoryId int) (
 	return s.repo.GetById(ctx, userId, categoryId)
 }
 
-func (s *CategoryService) Update(ctx context.Context, userId, categoryId int, input models.UpdateCategoryInput) error {
+func (s *CategoryService) Update(ctx context.Context, userId, categoryId int, input models.UpdateCategoryInput, version *int) error {
 	if err := input.Validate(); err != nil {
 		return err
 	}
 
-	return s.repo.Update(ctx, userId, categoryId, input)
+	return s.transactorRepo.WithinTransaction(ctx, func(txCtx context.Context) error {
+		before, err := s.repo.GetById(txCtx, userId, categoryId)
+		if err != nil {
+			return err
+		}
+		if err := checkVersion(before.Version, version); err != nil {
+			return err
+		}
+		if err := s.repo.Update(txCtx, userId, categoryId, input, version); err != nil {
+			return err
+		}
+		after, err := s.repo.GetById(txCtx, userId, categoryId)
+		if err != nil {
+			return err
+		}
+		return s.auditor.Record(txCtx, userId, models.AuditCategory, categoryId, models.AuditUpdate, before, after)
+	})
 }
 
-func (s *CategoryService) Delete(ctx context.Context, userId, categoryId int) error {
-	return s.repo.Delete(ctx, userId, categoryId)
+func (s *CategoryService) Delete(ctx context.Context, userId, categoryId int, version *int) error {
+	return s.transactorRepo.WithinTransaction(ctx, func(txCtx context.Context) error {
+		before, err := s.repo.GetById(txCtx, userId, categoryId)
+		if err != nil {
+			return err
+		}
+		if err := checkVersion(before.Version, version); err != nil {
+			return err
+		}
+		if err := s.repo.Delete(txCtx, userId, categoryId, version); err != nil {
+			return err
+		}
+		return s.auditor.Record(txCtx, userId, models.AuditCategory, categoryId, models.AuditDelete, before, nil)
+	})
 }
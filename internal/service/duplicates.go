@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/goonsorrow/finance-tracker-api/configs"
+	"github.com/goonsorrow/finance-tracker-api/internal/models"
+	"github.com/goonsorrow/finance-tracker-api/internal/repository"
+)
+
+var ErrDuplicateMovement = errors.New("possible duplicate movement")
+
+// DuplicateError — в кошельке уже есть похожие операции
+type DuplicateError struct {
+	Candidates []models.Movement
+}
+
+func (e *DuplicateError) Error() string {
+	return fmt.Sprintf("%s: %d similar movement(s) found", ErrDuplicateMovement, len(e.Candidates))
+}
+
+func (e *DuplicateError) Unwrap() error {
+	return ErrDuplicateMovement
+}
+
+// Доля общих слов в описаниях, начиная с которой они считаются похожими
+const descriptionSimilarity = 0.5
+
+// DuplicateDetector ищет операции с той же суммой и типом в пределах окна дат
+// и с похожим описанием
+type DuplicateDetector struct {
+	movementRepo repository.Movement
+	window       time.Duration
+}
+
+func NewDuplicateDetector(movementRepo repository.Movement, cfg configs.DuplicatesConfig, logger *slog.Logger) *DuplicateDetector {
+	window, err := time.ParseDuration(cfg.Window)
+	if err != nil || window < 0 {
+		logger.Warn("invalid duplicates window config, using default 72h", "value", cfg.Window)
+		window = 72 * time.Hour
+	}
+	return &DuplicateDetector{movementRepo: movementRepo, window: window}
+}
+
+// Find возвращает для каждой из probes список похожих операций кошелька
+func (d *DuplicateDetector) Find(ctx context.Context, userId, walletId int, probes []models.Movement) ([][]models.Movement, error) {
+	found := make([][]models.Movement, len(probes))
+	if len(probes) == 0 {
+		return found, nil
+	}
+
+	matches, err := d.movementRepo.FindDuplicates(ctx, userId, walletId, probes, d.window)
+	if err != nil {
+		return nil, err
+	}
+	for _, match := range matches {
+		if similarDescriptions(probes[match.Index].Description, match.Description) {
+			found[match.Index] = append(found[match.Index], match.Movement)
+		}
+	}
+	return found, nil
+}
+
+// similarDescriptions: пустое описание с любой стороны считается похожим — так выглядит
+// операция, внесённая вручную, и та же операция из выписки банка
+func similarDescriptions(a, b string) bool {
+	wordsA, wordsB := descriptionWords(a), descriptionWords(b)
+	if len(wordsA) == 0 || len(wordsB) == 0 {
+		return true
+	}
+
+	joinedA, joinedB := strings.Join(wordsA, " "), strings.Join(wordsB, " ")
+	if strings.Contains(joinedA, joinedB) || strings.Contains(joinedB, joinedA) {
+		return true
+	}
+
+	setA, setB := wordSet(wordsA), wordSet(wordsB)
+	common := 0
+	for w := range setB {
+		if setA[w] {
+			common++
+		}
+	}
+	union := len(setA) + len(setB) - common
+	return float64(common)/float64(union) >= descriptionSimilarity
+}
+
+func descriptionWords(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+func wordSet(words []string) map[string]bool {
+	set := make(map[string]bool, len(words))
+	for _, w := range words {
+		set[w] = true
+	}
+	return set
+}
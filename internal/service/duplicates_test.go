@@ -0,0 +1,26 @@
+package service
+
+import "testing"
+
+func TestSimilarDescriptions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"", "Coffee", true},
+		{"Coffee", "  --  ", true},
+		{"Starbucks", "STARBUCKS #123, Moscow", true},
+		{"ПЯТЁРОЧКА 1234", "Пятёрочка", true},
+		{"Coffee shop downtown", "coffee shop airport", true},
+		{"Uber trip", "Uber Eats order", false},
+		{"Rent", "Salary", false},
+	}
+	for _, tt := range tests {
+		if got := similarDescriptions(tt.a, tt.b); got != tt.want {
+			t.Errorf("similarDescriptions(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+		if got := similarDescriptions(tt.b, tt.a); got != tt.want {
+			t.Errorf("similarDescriptions(%q, %q) = %v, want %v", tt.b, tt.a, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"io"
+	"log/slog"
+
+	"github.com/goonsorrow/finance-tracker-api/internal/export"
+	"github.com/goonsorrow/finance-tracker-api/internal/models"
+	"github.com/goonsorrow/finance-tracker-api/internal/repository"
+)
+
+type ExportService struct {
+	walletRepo   repository.Wallet
+	movementRepo repository.Movement
+	logger       *slog.Logger
+}
+
+func NewExportService(walletRepo repository.Wallet, movementRepo repository.Movement, logger *slog.Logger) *ExportService {
+	return &ExportService{walletRepo: walletRepo, movementRepo: movementRepo, logger: logger}
+}
+
+// Movements проверяет параметры выгрузки и возвращает функцию, которая пишет её в w.
+// Ошибки параметров возвращаются до того, как в ответ записан первый байт.
+func (s *ExportService) Movements(ctx context.Context, userId int, input models.MovementExportInput) (func(w io.Writer) error, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	amountCurrency := input.Currency
+	if input.WalletID != nil {
+		wallet, err := s.walletRepo.GetById(ctx, userId, *input.WalletID)
+		if err != nil {
+			return nil, err
+		}
+		if amountCurrency == "" {
+			amountCurrency = wallet.Currency
+		}
+	}
+
+	filter, err := newMovementFilter(input.MovementConditions, amountCurrency)
+	if err != nil {
+		return nil, err
+	}
+	if input.WalletID != nil {
+		filter.WalletID = *input.WalletID
+	}
+	filter.Currency = input.Currency
+	filter.SortBy = "date"
+	filter.SortOrder = "asc"
+
+	format := input.Format
+	if format == "" {
+		format = export.FormatCSV
+	}
+
+	return func(w io.Writer) error {
+		writer, err := export.NewWriter(format, w)
+		if err != nil {
+			return err
+		}
+
+		var count int
+		err = s.movementRepo.Stream(ctx, userId, filter, func(m models.ExportMovement) error {
+			count++
+			return writer.Write(m)
+		})
+		if err != nil {
+			return err
+		}
+		if err := writer.Close(); err != nil {
+			return err
+		}
+
+		s.logger.Info("movements exported", slog.Int("user_id", userId), slog.String("format", format), slog.Int("count", count))
+		return nil
+	}, nil
+}
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/goonsorrow/finance-tracker-api/configs"
+	"github.com/goonsorrow/finance-tracker-api/internal/cache"
+	"github.com/goonsorrow/finance-tracker-api/internal/models"
+)
+
+var (
+	ErrIdempotencyKeyReused  = errors.New("idempotency key was already used with a different request")
+	ErrIdempotencyInProgress = errors.New("request with this idempotency key is still being processed")
+)
+
+// IdempotencyService помнит запросы с Idempotency-Key в течение окна, чтобы повтор
+// получил тот же ответ, а не создал запись ещё раз
+type IdempotencyService struct {
+	cache  cache.Idempotency
+	window time.Duration
+	logger *slog.Logger
+}
+
+func NewIdempotencyService(cache cache.Idempotency, cfg configs.IdempotencyConfig, logger *slog.Logger) *IdempotencyService {
+	window, err := time.ParseDuration(cfg.Window)
+	if err != nil || window <= 0 {
+		logger.Warn("invalid idempotency window config, using default 24h", "value", cfg.Window)
+		window = 24 * time.Hour
+	}
+	return &IdempotencyService{cache: cache, window: window, logger: logger}
+}
+
+// ключи разных пользователей не пересекаются
+func idempotencyCacheKey(userId int, key string) string {
+	return fmt.Sprintf("idempotency:userId:%d:%s", userId, key)
+}
+
+// Begin занимает ключ под запрос. Если запрос с этим ключом уже выполнен, возвращает
+// сохранённый ответ; nil без ошибки значит, что вызывающий выполняет запрос сам
+// и должен завершить его через Complete или Abort
+func (s *IdempotencyService) Begin(ctx context.Context, userId int, key, requestHash string) (*models.IdempotencyRecord, error) {
+	cacheKey := idempotencyCacheKey(userId, key)
+
+	// ключ мог истечь между Reserve и Get, тогда занимаем его ещё раз
+	for attempt := 0; attempt < 2; attempt++ {
+		reserved, err := s.cache.Reserve(ctx, cacheKey, models.IdempotencyRecord{RequestHash: requestHash}, s.window)
+		if err != nil {
+			return nil, fmt.Errorf("failed to reserve idempotency key: %w", err)
+		}
+		if reserved {
+			return nil, nil
+		}
+
+		record, err := s.cache.Get(ctx, cacheKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get idempotency record: %w", err)
+		}
+		if record == nil {
+			continue
+		}
+		if record.RequestHash != requestHash {
+			return nil, ErrIdempotencyKeyReused
+		}
+		if !record.Completed {
+			return nil, ErrIdempotencyInProgress
+		}
+		return record, nil
+	}
+	return nil, ErrIdempotencyInProgress
+}
+
+// Complete сохраняет ответ до конца окна. Хэш запроса хранится вместе с ответом,
+// иначе повтор не пройдёт сверку в Begin
+func (s *IdempotencyService) Complete(ctx context.Context, userId int, key, requestHash string, record models.IdempotencyRecord) error {
+	record.RequestHash = requestHash
+	record.Completed = true
+	return s.cache.Save(ctx, idempotencyCacheKey(userId, key), record)
+}
+
+// Abort освобождает ключ, чтобы повтор выполнил запрос заново
+func (s *IdempotencyService) Abort(ctx context.Context, userId int, key string) error {
+	return s.cache.Delete(ctx, idempotencyCacheKey(userId, key))
+}
@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/goonsorrow/finance-tracker-api/configs"
+	"github.com/goonsorrow/finance-tracker-api/internal/models"
+)
+
+// memoryIdempotency повторяет поведение IdempotencyRedis без срока жизни ключей
+type memoryIdempotency struct {
+	records map[string]models.IdempotencyRecord
+}
+
+func (m *memoryIdempotency) Reserve(_ context.Context, key string, record models.IdempotencyRecord, _ time.Duration) (bool, error) {
+	if _, ok := m.records[key]; ok {
+		return false, nil
+	}
+	m.records[key] = record
+	return true, nil
+}
+
+func (m *memoryIdempotency) Get(_ context.Context, key string) (*models.IdempotencyRecord, error) {
+	record, ok := m.records[key]
+	if !ok {
+		return nil, nil
+	}
+	return &record, nil
+}
+
+func (m *memoryIdempotency) Save(_ context.Context, key string, record models.IdempotencyRecord) error {
+	m.records[key] = record
+	return nil
+}
+
+func (m *memoryIdempotency) Delete(_ context.Context, key string) error {
+	delete(m.records, key)
+	return nil
+}
+
+func newTestIdempotencyService() *IdempotencyService {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	return NewIdempotencyService(&memoryIdempotency{records: map[string]models.IdempotencyRecord{}},
+		configs.IdempotencyConfig{Window: "1h"}, logger)
+}
+
+func TestIdempotencyReplay(t *testing.T) {
+	ctx := context.Background()
+	s := newTestIdempotencyService()
+
+	record, err := s.Begin(ctx, 1, "key", "hash")
+	if err != nil || record != nil {
+		t.Fatalf("first Begin = %v, %v; want nil, nil", record, err)
+	}
+
+	if _, err := s.Begin(ctx, 1, "key", "hash"); !errors.Is(err, ErrIdempotencyInProgress) {
+		t.Fatalf("Begin while in progress: err = %v, want %v", err, ErrIdempotencyInProgress)
+	}
+
+	response := models.IdempotencyRecord{StatusCode: 201, ContentType: "application/json", Body: []byte(`{"id":7}`)}
+	if err := s.Complete(ctx, 1, "key", "hash", response); err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+
+	record, err = s.Begin(ctx, 1, "key", "hash")
+	if err != nil {
+		t.Fatalf("Begin after Complete: %v", err)
+	}
+	if record == nil || record.StatusCode != 201 || string(record.Body) != `{"id":7}` {
+		t.Fatalf("Begin after Complete = %+v, want stored response", record)
+	}
+
+	if _, err := s.Begin(ctx, 1, "key", "other"); !errors.Is(err, ErrIdempotencyKeyReused) {
+		t.Fatalf("Begin with another hash: err = %v, want %v", err, ErrIdempotencyKeyReused)
+	}
+
+	// ключи разных пользователей независимы
+	if record, err := s.Begin(ctx, 2, "key", "other"); err != nil || record != nil {
+		t.Fatalf("Begin for another user = %v, %v; want nil, nil", record, err)
+	}
+}
+
+func TestIdempotencyAbort(t *testing.T) {
+	ctx := context.Background()
+	s := newTestIdempotencyService()
+
+	if _, err := s.Begin(ctx, 1, "key", "hash"); err != nil {
+		t.Fatalf("Begin: %v", err)
+	}
+	if err := s.Abort(ctx, 1, "key"); err != nil {
+		t.Fatalf("Abort: %v", err)
+	}
+	// после Abort повтор выполняет запрос заново, даже с другим телом
+	if record, err := s.Begin(ctx, 1, "key", "other"); err != nil || record != nil {
+		t.Fatalf("Begin after Abort = %v, %v; want nil, nil", record, err)
+	}
+}
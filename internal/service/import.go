@@ -0,0 +1,281 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+
+	"github.com/goonsorrow/finance-tracker-api/internal/currency"
+	"github.com/goonsorrow/finance-tracker-api/internal/importer"
+	"github.com/goonsorrow/finance-tracker-api/internal/models"
+	"github.com/goonsorrow/finance-tracker-api/internal/repository"
+)
+
+var (
+	ErrInvalidImport = errors.New("invalid import")
+	// В файле есть ошибочные строки — при записи не создаётся ни одной операции
+	ErrImportRejected = errors.New("statement has invalid rows, nothing was imported")
+)
+
+type ImportService struct {
+	walletRepo   repository.Wallet
+	categoryRepo repository.Category
+	movementRepo repository.Movement
+	transactor   repository.Transactor
+	movements    *MovementService
+	duplicates   *DuplicateDetector
+	logger       *slog.Logger
+}
+
+func NewImportService(walletRepo repository.Wallet, categoryRepo repository.Category, movementRepo repository.Movement, transactor repository.Transactor, movements *MovementService, duplicates *DuplicateDetector, logger *slog.Logger) *ImportService {
+	return &ImportService{walletRepo: walletRepo, categoryRepo: categoryRepo, movementRepo: movementRepo, transactor: transactor, movements: movements, duplicates: duplicates, logger: logger}
+}
+
+// ImportCSV разбирает выписку и при DryRun только возвращает результат разбора.
+// Иначе все операции записываются в одной транзакции тем же путём, что и через API.
+func (s *ImportService) ImportCSV(ctx context.Context, userId, walletId int, file io.Reader, mapping models.CSVMapping, opts models.ImportOptions) (models.ImportResult, error) {
+	wallet, err := s.walletRepo.GetById(ctx, userId, walletId)
+	if err != nil {
+		return models.ImportResult{}, err
+	}
+
+	if err := mapping.Validate(); err != nil {
+		return models.ImportResult{}, fmt.Errorf("%w: %v", ErrInvalidImport, err)
+	}
+
+	entries, rowErrs, err := importer.ParseCSV(file, mapping, wallet.Currency)
+	if err != nil {
+		return models.ImportResult{}, err
+	}
+
+	return s.importEntries(ctx, userId, wallet, entries, rowErrs, mapping.DefaultCategoryID, opts)
+}
+
+// ImportStatement импортирует выписку OFX/QFX или QIF. Операции, идентификаторы которых
+// уже есть в кошельке, пропускаются, поэтому выписку за пересекающийся период можно
+// загружать повторно.
+func (s *ImportService) ImportStatement(ctx context.Context, userId, walletId int, file io.Reader, input models.StatementImportInput, opts models.ImportOptions) (models.ImportResult, error) {
+	wallet, err := s.walletRepo.GetById(ctx, userId, walletId)
+	if err != nil {
+		return models.ImportResult{}, err
+	}
+
+	var (
+		entries []importer.Entry
+		rowErrs []models.ImportRowError
+	)
+	switch input.Format {
+	case "ofx", "qfx":
+		entries, rowErrs, err = importer.ParseOFX(file, wallet.Currency)
+	case "qif":
+		entries, rowErrs, err = importer.ParseQIF(file, input.DateFormat, wallet.Currency)
+	default:
+		return models.ImportResult{}, fmt.Errorf("%w: unsupported format %q", ErrInvalidImport, input.Format)
+	}
+	if err != nil {
+		return models.ImportResult{}, err
+	}
+
+	return s.importEntries(ctx, userId, wallet, entries, rowErrs, input.DefaultCategoryID, opts)
+}
+
+func (s *ImportService) importEntries(ctx context.Context, userId int, wallet models.Wallet, entries []importer.Entry, rowErrs []models.ImportRowError, defaultCategoryId *int, opts models.ImportOptions) (models.ImportResult, error) {
+	resolve, err := s.categoryResolver(ctx, userId, defaultCategoryId)
+	if err != nil {
+		return models.ImportResult{}, err
+	}
+
+	imported, err := s.importedIDs(ctx, wallet.ID, entries)
+	if err != nil {
+		return models.ImportResult{}, err
+	}
+
+	result := models.ImportResult{
+		DryRun:  opts.DryRun,
+		Rows:    make([]models.ImportRow, 0, len(entries)),
+		Errors:  rowErrs,
+		Skipped: []models.ImportRow{},
+	}
+	var delta int64
+	for _, entry := range entries {
+		m := entry.Movement
+		row := models.ImportRow{
+			Line:        entry.Line,
+			Date:        m.Date,
+			Type:        m.Type,
+			Amount:      m.Amount,
+			Currency:    wallet.Currency,
+			Description: m.Description,
+			Category:    entry.Category,
+			ExternalID:  m.ExternalID,
+		}
+		if m.ExternalID != nil {
+			if imported[*m.ExternalID] {
+				result.Skipped = append(result.Skipped, row)
+				continue
+			}
+			// тот же идентификатор дальше в файле тоже пропускается
+			imported[*m.ExternalID] = true
+		}
+
+		categoryId, err := resolve(entry.Category, m.Type)
+		if err != nil {
+			result.Errors = append(result.Errors, models.ImportRowError{Line: entry.Line, Error: err.Error()})
+			continue
+		}
+		row.CategoryID = categoryId
+		result.Rows = append(result.Rows, row)
+		delta += balanceDelta(m.Type, m.Amount)
+	}
+	if result.Errors == nil {
+		result.Errors = []models.ImportRowError{}
+	}
+	result.BalanceDelta = currency.NewMoney(delta, wallet.Currency)
+
+	duplicates, err := s.markDuplicates(ctx, userId, wallet.ID, result.Rows)
+	if err != nil {
+		return models.ImportResult{}, err
+	}
+
+	if opts.DryRun {
+		return result, nil
+	}
+	if len(result.Errors) > 0 {
+		return result, ErrImportRejected
+	}
+	if duplicates > 0 && !opts.AllowDuplicates {
+		return result, fmt.Errorf("%w: %d row(s) look like existing movements, pass allow_duplicates=true to import them anyway", ErrDuplicateMovement, duplicates)
+	}
+
+	err = s.transactor.WithinTransaction(ctx, func(txCtx context.Context) error {
+		for i := range result.Rows {
+			row := &result.Rows[i]
+			id, err := s.movements.createInTx(txCtx, models.Movement{
+				WalletID:    wallet.ID,
+				UserId:      userId,
+				Type:        row.Type,
+				Amount:      row.Amount,
+				Currency:    wallet.Currency,
+				CategoryID:  row.CategoryID,
+				Description: row.Description,
+				Date:        row.Date,
+				ExternalID:  row.ExternalID,
+			}, nil, nil)
+			if err != nil {
+				return fmt.Errorf("line %d: %w", row.Line, err)
+			}
+			row.MovementID = &id
+		}
+		return nil
+	})
+	if err != nil {
+		return models.ImportResult{}, err
+	}
+
+	result.Created = len(result.Rows)
+	s.logger.Info("statement imported",
+		slog.Int("wallet_id", wallet.ID),
+		slog.Int("movements", result.Created))
+	return result, nil
+}
+
+// markDuplicates отмечает строки, похожие на уже записанные операции, и возвращает их число
+func (s *ImportService) markDuplicates(ctx context.Context, userId, walletId int, rows []models.ImportRow) (int, error) {
+	probes := make([]models.Movement, len(rows))
+	for i, row := range rows {
+		probes[i] = models.Movement{Type: row.Type, Amount: row.Amount, Description: row.Description, Date: row.Date}
+	}
+
+	found, err := s.duplicates.Find(ctx, userId, walletId, probes)
+	if err != nil {
+		return 0, err
+	}
+	var count int
+	for i := range rows {
+		if len(found[i]) > 0 {
+			rows[i].Duplicates = found[i]
+			count++
+		}
+	}
+	return count, nil
+}
+
+// importedIDs — идентификаторы операций файла, которые уже есть в кошельке
+func (s *ImportService) importedIDs(ctx context.Context, walletId int, entries []importer.Entry) (map[string]bool, error) {
+	var ids []string
+	for _, entry := range entries {
+		if entry.Movement.ExternalID != nil {
+			ids = append(ids, *entry.Movement.ExternalID)
+		}
+	}
+
+	imported := make(map[string]bool, len(ids))
+	if len(ids) == 0 {
+		return imported, nil
+	}
+	existing, err := s.movementRepo.ExistingExternalIDs(ctx, walletId, ids)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range existing {
+		imported[id] = true
+	}
+	return imported, nil
+}
+
+// categoryResolver сопоставляет название категории из файла с категориями пользователя
+// без учёта регистра. Категория должна быть того же типа, что и операция, иначе берётся
+// категория по умолчанию — тоже только если совпадает тип
+func (s *ImportService) categoryResolver(ctx context.Context, userId int, defaultCategoryId *int) (func(name, movementType string) (*int, error), error) {
+	var defaultType string
+	if defaultCategoryId != nil {
+		category, err := s.categoryRepo.GetById(ctx, userId, *defaultCategoryId)
+		if err != nil {
+			if errors.Is(err, repository.ErrRecordNotFound) {
+				return nil, fmt.Errorf("%w: default category %d not found", ErrInvalidImport, *defaultCategoryId)
+			}
+			return nil, err
+		}
+		defaultType = category.Type
+	}
+
+	categories, err := s.categoryRepo.GetAll(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	byName := make(map[string][]models.Category, len(categories))
+	for _, c := range categories {
+		key := strings.ToLower(strings.TrimSpace(c.Name))
+		byName[key] = append(byName[key], c)
+	}
+
+	return func(name, movementType string) (*int, error) {
+		if name == "" {
+			if defaultCategoryId == nil {
+				return nil, errors.New("category is empty and default_category_id is not set")
+			}
+			if defaultType != movementType {
+				return nil, fmt.Errorf("category is empty and the default category is not an %s category", movementType)
+			}
+			return defaultCategoryId, nil
+		}
+
+		matches := byName[strings.ToLower(name)]
+		for _, c := range matches {
+			if c.Type == movementType {
+				return &c.ID, nil
+			}
+		}
+		if defaultCategoryId != nil && defaultType == movementType {
+			return defaultCategoryId, nil
+		}
+		// доход не должен молча попасть в одноимённую категорию расходов
+		if len(matches) > 0 {
+			return nil, fmt.Errorf("category %q is not an %s category", name, movementType)
+		}
+		return nil, fmt.Errorf("unknown category %q", name)
+	}, nil
+}
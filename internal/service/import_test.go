@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/goonsorrow/finance-tracker-api/internal/models"
+	"github.com/goonsorrow/finance-tracker-api/internal/repository"
+)
+
+type memoryCategories struct {
+	repository.Category
+	categories []models.Category
+}
+
+func (m memoryCategories) GetAll(_ context.Context, _ int) ([]models.Category, error) {
+	return m.categories, nil
+}
+
+func (m memoryCategories) GetById(_ context.Context, _, categoryId int) (models.Category, error) {
+	for _, c := range m.categories {
+		if c.ID == categoryId {
+			return c, nil
+		}
+	}
+	return models.Category{}, repository.ErrRecordNotFound
+}
+
+func TestCategoryResolver(t *testing.T) {
+	s := &ImportService{categoryRepo: memoryCategories{categories: []models.Category{
+		{ID: 1, Name: "Salary", Type: "income"},
+		{ID: 2, Name: "Food", Type: "expense"},
+		{ID: 3, Name: "Gifts", Type: "income"},
+		{ID: 4, Name: "Gifts", Type: "expense"},
+		{ID: 5, Name: "Other", Type: "expense"},
+	}}}
+	other := 5
+
+	tests := []struct {
+		name         string
+		defaultId    *int
+		category     string
+		movementType string
+		want         int // 0 — ожидается ошибка строки
+	}{
+		{"match by name", nil, "food", "expense", 2},
+		{"match by name and type", nil, "Gifts", "income", 3},
+		{"name of the other type", nil, "Salary", "expense", 0},
+		{"unknown name", nil, "Taxi", "expense", 0},
+		{"empty without default", nil, "", "expense", 0},
+		{"empty with default", &other, "", "expense", 5},
+		{"empty with default of the other type", &other, "", "income", 0},
+		{"unknown with default", &other, "Taxi", "expense", 5},
+		{"unknown with default of the other type", &other, "Taxi", "income", 0},
+		{"other type with default", &other, "Salary", "expense", 5},
+		{"other type with default of the other type", &other, "Food", "income", 0},
+	}
+	for _, tt := range tests {
+		resolve, err := s.categoryResolver(context.Background(), 1, tt.defaultId)
+		if err != nil {
+			t.Fatalf("%s: categoryResolver: %v", tt.name, err)
+		}
+		got, err := resolve(tt.category, tt.movementType)
+		switch {
+		case tt.want == 0 && err == nil:
+			t.Errorf("%s: resolve(%q, %s) = %d, want error", tt.name, tt.category, tt.movementType, *got)
+		case tt.want != 0 && err != nil:
+			t.Errorf("%s: resolve(%q, %s) error = %v, want %d", tt.name, tt.category, tt.movementType, err, tt.want)
+		case tt.want != 0 && *got != tt.want:
+			t.Errorf("%s: resolve(%q, %s) = %d, want %d", tt.name, tt.category, tt.movementType, *got, tt.want)
+		}
+	}
+
+	missing := 99
+	if _, err := s.categoryResolver(context.Background(), 1, &missing); err == nil {
+		t.Error("categoryResolver with unknown default category: want error")
+	}
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/goonsorrow/finance-tracker-api/internal/models"
+	"github.com/goonsorrow/finance-tracker-api/internal/repository"
+)
+
+// IntegrityService сверяет баланс кошелька по проводкам журнала с суммой его операций
+type IntegrityService struct {
+	integrityRepo repository.Integrity
+	ledger        *Ledger
+	auditor       *Auditor
+	transactor    repository.Transactor
+	logger        *slog.Logger
+}
+
+func NewIntegrityService(integrityRepo repository.Integrity, ledger *Ledger, auditor *Auditor, transactor repository.Transactor, logger *slog.Logger) *IntegrityService {
+	return &IntegrityService{integrityRepo: integrityRepo, ledger: ledger, auditor: auditor, transactor: transactor, logger: logger}
+}
+
+func (s *IntegrityService) Check(ctx context.Context) ([]models.BalanceMismatch, error) {
+	return s.integrityRepo.BalanceMismatches(ctx)
+}
+
+// Repair заново проводит операции кошельков с расхождениями в одной транзакции. Каждый кошелёк
+// перед этим блокируется и пересчитывается: расхождение могло исчезнуть после проверки.
+// Возвращает исправленные кошельки
+func (s *IntegrityService) Repair(ctx context.Context) ([]models.BalanceMismatch, error) {
+	var repaired []models.BalanceMismatch
+	err := s.transactor.WithinTransaction(ctx, func(txCtx context.Context) error {
+		repaired = nil
+		mismatches, err := s.integrityRepo.BalanceMismatches(txCtx)
+		if err != nil {
+			return err
+		}
+
+		// кошельки идут по возрастанию id, поэтому блокировки берутся в одном порядке
+		for _, m := range mismatches {
+			current, err := s.integrityRepo.LockedBalance(txCtx, m.WalletID)
+			if err != nil {
+				return err
+			}
+			if current.Stored == current.Computed {
+				continue
+			}
+			if err := s.ledger.Repost(txCtx, current.WalletID); err != nil {
+				return err
+			}
+			before := map[string]int64{"balance": current.Stored}
+			after := map[string]int64{"balance": current.Computed}
+			if err := s.auditor.Record(txCtx, current.UserID, models.AuditWallet, current.WalletID, models.AuditUpdate, before, after); err != nil {
+				return err
+			}
+			repaired = append(repaired, current)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, m := range repaired {
+		s.logger.Warn("wallet balance repaired", balanceAttrs(m)...)
+	}
+	return repaired, nil
+}
+
+// Verify только сообщает о расхождениях в лог, исправление остаётся за командой balances -repair
+func (s *IntegrityService) Verify(ctx context.Context) error {
+	mismatches, err := s.Check(ctx)
+	if err != nil {
+		return err
+	}
+	for _, m := range mismatches {
+		s.logger.Error("wallet balance mismatch", balanceAttrs(m)...)
+	}
+	return nil
+}
+
+func balanceAttrs(m models.BalanceMismatch) []any {
+	return []any{
+		slog.Int("wallet_id", m.WalletID),
+		slog.Int("user_id", m.UserID),
+		slog.String("currency", m.Currency),
+		slog.Int64("stored", m.Stored),
+		slog.Int64("computed", m.Computed),
+		slog.Int64("diff", m.Diff()),
+	}
+}
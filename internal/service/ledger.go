@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/goonsorrow/finance-tracker-api/internal/models"
+	"github.com/goonsorrow/finance-tracker-api/internal/repository"
+)
+
+// Ledger проводит операции по журналу двойной записи. Каждая живая операция — одна запись:
+// счёт кошелька против счёта доходов, расходов, начальных остатков или транзитного счёта переводов.
+// Баланс кошелька нигде не хранится, он складывается из проводок по его счёту
+type Ledger struct {
+	ledgerRepo repository.Ledger
+}
+
+func NewLedger(ledgerRepo repository.Ledger) *Ledger {
+	return &Ledger{ledgerRepo: ledgerRepo}
+}
+
+// counterAccount — счёт, против которого проводится операция этого типа
+func counterAccount(movementType string) string {
+	switch movementType {
+	case "income":
+		return models.AccountIncome
+	case "expense":
+		return models.AccountExpense
+	case "initial":
+		return models.AccountEquity
+	default:
+		return models.AccountTransfer
+	}
+}
+
+// Post проводит операции, заменяя их прежние записи. У операций должны быть заполнены
+// ID, кошелёк, пользователь, тип, сумма и валюта кошелька
+func (l *Ledger) Post(ctx context.Context, movements ...models.Movement) error {
+	ids := make([]int, 0, len(movements))
+	for _, m := range movements {
+		ids = append(ids, m.ID)
+	}
+	if err := l.ledgerRepo.Unpost(ctx, ids); err != nil {
+		return err
+	}
+
+	for _, m := range movements {
+		if m.Currency == "" {
+			return fmt.Errorf("[Ledger.Post] movement %d has no currency", m.ID)
+		}
+		walletAccount, err := l.ledgerRepo.WalletAccount(ctx, m.UserId, m.WalletID)
+		if err != nil {
+			return err
+		}
+		otherAccount, err := l.ledgerRepo.Account(ctx, m.UserId, counterAccount(m.Type), m.Currency)
+		if err != nil {
+			return err
+		}
+
+		delta := balanceDelta(m.Type, m.Amount)
+		entry := models.JournalEntry{
+			UserID:     m.UserId,
+			MovementID: m.ID,
+			Postings: []models.Posting{
+				{AccountID: walletAccount, Amount: delta, Currency: m.Currency},
+				{AccountID: otherAccount, Amount: -delta, Currency: m.Currency},
+			},
+		}
+		if _, err := l.ledgerRepo.Post(ctx, entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Unpost снимает записи операций, ушедших в корзину
+func (l *Ledger) Unpost(ctx context.Context, movementIds ...int) error {
+	return l.ledgerRepo.Unpost(ctx, movementIds)
+}
+
+// Repost заново проводит все живые операции кошелька при исправлении расхождений
+func (l *Ledger) Repost(ctx context.Context, walletId int) error {
+	if err := l.ledgerRepo.UnpostWallet(ctx, walletId); err != nil {
+		return err
+	}
+	movements, err := l.ledgerRepo.WalletMovements(ctx, walletId)
+	if err != nil {
+		return err
+	}
+	return l.Post(ctx, movements...)
+}
@@ -2,36 +2,52 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
-	"math"
 
+	"github.com/goonsorrow/finance-tracker-api/internal/currency"
 	"github.com/goonsorrow/finance-tracker-api/internal/models"
 	"github.com/goonsorrow/finance-tracker-api/internal/repository"
 )
 
-func (s *MovementService) validateWalletAccess(ctx context.Context, userId, walletId int) error {
-	_, err := s.walletRepo.GetById(ctx, userId, walletId)
-	if err != nil {
-		return err
+var (
+	ErrTransferMovement = errors.New("movement belongs to a transfer, change it via /api/transfers")
+	ErrInvalidSplits    = errors.New("invalid splits")
+)
+
+// balanceDelta — на сколько операция меняет баланс кошелька
+func balanceDelta(movementType string, amount int64) int64 {
+	if movementType == "expense" || movementType == "transfer_out" {
+		return -amount
 	}
-	return nil
+	return amount
+}
+
+// getWallet проверяет, что кошелёк принадлежит пользователю; валюта кошелька нужна для разбора сумм
+func (s *MovementService) getWallet(ctx context.Context, userId, walletId int) (models.Wallet, error) {
+	return s.walletRepo.GetById(ctx, userId, walletId)
 }
 
 type MovementService struct {
 	walletRepo     repository.Wallet
 	categoryRepo   repository.Category
+	tagRepo        repository.Tag
 	transactorRepo repository.Transactor
 	movementRepo   repository.Movement
+	duplicates     *DuplicateDetector
+	auditor        *Auditor
+	ledger         *Ledger
 	logger         *slog.Logger
 }
 
-func NewMovementService(walletRepo repository.Wallet, categoryRepo repository.Category, transactorRepo repository.Transactor, movementRepo repository.Movement, logger *slog.Logger) *MovementService {
-	return &MovementService{walletRepo: walletRepo, categoryRepo: categoryRepo, transactorRepo: transactorRepo, movementRepo: movementRepo, logger: logger}
+func NewMovementService(walletRepo repository.Wallet, categoryRepo repository.Category, tagRepo repository.Tag, transactorRepo repository.Transactor, movementRepo repository.Movement, duplicates *DuplicateDetector, auditor *Auditor, ledger *Ledger, logger *slog.Logger) *MovementService {
+	return &MovementService{walletRepo: walletRepo, categoryRepo: categoryRepo, tagRepo: tagRepo, transactorRepo: transactorRepo, movementRepo: movementRepo, duplicates: duplicates, auditor: auditor, ledger: ledger, logger: logger}
 }
 
 func (s *MovementService) Create(ctx context.Context, userId, walletId int, input models.CreateMovementInput) (int, error) {
-	if err := s.validateWalletAccess(ctx, userId, walletId); err != nil {
+	wallet, err := s.getWallet(ctx, userId, walletId)
+	if err != nil {
 		return 0, err
 	}
 
@@ -42,65 +58,380 @@ func (s *MovementService) Create(ctx context.Context, userId, walletId int, inpu
 
 	var movementId int
 
-	amountInCents := int64(math.Round(input.Amount * 100))
+	amount, err := input.Amount.Minor(wallet.Currency)
+	if err != nil {
+		return 0, err
+	}
 
-	err := s.transactorRepo.WithinTransaction(ctx, func(txCtx context.Context) error {
+	splits, err := s.parseSplits(ctx, userId, input.Splits, amount, wallet.Currency)
+	if err != nil {
+		return 0, err
+	}
+	categoryId := input.CategoryID
+	if categoryId == nil && len(splits) > 0 {
+		categoryId = &splits[0].CategoryID
+	}
+	tagIds, err := checkTags(ctx, s.tagRepo, userId, input.TagIDs)
+	if err != nil {
+		return 0, err
+	}
 
-		movement := models.Movement{
+	if !input.AllowDuplicate && input.Type != "initial" {
+		found, err := s.duplicates.Find(ctx, userId, walletId, []models.Movement{{
+			Type:        input.Type,
+			Amount:      amount,
+			Description: input.Description,
+			Date:        input.Date,
+		}})
+		if err != nil {
+			return 0, err
+		}
+		if len(found[0]) > 0 {
+			return 0, &DuplicateError{Candidates: found[0]}
+		}
+	}
+
+	err = s.transactorRepo.WithinTransaction(ctx, func(txCtx context.Context) error {
+		movementId, err = s.createInTx(txCtx, models.Movement{
 			WalletID:    walletId,
 			UserId:      userId,
 			Type:        input.Type,
-			Amount:      amountInCents,
-			CategoryID:  input.CategoryID,
+			Amount:      amount,
+			CategoryID:  categoryId,
 			Description: input.Description,
 			Date:        input.Date,
-		}
+		}, splits, tagIds)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return movementId, nil
+}
 
-		id, err := s.movementRepo.Create(txCtx, userId, walletId, movement)
-		if err != nil {
-			return fmt.Errorf("failed to create movement: %w", err)
-		}
-		movementId = id
-		var diff int64
+// createInTx записывает проверенную операцию вместе с разбивкой и метками, проводит её
+// по журналу и пишет аудит. Вызывается внутри транзакции — её же использует импорт выписок
+func (s *MovementService) createInTx(txCtx context.Context, movement models.Movement, splits []models.MovementSplit, tagIds []int) (int, error) {
+	movementId, err := s.movementRepo.Create(txCtx, movement.UserId, movement.WalletID, movement)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create movement: %w", err)
+	}
 
-		if movement.Type == "expense" {
-			diff = -amountInCents
-		} else {
-			diff = amountInCents
+	if len(splits) > 0 {
+		if err := s.movementRepo.ReplaceSplits(txCtx, movementId, splits); err != nil {
+			return 0, fmt.Errorf("failed to save splits: %w", err)
 		}
-
-		if diff != 0 {
-			if err := s.walletRepo.AddToBalance(txCtx, walletId, diff); err != nil {
-				return fmt.Errorf("failed to update wallet balance: %w", err)
-			}
+	}
+	if len(tagIds) > 0 {
+		if err := s.tagRepo.SetMovementTags(txCtx, movementId, tagIds); err != nil {
+			return 0, fmt.Errorf("failed to save tags: %w", err)
 		}
-		return nil
-	})
+	}
+
+	created, err := s.snapshot(txCtx, movement.UserId, movement.WalletID, movementId)
 	if err != nil {
 		return 0, err
 	}
+	if err := s.ledger.Post(txCtx, created); err != nil {
+		return 0, fmt.Errorf("failed to post movement: %w", err)
+	}
+	if err := s.auditor.Record(txCtx, movement.UserId, models.AuditMovement, movementId, models.AuditCreate, nil, created); err != nil {
+		return 0, err
+	}
 	return movementId, nil
 }
 
 func (s *MovementService) CreateInitial(userId, walletId int, input models.CreateMovementInput) (int, error) {
 	return 0, nil
 }
-func (s *MovementService) GetAll(ctx context.Context, userId, walletId int) ([]models.Movement, error) {
-	if err := s.validateWalletAccess(ctx, userId, walletId); err != nil {
-		return []models.Movement{}, err
+func (s *MovementService) GetAll(ctx context.Context, userId, walletId int, input models.MovementFilterInput) (models.MovementPage, error) {
+	wallet, err := s.getWallet(ctx, userId, walletId)
+	if err != nil {
+		return models.MovementPage{}, err
+	}
+
+	if err := input.Validate(); err != nil {
+		return models.MovementPage{}, err
+	}
+
+	filter, err := newMovementFilter(input.MovementConditions, wallet.Currency)
+	if err != nil {
+		return models.MovementPage{}, err
+	}
+	filter.WalletID = walletId
+	filter.SortBy = input.SortBy
+	filter.SortOrder = input.SortOrder
+	filter.Limit = input.Limit
+	if filter.Limit == 0 {
+		filter.Limit = models.DefaultMovementLimit
+	}
+	filter.Offset = input.Offset
+
+	total, err := s.movementRepo.Count(ctx, userId, filter)
+	if err != nil {
+		return models.MovementPage{}, err
+	}
+
+	movements, err := s.movementRepo.GetAll(ctx, userId, filter)
+	if err != nil {
+		return models.MovementPage{}, err
+	}
+	if movements == nil {
+		movements = []models.Movement{}
+	}
+	if err := s.attachDetails(ctx, userId, movements); err != nil {
+		return models.MovementPage{}, err
+	}
+
+	return models.MovementPage{
+		Items:  movements,
+		Total:  total,
+		Limit:  filter.Limit,
+		Offset: filter.Offset,
+	}, nil
+}
+
+// newMovementFilter переводит условия запроса в фильтр репозитория, суммы разбираются в валюте amountCurrency
+func newMovementFilter(input models.MovementConditions, amountCurrency string) (models.MovementFilter, error) {
+	filter := models.MovementFilter{
+		Type:       input.Type,
+		CategoryID: input.CategoryID,
+		TagID:      input.TagID,
+	}
+	if input.StartDate != nil {
+		filter.StartDate = *input.StartDate
+	}
+	if input.EndDate != nil {
+		// "to" включительно, в запрос уходит начало следующего дня
+		filter.EndDate = input.EndDate.AddDate(0, 0, 1)
+	}
+	if input.MinAmount != nil {
+		minAmount, err := input.MinAmount.Minor(amountCurrency)
+		if err != nil {
+			return filter, err
+		}
+		filter.MinAmount = &minAmount
+	}
+	if input.MaxAmount != nil {
+		maxAmount, err := input.MaxAmount.Minor(amountCurrency)
+		if err != nil {
+			return filter, err
+		}
+		filter.MaxAmount = &maxAmount
+	}
+	if filter.MinAmount != nil && filter.MaxAmount != nil && *filter.MaxAmount < *filter.MinAmount {
+		return filter, fmt.Errorf("%w: max_amount must not be less than min_amount", currency.ErrInvalidAmount)
+	}
+	return filter, nil
+}
+
+// List отдаёт ленту операций по всем кошелькам пользователя постранично по курсору (date, id)
+func (s *MovementService) List(ctx context.Context, userId int, input models.MovementCursorInput) (models.MovementCursorPage, error) {
+	if err := input.Validate(); err != nil {
+		return models.MovementCursorPage{}, err
+	}
+
+	amountCurrency := input.Currency
+	if input.WalletID != nil {
+		wallet, err := s.getWallet(ctx, userId, *input.WalletID)
+		if err != nil {
+			return models.MovementCursorPage{}, err
+		}
+		if amountCurrency == "" {
+			amountCurrency = wallet.Currency
+		}
+	}
+
+	filter, err := newMovementFilter(input.MovementConditions, amountCurrency)
+	if err != nil {
+		return models.MovementCursorPage{}, err
+	}
+	if input.WalletID != nil {
+		filter.WalletID = *input.WalletID
+	}
+	filter.Currency = input.Currency
+	filter.SortOrder = input.SortOrder
+	if filter.SortOrder == "" {
+		filter.SortOrder = "desc"
+	}
+	filter.Limit = input.Limit
+	if filter.Limit == 0 {
+		filter.Limit = models.DefaultMovementLimit
+	}
+
+	var after *models.MovementCursor
+	if input.Cursor != "" {
+		cursor, err := models.DecodeMovementCursor(input.Cursor)
+		if err != nil {
+			return models.MovementCursorPage{}, err
+		}
+		after = &cursor
+	}
+
+	// Берём на одну запись больше, чтобы понять, есть ли следующая страница
+	pageSize := filter.Limit
+	filter.Limit = pageSize + 1
+
+	movements, err := s.movementRepo.GetAfterCursor(ctx, userId, filter, after)
+	if err != nil {
+		return models.MovementCursorPage{}, err
+	}
+
+	page := models.MovementCursorPage{Items: movements}
+	if len(movements) > pageSize {
+		page.Items = movements[:pageSize]
+		page.HasMore = true
+		last := page.Items[pageSize-1]
+		page.NextCursor = models.MovementCursor{Date: last.Date, ID: last.ID}.Encode()
+	}
+	if page.Items == nil {
+		page.Items = []models.Movement{}
+	}
+	if err := s.attachDetails(ctx, userId, page.Items); err != nil {
+		return models.MovementCursorPage{}, err
+	}
+	return page, nil
+}
+
+// Search — поиск по описаниям с теми же фильтрами, что и лента; лучшие совпадения первыми
+func (s *MovementService) Search(ctx context.Context, userId int, input models.MovementSearchInput) (models.MovementSearchPage, error) {
+	if err := input.Validate(); err != nil {
+		return models.MovementSearchPage{}, err
+	}
+
+	amountCurrency := input.Currency
+	if input.WalletID != nil {
+		wallet, err := s.getWallet(ctx, userId, *input.WalletID)
+		if err != nil {
+			return models.MovementSearchPage{}, err
+		}
+		if amountCurrency == "" {
+			amountCurrency = wallet.Currency
+		}
+	}
+
+	filter, err := newMovementFilter(input.MovementConditions, amountCurrency)
+	if err != nil {
+		return models.MovementSearchPage{}, err
+	}
+	if input.WalletID != nil {
+		filter.WalletID = *input.WalletID
 	}
-	return s.movementRepo.GetAll(ctx, userId, walletId)
+	filter.Currency = input.Currency
+	filter.Limit = input.Limit
+	if filter.Limit == 0 {
+		filter.Limit = models.DefaultMovementLimit
+	}
+	filter.Offset = input.Offset
+
+	// Берём на одну запись больше, чтобы понять, есть ли следующая страница
+	pageSize := filter.Limit
+	filter.Limit = pageSize + 1
+
+	hits, err := s.movementRepo.Search(ctx, userId, filter, models.SearchTerms(input.Query))
+	if err != nil {
+		return models.MovementSearchPage{}, err
+	}
+
+	page := models.MovementSearchPage{Items: hits, Limit: pageSize, Offset: filter.Offset}
+	if len(hits) > pageSize {
+		page.Items = hits[:pageSize]
+		page.HasMore = true
+	}
+	if page.Items == nil {
+		page.Items = []models.MovementSearchHit{}
+	}
+	return page, nil
 }
 
 func (s *MovementService) GetById(ctx context.Context, userId, walletId, movementId int) (models.Movement, error) {
-	if err := s.validateWalletAccess(ctx, userId, walletId); err != nil {
+	if _, err := s.getWallet(ctx, userId, walletId); err != nil {
 		return models.Movement{}, err
 	}
-	return s.movementRepo.GetById(ctx, userId, walletId, movementId)
+	return s.snapshot(ctx, userId, walletId, movementId)
 }
 
-func (s *MovementService) Update(ctx context.Context, userId, walletId, movementId int, input models.UpdateMovementInput) error {
-	if err := s.validateWalletAccess(ctx, userId, walletId); err != nil {
+// snapshot — операция вместе с разбивкой и метками, как её видит клиент
+func (s *MovementService) snapshot(ctx context.Context, userId, walletId, movementId int) (models.Movement, error) {
+	movement, err := s.movementRepo.GetById(ctx, userId, walletId, movementId)
+	if err != nil {
+		return models.Movement{}, err
+	}
+	movements := []models.Movement{movement}
+	if err := s.attachDetails(ctx, userId, movements); err != nil {
+		return models.Movement{}, err
+	}
+	return movements[0], nil
+}
+
+// attachDetails подгружает разбивку и метки для списка операций, по запросу на каждое
+func (s *MovementService) attachDetails(ctx context.Context, userId int, movements []models.Movement) error {
+	ids := make([]int, len(movements))
+	for i, m := range movements {
+		ids[i] = m.ID
+	}
+	splits, err := s.movementRepo.GetSplits(ctx, userId, ids)
+	if err != nil {
+		return err
+	}
+	byMovement := make(map[int][]models.MovementSplit)
+	for _, split := range splits {
+		byMovement[split.MovementID] = append(byMovement[split.MovementID], split)
+	}
+
+	tags, err := s.tagRepo.GetByMovements(ctx, userId, ids)
+	if err != nil {
+		return err
+	}
+	tagsByMovement := make(map[int][]models.Tag)
+	for _, t := range tags {
+		tagsByMovement[t.MovementID] = append(tagsByMovement[t.MovementID], t.Tag)
+	}
+
+	for i := range movements {
+		movements[i].Splits = byMovement[movements[i].ID]
+		movements[i].Tags = tagsByMovement[movements[i].ID]
+	}
+	return nil
+}
+
+// parseSplits переводит строки разбивки в минимальные единицы и проверяет, что их сумма
+// равна сумме операции, а категории доступны пользователю
+func (s *MovementService) parseSplits(ctx context.Context, userId int, input []models.SplitInput, amount int64, code string) ([]models.MovementSplit, error) {
+	if len(input) == 0 {
+		return nil, nil
+	}
+
+	splits := make([]models.MovementSplit, len(input))
+	var total int64
+	checked := make(map[int]bool)
+	for i, line := range input {
+		lineAmount, err := line.Amount.Minor(code)
+		if err != nil {
+			return nil, fmt.Errorf("%w: split %d: %v", ErrInvalidSplits, i+1, err)
+		}
+		if !checked[line.CategoryID] {
+			if _, err := s.categoryRepo.GetById(ctx, userId, line.CategoryID); err != nil {
+				if errors.Is(err, repository.ErrRecordNotFound) {
+					return nil, fmt.Errorf("%w: split %d: category %d not found", ErrInvalidSplits, i+1, line.CategoryID)
+				}
+				return nil, err
+			}
+			checked[line.CategoryID] = true
+		}
+		splits[i] = models.MovementSplit{CategoryID: line.CategoryID, Amount: lineAmount, Currency: code, Note: line.Note}
+		total += lineAmount
+	}
+	if total != amount {
+		return nil, fmt.Errorf("%w: splits add up to %s, movement amount is %s", ErrInvalidSplits,
+			currency.FormatAmount(total, code), currency.FormatAmount(amount, code))
+	}
+	return splits, nil
+}
+
+func (s *MovementService) Update(ctx context.Context, userId, walletId, movementId int, input models.UpdateMovementInput, version *int) error {
+	wallet, err := s.getWallet(ctx, userId, walletId)
+	if err != nil {
 		return err
 	}
 
@@ -108,20 +439,37 @@ func (s *MovementService) Update(ctx context.Context, userId, walletId, movement
 		return err
 	}
 
-	err := s.transactorRepo.WithinTransaction(ctx, func(txCtx context.Context) error {
-		oldMovement, err := s.movementRepo.GetById(txCtx, userId, walletId, movementId)
+	var tagIds []int
+	if input.TagIDs != nil {
+		if tagIds, err = checkTags(ctx, s.tagRepo, userId, *input.TagIDs); err != nil {
+			return err
+		}
+	}
+
+	var newAmount *int64
+	if input.Amount != nil {
+		amount, err := input.Amount.Minor(wallet.Currency)
 		if err != nil {
-			return fmt.Errorf("failed to get old movement: %w", err)
+			return err
 		}
+		newAmount = &amount
+	}
 
-		oldDelta := oldMovement.Amount
-		if oldMovement.Type == "expense" {
-			oldDelta = -oldDelta
+	err = s.transactorRepo.WithinTransaction(ctx, func(txCtx context.Context) error {
+		oldMovement, err := s.snapshot(txCtx, userId, walletId, movementId)
+		if err != nil {
+			return fmt.Errorf("failed to get old movement: %w", err)
+		}
+		if oldMovement.TransferID != nil {
+			return ErrTransferMovement
+		}
+		if err := checkVersion(oldMovement.Version, version); err != nil {
+			return err
 		}
 
-		newAmount := oldMovement.Amount
-		if input.Amount != nil {
-			newAmount = int64(math.Round(*input.Amount * 100))
+		amount := oldMovement.Amount
+		if newAmount != nil {
+			amount = *newAmount
 		}
 
 		newType := oldMovement.Type
@@ -129,64 +477,93 @@ func (s *MovementService) Update(ctx context.Context, userId, walletId, movement
 			newType = *input.Type
 		}
 
-		newDelta := newAmount
-		if newType == "expense" {
-			newDelta = -newAmount
-		}
-
-		diff := newDelta - oldDelta
-
-		if diff != 0 {
-			if err := s.walletRepo.AddToBalance(txCtx, walletId, diff); err != nil {
-				return fmt.Errorf("failed to update wallet balance: %w", err)
+		categoryId := input.CategoryID
+		var splits []models.MovementSplit
+		if input.Splits != nil {
+			if splits, err = s.parseSplits(txCtx, userId, *input.Splits, amount, wallet.Currency); err != nil {
+				return err
+			}
+			if len(splits) > 0 && newType == "initial" {
+				return fmt.Errorf("%w: initial balance cannot be split", ErrInvalidSplits)
+			}
+			if categoryId == nil && len(splits) > 0 {
+				categoryId = &splits[0].CategoryID
+			}
+		} else if amount != oldMovement.Amount || newType != oldMovement.Type {
+			// старая разбивка перестанет сходиться с суммой, а её категории — с типом операции
+			existing, err := s.movementRepo.GetSplits(txCtx, userId, []int{movementId})
+			if err != nil {
+				return err
+			}
+			if len(existing) > 0 {
+				if newType == "initial" {
+					return fmt.Errorf("%w: initial balance cannot be split", ErrInvalidSplits)
+				}
+				return fmt.Errorf("%w: movement is split, send new splits together with the amount or type", ErrInvalidSplits)
 			}
 		}
 
 		updateInput := models.UpdateMovementData{
 			Type:        input.Type,
-			Amount:      nil,
-			CategoryID:  input.CategoryID,
+			Amount:      newAmount,
+			CategoryID:  categoryId,
 			Description: input.Description,
 			Date:        input.Date,
-		}
-		if input.Amount != nil {
-			amount := int64(math.Round(*input.Amount * 100))
-			updateInput.Amount = &amount
+			Version:     version,
 		}
 
 		if err := s.movementRepo.Update(txCtx, userId, walletId, movementId, updateInput); err != nil {
 			return fmt.Errorf("failed to update movement: %w", err)
 		}
-		return nil
+		if input.Splits != nil {
+			if err := s.movementRepo.ReplaceSplits(txCtx, movementId, splits); err != nil {
+				return fmt.Errorf("failed to save splits: %w", err)
+			}
+		}
+		if input.TagIDs != nil {
+			if err := s.tagRepo.SetMovementTags(txCtx, movementId, tagIds); err != nil {
+				return fmt.Errorf("failed to save tags: %w", err)
+			}
+		}
+
+		updated, err := s.snapshot(txCtx, userId, walletId, movementId)
+		if err != nil {
+			return err
+		}
+		if err := s.ledger.Post(txCtx, updated); err != nil {
+			return fmt.Errorf("failed to post movement: %w", err)
+		}
+		return s.auditor.Record(txCtx, userId, models.AuditMovement, movementId, models.AuditUpdate, oldMovement, updated)
 	})
 	return err
 }
 
-func (s *MovementService) Delete(ctx context.Context, userId, walletId, movementId int) error {
-	if err := s.validateWalletAccess(ctx, userId, walletId); err != nil {
+func (s *MovementService) Delete(ctx context.Context, userId, walletId, movementId int, version *int) error {
+	if _, err := s.getWallet(ctx, userId, walletId); err != nil {
 		return err
 	}
 
+	// операция уходит в корзину, её запись снимается с журнала до восстановления
 	err := s.transactorRepo.WithinTransaction(ctx, func(txCtx context.Context) error {
-		oldMovement, err := s.movementRepo.GetById(txCtx, userId, walletId, movementId)
+		oldMovement, err := s.snapshot(txCtx, userId, walletId, movementId)
 		if err != nil {
-			return fmt.Errorf("failed to get old movement")
+			return fmt.Errorf("failed to get old movement: %w", err)
+		}
+		if oldMovement.TransferID != nil {
+			return ErrTransferMovement
 		}
-		var delta int64
-		if oldMovement.Type == "expense" {
-			delta = oldMovement.Amount
-		} else {
-			delta = -oldMovement.Amount
+		if err := checkVersion(oldMovement.Version, version); err != nil {
+			return err
 		}
 
-		if err := s.walletRepo.AddToBalance(txCtx, walletId, delta); err != nil {
-			return fmt.Errorf("failed to update balance while deleting movement: %w", err)
+		if err := s.ledger.Unpost(txCtx, movementId); err != nil {
+			return fmt.Errorf("failed to unpost movement: %w", err)
 		}
 
-		if err := s.movementRepo.Delete(txCtx, userId, walletId, movementId); err != nil {
+		if err := s.movementRepo.Delete(txCtx, userId, walletId, movementId, version); err != nil {
 			return fmt.Errorf("failed to delete movement: %w", err)
 		}
-		return nil
+		return s.auditor.Record(txCtx, userId, models.AuditMovement, movementId, models.AuditDelete, oldMovement, nil)
 	})
 	return err
 }
@@ -2,18 +2,86 @@ package service
 
 import (
 	"context"
+	"errors"
 	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/goonsorrow/finance-tracker-api/internal/currency"
+	"github.com/goonsorrow/finance-tracker-api/internal/models"
+	"github.com/goonsorrow/finance-tracker-api/internal/repository"
 )
 
 type ProfileService struct {
-	logger *slog.Logger
+	authRepo   repository.Authorization
+	walletRepo repository.Wallet
+	converter  *currency.Converter
+	logger     *slog.Logger
 }
 
-func NewProfileService(logger *slog.Logger) *ProfileService {
-	return &ProfileService{logger: logger}
+func NewProfileService(authRepo repository.Authorization, walletRepo repository.Wallet, converter *currency.Converter, logger *slog.Logger) *ProfileService {
+	return &ProfileService{authRepo: authRepo, walletRepo: walletRepo, converter: converter, logger: logger}
 }
 
-func (ps ProfileService) GetMe(ctx context.Context, userId int) {
-	// we need to get username, profile and his balance in all currencies divided by them or combined into one by exchange rate.
+// GetMe отдаёт пользователя, балансы по валютам и общий итог в базовой валюте по курсу на сегодня
+func (ps *ProfileService) GetMe(ctx context.Context, userId int) (models.Profile, error) {
+	user, err := ps.authRepo.GetUserById(ctx, userId)
+	if err != nil {
+		return models.Profile{}, err
+	}
+
+	wallets, err := ps.walletRepo.GetAll(ctx, userId)
+	if err != nil {
+		return models.Profile{}, err
+	}
+
+	byCurrency := map[string]*models.CurrencyBalance{}
+	for _, w := range wallets {
+		b, ok := byCurrency[w.Currency]
+		if !ok {
+			b = &models.CurrencyBalance{Currency: w.Currency, Balance: currency.NewMoney(0, w.Currency)}
+			byCurrency[w.Currency] = b
+		}
+		b.Balance.Amount += w.Balance
+		b.WalletCount++
+	}
+
+	profile := models.Profile{
+		User:         user,
+		BaseCurrency: user.BaseCurrency,
+		Balances:     make([]models.CurrencyBalance, 0, len(byCurrency)),
+		Total:        currency.NewMoney(0, user.BaseCurrency),
+	}
+
+	now := time.Now().UTC()
+	for _, b := range byCurrency {
+		rate, err := ps.converter.Rate(ctx, b.Currency, user.BaseCurrency, now)
+		if err != nil {
+			if !errors.Is(err, currency.ErrRateNotFound) {
+				return models.Profile{}, err
+			}
+			ps.logger.Warn("no exchange rate for profile total",
+				slog.String("from", b.Currency),
+				slog.String("to", user.BaseCurrency))
+			profile.MissingRates = append(profile.MissingRates, b.Currency)
+			profile.Balances = append(profile.Balances, *b)
+			continue
+		}
+
+		converted := currency.NewMoney(currency.Convert(b.Balance.Amount, b.Currency, user.BaseCurrency, rate.Rate), user.BaseCurrency)
+		b.Converted = &converted
+		b.Rate = &rate.Rate
+		b.RateDate = &rate.Date
+		profile.Total.Amount += converted.Amount
+		if profile.RateDate == nil || rate.Date.Before(*profile.RateDate) {
+			profile.RateDate = &rate.Date
+		}
+		profile.Balances = append(profile.Balances, *b)
+	}
 
+	sort.Slice(profile.Balances, func(i, j int) bool {
+		return profile.Balances[i].Currency < profile.Balances[j].Currency
+	})
+	sort.Strings(profile.MissingRates)
+	return profile, nil
 }
@@ -0,0 +1,26 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/goonsorrow/finance-tracker-api/internal/currency"
+)
+
+type RateService struct {
+	converter *currency.Converter
+	logger    *slog.Logger
+}
+
+func NewRateService(converter *currency.Converter, logger *slog.Logger) *RateService {
+	return &RateService{converter: converter, logger: logger}
+}
+
+func (s *RateService) GetRate(ctx context.Context, base, quote string, date time.Time) (currency.Rate, error) {
+	if !currency.IsSupported(base) || !currency.IsSupported(quote) {
+		return currency.Rate{}, fmt.Errorf("unsupported currency pair %s/%s, supported: %v", base, quote, currency.SupportedCurrencies)
+	}
+	return s.converter.Rate(ctx, base, quote, date)
+}
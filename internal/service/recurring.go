@@ -0,0 +1,236 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/goonsorrow/finance-tracker-api/internal/currency"
+	"github.com/goonsorrow/finance-tracker-api/internal/models"
+	"github.com/goonsorrow/finance-tracker-api/internal/repository"
+)
+
+var (
+	ErrInvalidOccurrence = errors.New("date is not an upcoming occurrence of this schedule")
+	ErrOccurrenceTaken   = errors.New("occurrence is already created or skipped")
+)
+
+// Сколько шаблонов планировщик обрабатывает за один проход
+const recurringBatchSize = 100
+
+type RecurringService struct {
+	recurringRepo repository.Recurring
+	walletRepo    repository.Wallet
+	movements     Movement
+	transactor    repository.Transactor
+	logger        *slog.Logger
+}
+
+func NewRecurringService(recurringRepo repository.Recurring, walletRepo repository.Wallet, movements Movement, transactor repository.Transactor, logger *slog.Logger) *RecurringService {
+	return &RecurringService{recurringRepo: recurringRepo, walletRepo: walletRepo, movements: movements, transactor: transactor, logger: logger}
+}
+
+func (s *RecurringService) Create(ctx context.Context, userId int, input models.CreateRecurringInput) (int, error) {
+	if err := input.Validate(); err != nil {
+		return 0, err
+	}
+
+	wallet, err := s.walletRepo.GetById(ctx, userId, input.WalletID)
+	if err != nil {
+		return 0, err
+	}
+	amount, err := input.Amount.Minor(wallet.Currency)
+	if err != nil {
+		return 0, err
+	}
+
+	interval := input.Interval
+	if interval == 0 {
+		interval = 1
+	}
+
+	return s.recurringRepo.Create(ctx, userId, models.RecurringMovement{
+		WalletID:    input.WalletID,
+		Type:        input.Type,
+		Amount:      amount,
+		CategoryID:  input.CategoryID,
+		Description: input.Description,
+		Frequency:   input.Frequency,
+		Interval:    interval,
+		StartDate:   input.StartDate.UTC(),
+		EndDate:     input.EndDate,
+		NextDate:    input.StartDate.UTC(),
+	})
+}
+
+func (s *RecurringService) GetAll(ctx context.Context, userId int) ([]models.RecurringMovement, error) {
+	return s.recurringRepo.GetAll(ctx, userId)
+}
+
+func (s *RecurringService) GetById(ctx context.Context, userId, recurringId int) (models.RecurringMovement, error) {
+	return s.recurringRepo.GetById(ctx, userId, recurringId)
+}
+
+func (s *RecurringService) Delete(ctx context.Context, userId, recurringId int) error {
+	return s.recurringRepo.Delete(ctx, userId, recurringId)
+}
+
+func (s *RecurringService) Pause(ctx context.Context, userId, recurringId int) error {
+	recurring, err := s.recurringRepo.GetById(ctx, userId, recurringId)
+	if err != nil {
+		return err
+	}
+	return s.recurringRepo.SetPaused(ctx, userId, recurringId, true, recurring.NextDate)
+}
+
+// Resume снимает паузу. Повторения, пропущенные за время паузы, не создаются:
+// расписание продолжается с ближайшей будущей даты.
+func (s *RecurringService) Resume(ctx context.Context, userId, recurringId int) error {
+	recurring, err := s.recurringRepo.GetById(ctx, userId, recurringId)
+	if err != nil {
+		return err
+	}
+
+	next := recurring.NextDate
+	if now := time.Now().UTC(); recurring.Paused && next.Before(now) {
+		next, _ = recurring.NextAfter(now)
+	}
+	return s.recurringRepo.SetPaused(ctx, userId, recurringId, false, next)
+}
+
+// Skip помечает повторение пропущенным, без даты — ближайшее
+func (s *RecurringService) Skip(ctx context.Context, userId, recurringId int, input models.SkipOccurrenceInput) (time.Time, error) {
+	recurring, err := s.recurringRepo.GetById(ctx, userId, recurringId)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	date := recurring.NextDate
+	if input.Date != nil {
+		date = input.Date.UTC()
+	}
+	if date.Before(recurring.NextDate) || !recurring.IsOccurrence(date) {
+		return time.Time{}, fmt.Errorf("%w: %s", ErrInvalidOccurrence, date.Format(time.RFC3339))
+	}
+
+	claimed, err := s.recurringRepo.ClaimOccurrence(ctx, recurringId, date, true)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if !claimed {
+		return time.Time{}, ErrOccurrenceTaken
+	}
+	return date, nil
+}
+
+// Preview — ближайшие count повторений начиная с NextDate с отметкой о пропуске
+func (s *RecurringService) Preview(ctx context.Context, userId, recurringId int, input models.RecurringPreviewInput) ([]models.RecurringOccurrence, error) {
+	recurring, err := s.recurringRepo.GetById(ctx, userId, recurringId)
+	if err != nil {
+		return nil, err
+	}
+	count := input.Count
+	if count == 0 {
+		count = 10
+	}
+
+	known, err := s.recurringRepo.GetOccurrences(ctx, recurringId, recurring.NextDate)
+	if err != nil {
+		return nil, err
+	}
+	skipped := make(map[int64]bool, len(known))
+	for _, o := range known {
+		if o.Skipped {
+			skipped[o.Date.Unix()] = true
+		}
+	}
+
+	preview := make([]models.RecurringOccurrence, 0, count)
+	date, ok := recurring.NextDate, recurring.EndDate == nil || !recurring.NextDate.After(*recurring.EndDate)
+	for ok && len(preview) < count {
+		preview = append(preview, models.RecurringOccurrence{
+			RecurringID: recurringId,
+			Date:        date,
+			Skipped:     skipped[date.Unix()],
+		})
+		date, ok = recurring.NextAfter(date)
+	}
+	return preview, nil
+}
+
+// MaterializeDue создаёт операции по всем наступившим повторениям. Каждое повторение
+// занимается в recurring_occurrences в той же транзакции, что и операция, поэтому
+// повторный или параллельный запуск не создаёт дублей.
+func (s *RecurringService) MaterializeDue(ctx context.Context) error {
+	now := time.Now().UTC()
+
+	due, err := s.recurringRepo.GetDue(ctx, now, recurringBatchSize)
+	if err != nil {
+		return err
+	}
+
+	var created int
+	for _, recurring := range due {
+		n, err := s.materialize(ctx, recurring, now)
+		created += n
+		if err != nil {
+			s.logger.Error("failed to materialize recurring movement",
+				slog.Int("recurring_id", recurring.ID),
+				slog.String("error", err.Error()))
+		}
+	}
+	if created > 0 {
+		s.logger.Info("recurring movements created", slog.Int("count", created))
+	}
+	return nil
+}
+
+func (s *RecurringService) materialize(ctx context.Context, recurring models.RecurringMovement, now time.Time) (int, error) {
+	amount := currency.Decimal(currency.FormatAmount(recurring.Amount, recurring.Currency))
+
+	var created int
+	date, ok := recurring.NextDate, true
+	for ok && !date.After(now) {
+		if recurring.EndDate != nil && date.After(*recurring.EndDate) {
+			break
+		}
+		next, more := recurring.NextAfter(date)
+
+		var claimed bool
+		err := s.transactor.WithinTransaction(ctx, func(txCtx context.Context) error {
+			var err error
+			claimed, err = s.recurringRepo.ClaimOccurrence(txCtx, recurring.ID, date, false)
+			if err != nil {
+				return err
+			}
+			if claimed {
+				movementId, err := s.movements.Create(txCtx, recurring.UserID, recurring.WalletID, models.CreateMovementInput{
+					Type:        recurring.Type,
+					Amount:      amount,
+					CategoryID:  recurring.CategoryID,
+					Description: recurring.Description,
+					Date:        date,
+					// повторения одного шаблона по определению похожи друг на друга
+					AllowDuplicate: true,
+				})
+				if err != nil {
+					return err
+				}
+				if err := s.recurringRepo.SetOccurrenceMovement(txCtx, recurring.ID, date, movementId); err != nil {
+					return err
+				}
+			}
+			return s.recurringRepo.SetNextDate(txCtx, recurring.ID, next)
+		})
+		if err != nil {
+			return created, err
+		}
+		if claimed {
+			created++
+		}
+		date, ok = next, more
+	}
+	return created, nil
+}
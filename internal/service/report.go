@@ -0,0 +1,437 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/goonsorrow/finance-tracker-api/internal/currency"
+	"github.com/goonsorrow/finance-tracker-api/internal/models"
+	"github.com/goonsorrow/finance-tracker-api/internal/repository"
+)
+
+type ReportService struct {
+	reportRepo repository.Report
+	walletRepo repository.Wallet
+	authRepo   repository.Authorization
+	converter  *currency.Converter
+	logger     *slog.Logger
+}
+
+func NewReportService(reportRepo repository.Report, walletRepo repository.Wallet, authRepo repository.Authorization, converter *currency.Converter, logger *slog.Logger) *ReportService {
+	return &ReportService{reportRepo: reportRepo, walletRepo: walletRepo, authRepo: authRepo, converter: converter, logger: logger}
+}
+
+// baseConversion пересчитывает суммы в базовую валюту, кэширует курсы и запоминает валюты без курса
+type baseConversion struct {
+	converter *currency.Converter
+	base      string
+	rates     map[string]currency.Rate
+	missing   map[string]bool
+	rateDate  *time.Time
+}
+
+func newBaseConversion(converter *currency.Converter, base string) *baseConversion {
+	return &baseConversion{converter: converter, base: base, rates: map[string]currency.Rate{}, missing: map[string]bool{}}
+}
+
+// convert отдаёт сумму в базовой валюте; false — курса на дату нет, сумма в итог не входит
+func (b *baseConversion) convert(ctx context.Context, amount int64, code string, date time.Time) (int64, bool, error) {
+	key := code + date.Format(time.DateOnly)
+	rate, ok := b.rates[key]
+	if !ok {
+		var err error
+		rate, err = b.converter.Rate(ctx, code, b.base, date)
+		if err != nil {
+			if errors.Is(err, currency.ErrRateNotFound) {
+				b.missing[code] = true
+				return 0, false, nil
+			}
+			return 0, false, err
+		}
+		b.rates[key] = rate
+		if b.rateDate == nil || rate.Date.Before(*b.rateDate) {
+			b.rateDate = &rate.Date
+		}
+	}
+	return currency.Convert(amount, code, b.base, rate.Rate), true, nil
+}
+
+func (b *baseConversion) missingRates() []string {
+	if len(b.missing) == 0 {
+		return nil
+	}
+	codes := make([]string, 0, len(b.missing))
+	for code := range b.missing {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// reportScope проверяет кошельки из фильтра и отдаёт выбранные кошельки вместе с фильтром для репозитория
+func (s *ReportService) reportScope(ctx context.Context, userId int, input models.ReportFilterInput) ([]models.Wallet, models.ReportFilter, error) {
+	filter := models.ReportFilter{WalletIDs: input.WalletIDs}
+	if input.StartDate != nil {
+		filter.StartDate = *input.StartDate
+	}
+	if input.EndDate != nil {
+		filter.EndDate = input.EndDate.AddDate(0, 0, 1)
+	}
+
+	wallets, err := s.walletRepo.GetAll(ctx, userId)
+	if err != nil {
+		return nil, filter, err
+	}
+	if len(input.WalletIDs) == 0 {
+		return wallets, filter, nil
+	}
+
+	byId := make(map[int]models.Wallet, len(wallets))
+	for _, w := range wallets {
+		byId[w.ID] = w
+	}
+	selected := make([]models.Wallet, 0, len(input.WalletIDs))
+	for _, id := range input.WalletIDs {
+		w, ok := byId[id]
+		if !ok {
+			return nil, filter, repository.ErrRecordNotFound
+		}
+		selected = append(selected, w)
+	}
+	return selected, filter, nil
+}
+
+// reportDate — на какую дату пересчитывать: конец периода, но не позже сегодняшнего дня
+func reportDate(filter models.ReportFilter) time.Time {
+	now := time.Now().UTC()
+	if !filter.EndDate.IsZero() && filter.EndDate.Before(now) {
+		return filter.EndDate.AddDate(0, 0, -1)
+	}
+	return now
+}
+
+func (s *ReportService) Summary(ctx context.Context, userId int, input models.ReportFilterInput) (models.SummaryReport, error) {
+	if err := input.Validate(); err != nil {
+		return models.SummaryReport{}, err
+	}
+
+	user, err := s.authRepo.GetUserById(ctx, userId)
+	if err != nil {
+		return models.SummaryReport{}, err
+	}
+	wallets, filter, err := s.reportScope(ctx, userId, input)
+	if err != nil {
+		return models.SummaryReport{}, err
+	}
+	totals, err := s.reportRepo.TotalsByCurrency(ctx, userId, filter)
+	if err != nil {
+		return models.SummaryReport{}, err
+	}
+
+	byCurrency := map[string]*models.CurrencySummary{}
+	get := func(code string) *models.CurrencySummary {
+		cs, ok := byCurrency[code]
+		if !ok {
+			cs = &models.CurrencySummary{
+				Currency: code,
+				Balance:  currency.NewMoney(0, code),
+				Income:   currency.NewMoney(0, code),
+				Expense:  currency.NewMoney(0, code),
+			}
+			byCurrency[code] = cs
+		}
+		return cs
+	}
+	for _, w := range wallets {
+		cs := get(w.Currency)
+		cs.Balance.Amount += w.Balance
+		cs.WalletCount++
+	}
+	for _, t := range totals {
+		cs := get(t.Currency)
+		cs.Income.Amount += t.Income
+		cs.Expense.Amount += t.Expense
+		cs.MovementCount += t.Count
+	}
+
+	base := user.BaseCurrency
+	report := models.SummaryReport{
+		BaseCurrency: base,
+		TotalBalance: currency.NewMoney(0, base),
+		TotalIncome:  currency.NewMoney(0, base),
+		TotalExpense: currency.NewMoney(0, base),
+		WalletCount:  len(wallets),
+		ByCurrency:   make([]models.CurrencySummary, 0, len(byCurrency)),
+	}
+
+	conv := newBaseConversion(s.converter, base)
+	date := reportDate(filter)
+	for _, cs := range byCurrency {
+		report.MovementCount += cs.MovementCount
+		report.ByCurrency = append(report.ByCurrency, *cs)
+
+		for _, pair := range []struct {
+			from int64
+			to   *int64
+		}{
+			{cs.Balance.Amount, &report.TotalBalance.Amount},
+			{cs.Income.Amount, &report.TotalIncome.Amount},
+			{cs.Expense.Amount, &report.TotalExpense.Amount},
+		} {
+			converted, ok, err := conv.convert(ctx, pair.from, cs.Currency, date)
+			if err != nil {
+				return models.SummaryReport{}, err
+			}
+			if ok {
+				*pair.to += converted
+			}
+		}
+	}
+	sort.Slice(report.ByCurrency, func(i, j int) bool {
+		return report.ByCurrency[i].Currency < report.ByCurrency[j].Currency
+	})
+
+	report.NetBalance = currency.NewMoney(report.TotalIncome.Amount-report.TotalExpense.Amount, base)
+	report.RateDate = conv.rateDate
+	report.MissingRates = conv.missingRates()
+	return report, nil
+}
+
+func (s *ReportService) Categories(ctx context.Context, userId int, input models.CategoryReportInput) (models.CategoryReport, error) {
+	if err := input.Validate(); err != nil {
+		return models.CategoryReport{}, err
+	}
+	movementType := input.Type
+	if movementType == "" {
+		movementType = "expense"
+	}
+
+	user, err := s.authRepo.GetUserById(ctx, userId)
+	if err != nil {
+		return models.CategoryReport{}, err
+	}
+	_, filter, err := s.reportScope(ctx, userId, input.ReportFilterInput)
+	if err != nil {
+		return models.CategoryReport{}, err
+	}
+	totals, err := s.reportRepo.TotalsByCategory(ctx, userId, movementType, filter)
+	if err != nil {
+		return models.CategoryReport{}, err
+	}
+
+	base := user.BaseCurrency
+	conv := newBaseConversion(s.converter, base)
+	date := reportDate(filter)
+
+	// одна категория может встречаться в нескольких валютах — сводим в одну строку
+	var order []int
+	byCategory := map[int]*models.CategorySummary{}
+	var grandTotal int64
+	for _, t := range totals {
+		converted, ok, err := conv.convert(ctx, t.Total, t.Currency, date)
+		if err != nil {
+			return models.CategoryReport{}, err
+		}
+		if !ok {
+			continue
+		}
+
+		key := 0
+		if t.CategoryID != nil {
+			key = *t.CategoryID
+		}
+		cs, exists := byCategory[key]
+		if !exists {
+			cs = &models.CategorySummary{CategoryID: t.CategoryID, Category: t.Name, Total: currency.NewMoney(0, base)}
+			byCategory[key] = cs
+			order = append(order, key)
+		}
+		cs.Total.Amount += converted
+		cs.Count += t.Count
+		grandTotal += converted
+	}
+
+	report := models.CategoryReport{
+		Type:         movementType,
+		BaseCurrency: base,
+		Data:         make([]models.CategorySummary, 0, len(order)),
+		GrandTotal:   currency.NewMoney(grandTotal, base),
+		RateDate:     conv.rateDate,
+		MissingRates: conv.missingRates(),
+	}
+	for _, key := range order {
+		cs := byCategory[key]
+		if grandTotal != 0 {
+			cs.Percent = roundPercent(float64(cs.Total.Amount) / float64(grandTotal) * 100)
+		}
+		report.Data = append(report.Data, *cs)
+	}
+	sort.SliceStable(report.Data, func(i, j int) bool {
+		return report.Data[i].Total.Amount > report.Data[j].Total.Amount
+	})
+	return report, nil
+}
+
+// Tags — суммы по меткам в базовой валюте. Операция без меток в отчёт не попадает.
+func (s *ReportService) Tags(ctx context.Context, userId int, input models.TagReportInput) (models.TagReport, error) {
+	if err := input.Validate(); err != nil {
+		return models.TagReport{}, err
+	}
+	movementType := input.Type
+	if movementType == "" {
+		movementType = "expense"
+	}
+
+	user, err := s.authRepo.GetUserById(ctx, userId)
+	if err != nil {
+		return models.TagReport{}, err
+	}
+	_, filter, err := s.reportScope(ctx, userId, input.ReportFilterInput)
+	if err != nil {
+		return models.TagReport{}, err
+	}
+	totals, err := s.reportRepo.TotalsByTag(ctx, userId, movementType, filter)
+	if err != nil {
+		return models.TagReport{}, err
+	}
+
+	base := user.BaseCurrency
+	conv := newBaseConversion(s.converter, base)
+	date := reportDate(filter)
+
+	report := models.TagReport{Type: movementType, BaseCurrency: base, Data: []models.TagSummary{}}
+	index := map[int]int{}
+	for _, t := range totals {
+		converted, ok, err := conv.convert(ctx, t.Total, t.Currency, date)
+		if err != nil {
+			return models.TagReport{}, err
+		}
+		if !ok {
+			continue
+		}
+		i, exists := index[t.TagID]
+		if !exists {
+			i = len(report.Data)
+			index[t.TagID] = i
+			report.Data = append(report.Data, models.TagSummary{TagID: t.TagID, Tag: t.Name, Total: currency.NewMoney(0, base)})
+		}
+		report.Data[i].Total.Amount += converted
+		report.Data[i].Count += t.Count
+	}
+	sort.SliceStable(report.Data, func(i, j int) bool {
+		return report.Data[i].Total.Amount > report.Data[j].Total.Amount
+	})
+	report.RateDate = conv.rateDate
+	report.MissingRates = conv.missingRates()
+	return report, nil
+}
+
+func (s *ReportService) Monthly(ctx context.Context, userId int, input models.ReportFilterInput) (models.MonthlyReport, error) {
+	if err := input.Validate(); err != nil {
+		return models.MonthlyReport{}, err
+	}
+
+	user, err := s.authRepo.GetUserById(ctx, userId)
+	if err != nil {
+		return models.MonthlyReport{}, err
+	}
+	_, filter, err := s.reportScope(ctx, userId, input)
+	if err != nil {
+		return models.MonthlyReport{}, err
+	}
+	totals, err := s.reportRepo.TotalsByMonth(ctx, userId, filter)
+	if err != nil {
+		return models.MonthlyReport{}, err
+	}
+
+	base := user.BaseCurrency
+	conv := newBaseConversion(s.converter, base)
+	now := time.Now().UTC()
+
+	report := models.MonthlyReport{BaseCurrency: base, Data: []models.MonthlySummary{}}
+	index := map[string]int{}
+	for _, t := range totals {
+		month := t.Month.Format("2006-01")
+		i, ok := index[month]
+		if !ok {
+			i = len(report.Data)
+			index[month] = i
+			report.Data = append(report.Data, models.MonthlySummary{
+				Month:   month,
+				Income:  currency.NewMoney(0, base),
+				Expense: currency.NewMoney(0, base),
+			})
+		}
+
+		// курс на последний день месяца, для текущего месяца — на сегодня
+		date := t.Month.AddDate(0, 1, -1)
+		if date.After(now) {
+			date = now
+		}
+		income, ok, err := conv.convert(ctx, t.Income, t.Currency, date)
+		if err != nil {
+			return models.MonthlyReport{}, err
+		}
+		if !ok {
+			continue
+		}
+		expense, _, err := conv.convert(ctx, t.Expense, t.Currency, date)
+		if err != nil {
+			return models.MonthlyReport{}, err
+		}
+		report.Data[i].Income.Amount += income
+		report.Data[i].Expense.Amount += expense
+	}
+	for i := range report.Data {
+		report.Data[i].NetChange = currency.NewMoney(report.Data[i].Income.Amount-report.Data[i].Expense.Amount, base)
+	}
+	report.MissingRates = conv.missingRates()
+	return report, nil
+}
+
+func (s *ReportService) WalletStats(ctx context.Context, userId, walletId int, input models.ReportPeriodInput) (models.WalletStats, error) {
+	if err := input.Validate(); err != nil {
+		return models.WalletStats{}, err
+	}
+
+	wallet, err := s.walletRepo.GetById(ctx, userId, walletId)
+	if err != nil {
+		return models.WalletStats{}, err
+	}
+
+	filter := models.ReportFilter{WalletIDs: []int{walletId}}
+	if input.StartDate != nil {
+		filter.StartDate = *input.StartDate
+	}
+	if input.EndDate != nil {
+		filter.EndDate = input.EndDate.AddDate(0, 0, 1)
+	}
+	totals, err := s.reportRepo.TotalsByCurrency(ctx, userId, filter)
+	if err != nil {
+		return models.WalletStats{}, err
+	}
+
+	stats := models.WalletStats{
+		WalletID:     wallet.ID,
+		WalletName:   wallet.Name,
+		Balance:      currency.NewMoney(wallet.Balance, wallet.Currency),
+		Currency:     wallet.Currency,
+		TotalIncome:  currency.NewMoney(0, wallet.Currency),
+		TotalExpense: currency.NewMoney(0, wallet.Currency),
+	}
+	for _, t := range totals {
+		stats.TotalIncome.Amount += t.Income
+		stats.TotalExpense.Amount += t.Expense
+		stats.MovementCount += t.Count
+		stats.LastMovement = t.LastMovement
+	}
+	return stats, nil
+}
+
+func roundPercent(p float64) float64 {
+	return math.Round(p*10) / 10
+}
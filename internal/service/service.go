@@ -2,12 +2,16 @@ package service
 
 import (
 	"context"
+	"io"
 	"log/slog"
+	"time"
 
 	"github.com/goonsorrow/finance-tracker-api/configs"
 	"github.com/goonsorrow/finance-tracker-api/internal/cache"
+	"github.com/goonsorrow/finance-tracker-api/internal/currency"
 	"github.com/goonsorrow/finance-tracker-api/internal/models"
 	"github.com/goonsorrow/finance-tracker-api/internal/repository"
+	"github.com/goonsorrow/finance-tracker-api/internal/storage"
 )
 
 type Authorization interface {
@@ -22,48 +26,181 @@ type Authorization interface {
 }
 
 type Profile interface {
-	GetMe(ctx context.Context, userId int)
+	GetMe(ctx context.Context, userId int) (models.Profile, error)
 }
 
 type Wallet interface {
 	Create(ctx context.Context, userId int, wallet models.CreateWalletInput) (int, error)
 	GetAll(ctx context.Context, userId int) ([]models.Wallet, error)
 	GetById(ctx context.Context, userId, walletId int) (models.Wallet, error)
-	Delete(ctx context.Context, userId, walletId int) error
-	Update(ctx context.Context, userId, walletId int, input models.UpdateWalletInput) error
+	Delete(ctx context.Context, userId, walletId int, version *int) error
+	Update(ctx context.Context, userId, walletId int, input models.UpdateWalletInput, version *int) error
 }
 type Movement interface {
 	Create(ctx context.Context, userId int, walletId int, movement models.CreateMovementInput) (int, error)
-	GetAll(ctx context.Context, userId, walletId int) ([]models.Movement, error)
+	GetAll(ctx context.Context, userId, walletId int, input models.MovementFilterInput) (models.MovementPage, error)
+	List(ctx context.Context, userId int, input models.MovementCursorInput) (models.MovementCursorPage, error)
+	Search(ctx context.Context, userId int, input models.MovementSearchInput) (models.MovementSearchPage, error)
 	GetById(ctx context.Context, userId, walletId, movementId int) (models.Movement, error)
-	Delete(ctx context.Context, userId, walletId, movementId int) error
-	Update(ctx context.Context, userId, walletId, movementId int, input models.UpdateMovementInput) error
+	Delete(ctx context.Context, userId, walletId, movementId int, version *int) error
+	Update(ctx context.Context, userId, walletId, movementId int, input models.UpdateMovementInput, version *int) error
+}
+
+type Transfer interface {
+	Create(ctx context.Context, userId int, input models.CreateTransferInput) (int, error)
+	GetAll(ctx context.Context, userId int) ([]models.Transfer, error)
+	GetById(ctx context.Context, userId, transferId int) (models.Transfer, error)
+	Update(ctx context.Context, userId, transferId int, input models.UpdateTransferInput) error
+	Delete(ctx context.Context, userId, transferId int) error
+	FXReport(ctx context.Context, userId int, input models.ReportPeriodInput) (models.FXReport, error)
 }
 
 type Category interface {
 	Create(ctx context.Context, userId int, input models.CreateCategoryInput) (int, error)
 	GetAll(ctx context.Context, userId int) ([]models.Category, error)
 	GetById(ctx context.Context, userId, categoryId int) (models.Category, error)
-	Update(ctx context.Context, userId, categoryId int, input models.UpdateCategoryInput) error
-	Delete(ctx context.Context, userId, categoryId int) error
+	Update(ctx context.Context, userId, categoryId int, input models.UpdateCategoryInput, version *int) error
+	Delete(ctx context.Context, userId, categoryId int, version *int) error
+}
+
+type Tag interface {
+	Create(ctx context.Context, userId int, input models.CreateTagInput) (int, error)
+	GetAll(ctx context.Context, userId int) ([]models.Tag, error)
+	GetById(ctx context.Context, userId, tagId int) (models.Tag, error)
+	Update(ctx context.Context, userId, tagId int, input models.UpdateTagInput) error
+	Delete(ctx context.Context, userId, tagId int) error
+}
+
+type Report interface {
+	Summary(ctx context.Context, userId int, input models.ReportFilterInput) (models.SummaryReport, error)
+	Categories(ctx context.Context, userId int, input models.CategoryReportInput) (models.CategoryReport, error)
+	Monthly(ctx context.Context, userId int, input models.ReportFilterInput) (models.MonthlyReport, error)
+	Tags(ctx context.Context, userId int, input models.TagReportInput) (models.TagReport, error)
+	WalletStats(ctx context.Context, userId, walletId int, input models.ReportPeriodInput) (models.WalletStats, error)
+}
+
+type Budget interface {
+	Create(ctx context.Context, userId int, input models.CreateBudgetInput) (int, error)
+	GetAll(ctx context.Context, userId int) ([]models.Budget, error)
+	GetById(ctx context.Context, userId, budgetId int) (models.Budget, error)
+	Update(ctx context.Context, userId, budgetId int, input models.UpdateBudgetInput) error
+	Delete(ctx context.Context, userId, budgetId int) error
+	Status(ctx context.Context, userId int, input models.BudgetPeriodInput) ([]models.BudgetStatus, error)
+	History(ctx context.Context, userId, budgetId int, input models.BudgetPeriodInput) ([]models.BudgetStatus, error)
+}
+
+type Recurring interface {
+	Create(ctx context.Context, userId int, input models.CreateRecurringInput) (int, error)
+	GetAll(ctx context.Context, userId int) ([]models.RecurringMovement, error)
+	GetById(ctx context.Context, userId, recurringId int) (models.RecurringMovement, error)
+	Delete(ctx context.Context, userId, recurringId int) error
+	Pause(ctx context.Context, userId, recurringId int) error
+	Resume(ctx context.Context, userId, recurringId int) error
+	Skip(ctx context.Context, userId, recurringId int, input models.SkipOccurrenceInput) (time.Time, error)
+	Preview(ctx context.Context, userId, recurringId int, input models.RecurringPreviewInput) ([]models.RecurringOccurrence, error)
+	MaterializeDue(ctx context.Context) error
+}
+
+type Import interface {
+	ImportCSV(ctx context.Context, userId, walletId int, file io.Reader, mapping models.CSVMapping, opts models.ImportOptions) (models.ImportResult, error)
+	ImportStatement(ctx context.Context, userId, walletId int, file io.Reader, input models.StatementImportInput, opts models.ImportOptions) (models.ImportResult, error)
+}
+
+type Export interface {
+	Movements(ctx context.Context, userId int, input models.MovementExportInput) (func(w io.Writer) error, error)
+}
+
+type Backup interface {
+	Export(ctx context.Context, userId int) (models.Backup, error)
+	Restore(ctx context.Context, userId int, backup models.Backup) (models.RestoreResult, error)
+}
+
+type Attachment interface {
+	Upload(ctx context.Context, userId, walletId, movementId int, upload models.AttachmentUpload) (models.Attachment, error)
+	GetAll(ctx context.Context, userId, walletId, movementId int) ([]models.Attachment, error)
+	Open(ctx context.Context, userId, walletId, movementId, attachmentId int) (models.Attachment, io.ReadCloser, error)
+	Delete(ctx context.Context, userId, walletId, movementId, attachmentId int) error
+}
+
+type Trash interface {
+	List(ctx context.Context, userId int) (models.Trash, error)
+	RestoreWallet(ctx context.Context, userId, walletId int) error
+	RestoreMovement(ctx context.Context, userId, movementId int) error
+	RestoreCategory(ctx context.Context, userId, categoryId int) error
+	Purge(ctx context.Context) error
+}
+
+type Audit interface {
+	History(ctx context.Context, userId int, entity string, entityId int, input models.AuditHistoryInput) ([]models.AuditEntry, error)
+}
+
+type Integrity interface {
+	Check(ctx context.Context) ([]models.BalanceMismatch, error)
+	Repair(ctx context.Context) ([]models.BalanceMismatch, error)
+	Verify(ctx context.Context) error
+}
+
+type Idempotency interface {
+	Begin(ctx context.Context, userId int, key, requestHash string) (*models.IdempotencyRecord, error)
+	Complete(ctx context.Context, userId int, key, requestHash string, record models.IdempotencyRecord) error
+	Abort(ctx context.Context, userId int, key string) error
+}
+
+type Rates interface {
+	GetRate(ctx context.Context, base, quote string, date time.Time) (currency.Rate, error)
 }
 
 type Service struct {
 	Authorization
 	Wallet
 	Movement
+	Transfer
 	Category
+	Tag
 	Profile
+	Rates
+	Report
+	Budget
+	Recurring
+	Import
+	Export
+	Backup
+	Attachment
+	Trash
+	Audit
+	Integrity
+	Idempotency
 	logger *slog.Logger
 }
 
-func NewService(repos *repository.Repository, cache *cache.Cache, logger *slog.Logger, cfg configs.Config) *Service {
+func NewService(repos *repository.Repository, cache *cache.Cache, blobs storage.BlobStore, logger *slog.Logger, cfg configs.Config) *Service {
+	converter := currency.NewConverter(repos.Rates, cfg.Rates.Base)
+	duplicates := NewDuplicateDetector(repos.Movement, cfg.Duplicates, logger)
+	attachments := NewAttachmentCleaner(repos.Attachment, blobs, logger)
+	auditor := NewAuditor(repos.Audit)
+	ledger := NewLedger(repos.Ledger)
+	movements := NewMovementService(repos.Wallet, repos.Category, repos.Tag, repos.Transactor, repos.Movement, duplicates, auditor, ledger, logger)
+
 	return &Service{
 		Authorization: NewAuthService(repos.Authorization, cache.Authorization, logger, cfg.JWT),
-		Wallet:        NewWalletService(repos.Wallet, repos.Movement, repos.Transactor, logger),
-		Movement:      NewMovementService(repos.Wallet, repos.Category, repos.Transactor, repos.Movement, logger),
-		Category:      NewCategoryService(repos.Category, repos.Transactor, logger),
-		Profile:       NewProfileService(logger),
+		Wallet:        NewWalletService(repos.Wallet, repos.Movement, auditor, ledger, repos.Transactor, logger),
+		Movement:      movements,
+		Transfer:      NewTransferService(repos.Transfer, repos.Movement, repos.Wallet, repos.Authorization, attachments, converter, auditor, ledger, repos.Transactor, logger),
+		Category:      NewCategoryService(repos.Category, auditor, repos.Transactor, logger),
+		Tag:           NewTagService(repos.Tag, logger),
+		Profile:       NewProfileService(repos.Authorization, repos.Wallet, converter, logger),
+		Rates:         NewRateService(converter, logger),
+		Report:        NewReportService(repos.Report, repos.Wallet, repos.Authorization, converter, logger),
+		Budget:        NewBudgetService(repos.Budget, repos.Category, repos.Authorization, converter, logger),
+		Recurring:     NewRecurringService(repos.Recurring, repos.Wallet, movements, repos.Transactor, logger),
+		Import:        NewImportService(repos.Wallet, repos.Category, repos.Movement, repos.Transactor, movements, duplicates, logger),
+		Export:        NewExportService(repos.Wallet, repos.Movement, logger),
+		Backup:        NewBackupService(repos.Backup, repos.Authorization, repos.Tag, repos.Wallet, repos.Transfer, repos.Movement, repos.Budget, repos.Recurring, auditor, ledger, repos.Transactor, logger),
+		Attachment:    NewAttachmentService(repos.Attachment, repos.Movement, blobs, cfg.Storage, logger),
+		Trash:         NewTrashService(repos.Wallet, repos.Movement, repos.Category, repos.Attachment, repos.Trash, attachments, auditor, ledger, repos.Transactor, cfg.Trash, logger),
+		Audit:         NewAuditService(repos.Audit, logger),
+		Integrity:     NewIntegrityService(repos.Integrity, ledger, auditor, repos.Transactor, logger),
+		Idempotency:   NewIdempotencyService(cache.Idempotency, cfg.Idempotency, logger),
 		logger:        logger,
 	}
 }
@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/goonsorrow/finance-tracker-api/internal/models"
+	"github.com/goonsorrow/finance-tracker-api/internal/repository"
+)
+
+var ErrInvalidTags = errors.New("invalid tags")
+
+type TagService struct {
+	repo   repository.Tag
+	logger *slog.Logger
+}
+
+func NewTagService(tagRepo repository.Tag, logger *slog.Logger) *TagService {
+	return &TagService{repo: tagRepo, logger: logger}
+}
+
+func (s *TagService) Create(ctx context.Context, userId int, input models.CreateTagInput) (int, error) {
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return 0, fmt.Errorf("%w: name must not be empty", ErrInvalidTags)
+	}
+	return s.repo.Create(ctx, userId, models.Tag{Name: name, Color: input.Color})
+}
+
+func (s *TagService) GetAll(ctx context.Context, userId int) ([]models.Tag, error) {
+	tags, err := s.repo.GetAll(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	if tags == nil {
+		tags = []models.Tag{}
+	}
+	return tags, nil
+}
+
+func (s *TagService) GetById(ctx context.Context, userId, tagId int) (models.Tag, error) {
+	return s.repo.GetById(ctx, userId, tagId)
+}
+
+func (s *TagService) Update(ctx context.Context, userId, tagId int, input models.UpdateTagInput) error {
+	if err := input.Validate(); err != nil {
+		return err
+	}
+	if input.Name != nil {
+		name := strings.TrimSpace(*input.Name)
+		if name == "" {
+			return fmt.Errorf("%w: name must not be empty", ErrInvalidTags)
+		}
+		input.Name = &name
+	}
+	return s.repo.Update(ctx, userId, tagId, input)
+}
+
+func (s *TagService) Delete(ctx context.Context, userId, tagId int) error {
+	return s.repo.Delete(ctx, userId, tagId)
+}
+
+// checkTags убирает повторы и проверяет, что все метки принадлежат пользователю
+func checkTags(ctx context.Context, tagRepo repository.Tag, userId int, ids []int) ([]int, error) {
+	unique := make([]int, 0, len(ids))
+	seen := make(map[int]bool, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+
+	owned, err := tagRepo.OwnedIDs(ctx, userId, unique)
+	if err != nil {
+		return nil, err
+	}
+	if len(owned) != len(unique) {
+		found := make(map[int]bool, len(owned))
+		for _, id := range owned {
+			found[id] = true
+		}
+		for _, id := range unique {
+			if !found[id] {
+				return nil, fmt.Errorf("%w: tag %d not found", ErrInvalidTags, id)
+			}
+		}
+	}
+	return unique, nil
+}
@@ -0,0 +1,345 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/goonsorrow/finance-tracker-api/internal/currency"
+	"github.com/goonsorrow/finance-tracker-api/internal/models"
+	"github.com/goonsorrow/finance-tracker-api/internal/repository"
+)
+
+var ErrInvalidTransferRate = errors.New("invalid transfer amounts")
+
+type TransferService struct {
+	transferRepo repository.Transfer
+	movementRepo repository.Movement
+	walletRepo   repository.Wallet
+	authRepo     repository.Authorization
+	attachments  *AttachmentCleaner
+	converter    *currency.Converter
+	auditor      *Auditor
+	ledger       *Ledger
+	transactor   repository.Transactor
+	logger       *slog.Logger
+}
+
+func NewTransferService(transferRepo repository.Transfer, movementRepo repository.Movement, walletRepo repository.Wallet, authRepo repository.Authorization, attachments *AttachmentCleaner, converter *currency.Converter, auditor *Auditor, ledger *Ledger, transactor repository.Transactor, logger *slog.Logger) *TransferService {
+	return &TransferService{transferRepo: transferRepo, movementRepo: movementRepo, walletRepo: walletRepo, authRepo: authRepo, attachments: attachments, converter: converter, auditor: auditor, ledger: ledger, transactor: transactor, logger: logger}
+}
+
+func (s *TransferService) Create(ctx context.Context, userId int, input models.CreateTransferInput) (int, error) {
+	if err := input.Validate(); err != nil {
+		return 0, err
+	}
+
+	fromWallet, err := s.walletRepo.GetById(ctx, userId, input.FromWalletID)
+	if err != nil {
+		return 0, err
+	}
+	toWallet, err := s.walletRepo.GetById(ctx, userId, input.ToWalletID)
+	if err != nil {
+		return 0, err
+	}
+	if !currency.IsSupported(fromWallet.Currency) || !currency.IsSupported(toWallet.Currency) {
+		return 0, fmt.Errorf("%w: %s -> %s, supported: %v", ErrInvalidTransferRate, fromWallet.Currency, toWallet.Currency, currency.SupportedCurrencies)
+	}
+
+	fromAmount, err := input.Amount.Minor(fromWallet.Currency)
+	if err != nil {
+		return 0, err
+	}
+	toAmount, rate, err := resolveTransferAmounts(fromAmount, fromWallet.Currency, toWallet.Currency, input.ToAmount, input.Rate)
+	if err != nil {
+		return 0, err
+	}
+
+	var transferId int
+
+	err = s.transactor.WithinTransaction(ctx, func(txCtx context.Context) error {
+		transfer := models.Transfer{
+			UserID:       userId,
+			FromWalletID: input.FromWalletID,
+			ToWalletID:   input.ToWalletID,
+			FromAmount:   fromAmount,
+			ToAmount:     toAmount,
+			FromCurrency: fromWallet.Currency,
+			ToCurrency:   toWallet.Currency,
+			Rate:         rate,
+			Description:  input.Description,
+			Date:         input.Date,
+		}
+
+		id, err := s.transferRepo.Create(txCtx, userId, transfer)
+		if err != nil {
+			return err
+		}
+		transferId = id
+
+		legs := []models.Movement{
+			{WalletID: input.FromWalletID, Type: "transfer_out", Amount: fromAmount, Currency: fromWallet.Currency},
+			{WalletID: input.ToWalletID, Type: "transfer_in", Amount: toAmount, Currency: toWallet.Currency},
+		}
+		for _, leg := range legs {
+			leg.UserId = userId
+			leg.Description = input.Description
+			leg.Date = input.Date
+			leg.TransferID = &transferId
+
+			if leg.ID, err = s.movementRepo.Create(txCtx, userId, leg.WalletID, leg); err != nil {
+				return fmt.Errorf("failed to create transfer movement: %w", err)
+			}
+			if err := s.ledger.Post(txCtx, leg); err != nil {
+				return fmt.Errorf("failed to post transfer movement: %w", err)
+			}
+			created, err := s.movementRepo.GetById(txCtx, userId, leg.WalletID, leg.ID)
+			if err != nil {
+				return err
+			}
+			if err := s.auditor.Record(txCtx, userId, models.AuditMovement, leg.ID, models.AuditCreate, nil, created); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return transferId, nil
+}
+
+// resolveTransferAmounts считает сумму зачисления в валюте получателя и фактический курс.
+// В одной валюте суммы ног совпадают, между валютами нужен to_amount или rate.
+func resolveTransferAmounts(fromAmount int64, fromCurrency, toCurrency string, toAmount *currency.Decimal, rate *float64) (int64, float64, error) {
+	var to int64
+	if toAmount != nil {
+		var err error
+		if to, err = toAmount.Minor(toCurrency); err != nil {
+			return 0, 0, err
+		}
+	}
+
+	if fromCurrency == toCurrency {
+		if (toAmount != nil && to != fromAmount) || (rate != nil && *rate != 1) {
+			return 0, 0, fmt.Errorf("%w: to_amount and rate are only used between wallets in different currencies", ErrInvalidTransferRate)
+		}
+		return fromAmount, 1, nil
+	}
+
+	var effectiveRate float64
+	switch {
+	case toAmount != nil:
+		effectiveRate = currency.ImpliedRate(fromAmount, fromCurrency, to, toCurrency)
+	case rate != nil:
+		to = currency.Convert(fromAmount, fromCurrency, toCurrency, *rate)
+		effectiveRate = *rate
+	default:
+		return 0, 0, fmt.Errorf("%w: to_amount or rate is required between wallets in different currencies", ErrInvalidTransferRate)
+	}
+	if to <= 0 {
+		return 0, 0, fmt.Errorf("%w: received amount rounds to zero", ErrInvalidTransferRate)
+	}
+	return to, effectiveRate, nil
+}
+
+func (s *TransferService) GetAll(ctx context.Context, userId int) ([]models.Transfer, error) {
+	return s.transferRepo.GetAll(ctx, userId)
+}
+
+func (s *TransferService) GetById(ctx context.Context, userId, transferId int) (models.Transfer, error) {
+	transfer, err := s.transferRepo.GetById(ctx, userId, transferId)
+	if err != nil {
+		return models.Transfer{}, err
+	}
+
+	transfer.Movements, err = s.movementRepo.GetByTransferId(ctx, userId, transferId)
+	if err != nil {
+		return models.Transfer{}, err
+	}
+	return transfer, nil
+}
+
+func (s *TransferService) Update(ctx context.Context, userId, transferId int, input models.UpdateTransferInput) error {
+	if err := input.Validate(); err != nil {
+		return err
+	}
+
+	return s.transactor.WithinTransaction(ctx, func(txCtx context.Context) error {
+		transfer, err := s.transferRepo.GetById(txCtx, userId, transferId)
+		if err != nil {
+			return err
+		}
+
+		data := models.UpdateTransferData{
+			Description: input.Description,
+			Date:        input.Date,
+		}
+
+		newFrom, newTo := transfer.FromAmount, transfer.ToAmount
+		if input.Amount != nil || input.ToAmount != nil || input.Rate != nil {
+			if input.Amount != nil {
+				if newFrom, err = input.Amount.Minor(transfer.FromCurrency); err != nil {
+					return err
+				}
+			}
+			// при смене только суммы списания сохраняем ранее зафиксированный курс
+			rate := input.Rate
+			if input.ToAmount == nil && rate == nil && transfer.FromCurrency != transfer.ToCurrency {
+				rate = &transfer.Rate
+			}
+			to, effectiveRate, err := resolveTransferAmounts(newFrom, transfer.FromCurrency, transfer.ToCurrency, input.ToAmount, rate)
+			if err != nil {
+				return err
+			}
+			newTo = to
+			data.FromAmount = &newFrom
+			data.ToAmount = &newTo
+			data.Rate = &effectiveRate
+		}
+
+		if err := s.transferRepo.Update(txCtx, userId, transferId, data); err != nil {
+			return err
+		}
+
+		legs, err := s.movementRepo.GetByTransferId(txCtx, userId, transferId)
+		if err != nil {
+			return err
+		}
+		for _, leg := range legs {
+			before := leg
+			newAmount := newFrom
+			if leg.Type == "transfer_in" {
+				newAmount = newTo
+			}
+
+			legData := models.UpdateMovementData{
+				Amount:      &newAmount,
+				Description: data.Description,
+				Date:        data.Date,
+			}
+			if err := s.movementRepo.Update(txCtx, userId, leg.WalletID, leg.ID, legData); err != nil {
+				return fmt.Errorf("failed to update transfer movement: %w", err)
+			}
+
+			leg.Amount = newAmount
+			if err := s.ledger.Post(txCtx, leg); err != nil {
+				return fmt.Errorf("failed to post transfer movement: %w", err)
+			}
+			updated, err := s.movementRepo.GetById(txCtx, userId, leg.WalletID, leg.ID)
+			if err != nil {
+				return err
+			}
+			if err := s.auditor.Record(txCtx, userId, models.AuditMovement, leg.ID, models.AuditUpdate, before, updated); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (s *TransferService) Delete(ctx context.Context, userId, transferId int) error {
+	var keys []string
+	err := s.transactor.WithinTransaction(ctx, func(txCtx context.Context) error {
+		legs, err := s.movementRepo.GetByTransferId(txCtx, userId, transferId)
+		if err != nil {
+			return err
+		}
+		legIds := make([]int, 0, len(legs))
+		for _, leg := range legs {
+			legIds = append(legIds, leg.ID)
+			if err := s.auditor.Record(txCtx, userId, models.AuditMovement, leg.ID, models.AuditDelete, leg, nil); err != nil {
+				return err
+			}
+		}
+		if keys, err = s.attachments.MovementKeys(txCtx, legIds...); err != nil {
+			return err
+		}
+
+		// ноги перевода удаляются каскадом вместе со своими записями в журнале
+		return s.transferRepo.Delete(txCtx, userId, transferId)
+	})
+	if err != nil {
+		return err
+	}
+	s.attachments.Remove(ctx, keys)
+	return nil
+}
+
+func (s *TransferService) FXReport(ctx context.Context, userId int, input models.ReportPeriodInput) (models.FXReport, error) {
+	if err := input.Validate(); err != nil {
+		return models.FXReport{}, err
+	}
+
+	var from, to *time.Time
+	if input.StartDate != nil {
+		from = input.StartDate
+	}
+	if input.EndDate != nil {
+		end := input.EndDate.AddDate(0, 0, 1)
+		to = &end
+	}
+
+	user, err := s.authRepo.GetUserById(ctx, userId)
+	if err != nil {
+		return models.FXReport{}, err
+	}
+	transfers, err := s.transferRepo.FXTransfers(ctx, userId, from, to)
+	if err != nil {
+		return models.FXReport{}, err
+	}
+
+	base := user.BaseCurrency
+	report := models.FXReport{
+		BaseCurrency:  base,
+		Pairs:         []models.FXPairSummary{},
+		NetByCurrency: map[string]currency.Money{},
+		TotalGain:     currency.NewMoney(0, base),
+	}
+	conv := newBaseConversion(s.converter, base)
+	net := map[string]int64{}
+	// переводы отсортированы по паре валют, поэтому пара собирается подряд
+	var pair *models.FXPairSummary
+	for _, t := range transfers {
+		if pair == nil || pair.FromCurrency != t.FromCurrency || pair.ToCurrency != t.ToCurrency {
+			report.Pairs = append(report.Pairs, models.FXPairSummary{
+				FromCurrency: t.FromCurrency,
+				ToCurrency:   t.ToCurrency,
+				Gain:         currency.NewMoney(0, base),
+			})
+			pair = &report.Pairs[len(report.Pairs)-1]
+		}
+		pair.Count++
+		pair.Sent += t.FromAmount
+		pair.Received += t.ToAmount
+		net[t.FromCurrency] -= t.FromAmount
+		net[t.ToCurrency] += t.ToAmount
+
+		// обе ноги по справочному курсу на дату перевода: разница — выигрыш или потеря на обмене
+		sent, ok, err := conv.convert(ctx, t.FromAmount, t.FromCurrency, t.Date)
+		if err != nil {
+			return models.FXReport{}, err
+		}
+		received, receivedOk, err := conv.convert(ctx, t.ToAmount, t.ToCurrency, t.Date)
+		if err != nil {
+			return models.FXReport{}, err
+		}
+		if !ok || !receivedOk {
+			pair.Unvalued++
+			continue
+		}
+		pair.Gain.Amount += received - sent
+		report.TotalGain.Amount += received - sent
+	}
+	for i := range report.Pairs {
+		p := &report.Pairs[i]
+		p.AvgRate = currency.ImpliedRate(p.Sent, p.FromCurrency, p.Received, p.ToCurrency)
+	}
+	for code, amount := range net {
+		report.NetByCurrency[code] = currency.NewMoney(amount, code)
+	}
+	report.MissingRates = conv.missingRates()
+	return report, nil
+}
@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/goonsorrow/finance-tracker-api/configs"
+	"github.com/goonsorrow/finance-tracker-api/internal/models"
+	"github.com/goonsorrow/finance-tracker-api/internal/repository"
+)
+
+var ErrWalletInTrash = errors.New("wallet is in the trash, restore it first")
+
+type TrashService struct {
+	walletRepo     repository.Wallet
+	movementRepo   repository.Movement
+	categoryRepo   repository.Category
+	attachmentRepo repository.Attachment
+	trashRepo      repository.Trash
+	attachments    *AttachmentCleaner
+	auditor        *Auditor
+	ledger         *Ledger
+	transactor     repository.Transactor
+	retention      time.Duration
+	logger         *slog.Logger
+}
+
+func NewTrashService(walletRepo repository.Wallet, movementRepo repository.Movement, categoryRepo repository.Category, attachmentRepo repository.Attachment, trashRepo repository.Trash, attachments *AttachmentCleaner, auditor *Auditor, ledger *Ledger, transactor repository.Transactor, cfg configs.TrashConfig, logger *slog.Logger) *TrashService {
+	retention, err := time.ParseDuration(cfg.Retention)
+	if err != nil || retention <= 0 {
+		logger.Warn("invalid trash retention config, using default 720h", "value", cfg.Retention)
+		retention = 720 * time.Hour
+	}
+	return &TrashService{walletRepo: walletRepo, movementRepo: movementRepo, categoryRepo: categoryRepo, attachmentRepo: attachmentRepo,
+		trashRepo: trashRepo, attachments: attachments, auditor: auditor, ledger: ledger, transactor: transactor, retention: retention, logger: logger}
+}
+
+func (s *TrashService) List(ctx context.Context, userId int) (models.Trash, error) {
+	trash := models.Trash{Wallets: []models.Wallet{}, Movements: []models.Movement{}, Categories: []models.Category{}}
+
+	wallets, err := s.walletRepo.GetDeleted(ctx, userId)
+	if err != nil {
+		return models.Trash{}, err
+	}
+	movements, err := s.movementRepo.GetDeleted(ctx, userId)
+	if err != nil {
+		return models.Trash{}, err
+	}
+	categories, err := s.categoryRepo.GetDeleted(ctx, userId)
+	if err != nil {
+		return models.Trash{}, err
+	}
+
+	trash.Wallets = append(trash.Wallets, wallets...)
+	trash.Movements = append(trash.Movements, movements...)
+	trash.Categories = append(trash.Categories, categories...)
+	return trash, nil
+}
+
+// RestoreWallet возвращает кошелёк и ноги его переводов, убранные вместе с ним,
+// и снова проводит их по журналу
+func (s *TrashService) RestoreWallet(ctx context.Context, userId, walletId int) error {
+	return s.transactor.WithinTransaction(ctx, func(txCtx context.Context) error {
+		// ноги находятся по времени удаления кошелька, поэтому до его восстановления
+		legs, err := s.movementRepo.RestoreTransferLegs(txCtx, userId, walletId)
+		if err != nil {
+			return err
+		}
+		if err := s.walletRepo.Restore(txCtx, userId, walletId); err != nil {
+			return err
+		}
+		for _, leg := range legs {
+			if err := s.ledger.Post(txCtx, leg); err != nil {
+				return fmt.Errorf("failed to post transfer leg while restoring wallet: %w", err)
+			}
+			if err := s.auditor.Record(txCtx, userId, models.AuditMovement, leg.ID, models.AuditRestore, nil, leg); err != nil {
+				return err
+			}
+		}
+
+		restored, err := s.walletRepo.GetById(txCtx, userId, walletId)
+		if err != nil {
+			return err
+		}
+		return s.auditor.Record(txCtx, userId, models.AuditWallet, walletId, models.AuditRestore, nil, restored)
+	})
+}
+
+func (s *TrashService) RestoreMovement(ctx context.Context, userId, movementId int) error {
+	return s.transactor.WithinTransaction(ctx, func(txCtx context.Context) error {
+		movement, err := s.movementRepo.GetDeletedById(txCtx, userId, movementId)
+		if err != nil {
+			return err
+		}
+		if _, err := s.walletRepo.GetById(txCtx, userId, movement.WalletID); err != nil {
+			if errors.Is(err, repository.ErrRecordNotFound) {
+				return ErrWalletInTrash
+			}
+			return err
+		}
+
+		if err := s.movementRepo.Restore(txCtx, userId, movementId); err != nil {
+			return err
+		}
+		if err := s.ledger.Post(txCtx, movement); err != nil {
+			return fmt.Errorf("failed to post movement while restoring: %w", err)
+		}
+		movement.DeletedAt = nil
+		return s.auditor.Record(txCtx, userId, models.AuditMovement, movementId, models.AuditRestore, nil, movement)
+	})
+}
+
+func (s *TrashService) RestoreCategory(ctx context.Context, userId, categoryId int) error {
+	return s.transactor.WithinTransaction(ctx, func(txCtx context.Context) error {
+		if err := s.categoryRepo.Restore(txCtx, userId, categoryId); err != nil {
+			return err
+		}
+		restored, err := s.categoryRepo.GetById(txCtx, userId, categoryId)
+		if err != nil {
+			return err
+		}
+		return s.auditor.Record(txCtx, userId, models.AuditCategory, categoryId, models.AuditRestore, nil, restored)
+	})
+}
+
+// Purge окончательно удаляет всё, что лежит в корзине дольше срока хранения, у всех пользователей.
+// Файлы вложений стираются после коммита
+func (s *TrashService) Purge(ctx context.Context) error {
+	var keys []string
+	var result models.PurgeResult
+	err := s.transactor.WithinTransaction(ctx, func(txCtx context.Context) error {
+		var err error
+		if keys, err = s.attachmentRepo.ExpiredKeys(txCtx, s.retention); err != nil {
+			return err
+		}
+		result, err = s.trashRepo.Purge(txCtx, s.retention)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	s.attachments.Remove(ctx, keys)
+
+	if result.Wallets+result.Movements+result.Categories > 0 {
+		s.logger.Info("trash purged",
+			slog.Int("wallets", result.Wallets),
+			slog.Int("movements", result.Movements),
+			slog.Int("transfers", result.Transfers),
+			slog.Int("categories", result.Categories),
+			slog.Int("attachments", len(keys)))
+	}
+	return nil
+}
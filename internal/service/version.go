@@ -0,0 +1,12 @@
+package service
+
+import "github.com/goonsorrow/finance-tracker-api/internal/repository"
+
+// checkVersion сверяет версию записи с той, что клиент прислал в If-Match.
+// Без заголовка проверка не выполняется
+func checkVersion(current int, expected *int) error {
+	if expected != nil && *expected != current {
+		return repository.ErrVersionConflict
+	}
+	return nil
+}
@@ -2,26 +2,32 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
-	"math"
 	"time"
 
+	"github.com/goonsorrow/finance-tracker-api/internal/currency"
 	"github.com/goonsorrow/finance-tracker-api/internal/models"
 	"github.com/goonsorrow/finance-tracker-api/internal/repository"
 )
 
+// Суммы операций и курсы переводов записаны в валюте кошелька, при смене валюты они
+// поменяли бы смысл без всякой конвертации
+var ErrWalletCurrencyLocked = errors.New("wallet currency cannot be changed once it has movements or transfers")
+
 type WalletService struct {
-	walletRepo      repository.Wallet
-	movementRepo    repository.Movement
-	logger          *slog.Logger
-	transactor      repository.Transactor
-	validCurrencies []string
+	walletRepo   repository.Wallet
+	movementRepo repository.Movement
+	auditor      *Auditor
+	ledger       *Ledger
+	logger       *slog.Logger
+	transactor   repository.Transactor
 }
 
-func NewWalletService(walletRepo repository.Wallet, movementRepo repository.Movement, transactor repository.Transactor, logger *slog.Logger) *WalletService {
+func NewWalletService(walletRepo repository.Wallet, movementRepo repository.Movement, auditor *Auditor, ledger *Ledger, transactor repository.Transactor, logger *slog.Logger) *WalletService {
 
-	return &WalletService{walletRepo: walletRepo, movementRepo: movementRepo, logger: logger, transactor: transactor, validCurrencies: []string{"USD", "EUR", "RUB", "GBP", "JPY"}}
+	return &WalletService{walletRepo: walletRepo, movementRepo: movementRepo, auditor: auditor, ledger: ledger, logger: logger, transactor: transactor}
 }
 
 func (s *WalletService) Create(ctx context.Context, userId int, input models.CreateWalletInput) (int, error) {
@@ -31,18 +37,21 @@ func (s *WalletService) Create(ctx context.Context, userId int, input models.Cre
 	if err := s.ValidateCurrency(ctx, input.Currency); err != nil {
 		return 0, err
 	}
-	balanceInCents := int64(math.Round(input.InitialBalance * 100))
+	balance, err := input.InitialBalance.Minor(input.Currency)
+	if err != nil {
+		return 0, err
+	}
 
 	var walletId int
 
-	err := s.transactor.WithinTransaction(ctx, func(txCtx context.Context) error {
+	err = s.transactor.WithinTransaction(ctx, func(txCtx context.Context) error {
 		var err error
 
+		// начальный баланс появится из проводки начальной операции
 		wallet := models.Wallet{
 			UserID:   userId,
 			Name:     input.Name,
 			Currency: input.Currency,
-			Balance:  balanceInCents, // Пишем копейки
 		}
 
 		walletId, err = s.walletRepo.Create(txCtx, userId, wallet)
@@ -50,22 +59,34 @@ func (s *WalletService) Create(ctx context.Context, userId int, input models.Cre
 			return err
 		}
 
-		if balanceInCents != 0 {
+		if balance != 0 {
 			initialMovement := models.Movement{
 				WalletID:    walletId,
 				UserId:      userId,
 				Type:        "initial",
-				Amount:      balanceInCents,
+				Amount:      balance,
 				CategoryID:  nil,
 				Description: "Initial balance set at wallet creation",
 				Date:        time.Now(),
 			}
-			_, err = s.movementRepo.Create(txCtx, userId, walletId, initialMovement)
+			initialMovement.ID, err = s.movementRepo.Create(txCtx, userId, walletId, initialMovement)
 			if err != nil {
 				return err
 			}
+			initialMovement.Currency = input.Currency
+			if err := s.ledger.Post(txCtx, initialMovement); err != nil {
+				return err
+			}
+			if err := s.auditor.Record(txCtx, userId, models.AuditMovement, initialMovement.ID, models.AuditCreate, nil, initialMovement); err != nil {
+				return err
+			}
 		}
-		return nil
+
+		created, err := s.walletRepo.GetById(txCtx, userId, walletId)
+		if err != nil {
+			return err
+		}
+		return s.auditor.Record(txCtx, userId, models.AuditWallet, walletId, models.AuditCreate, nil, created)
 	})
 
 	if err != nil {
@@ -82,23 +103,84 @@ func (s *WalletService) GetById(ctx context.Context, userId, walletId int) (mode
 	return s.walletRepo.GetById(ctx, userId, walletId)
 }
 
-func (s *WalletService) Update(ctx context.Context, userId, walletId int, input models.UpdateWalletInput) error {
+func (s *WalletService) Update(ctx context.Context, userId, walletId int, input models.UpdateWalletInput, version *int) error {
 	if err := input.Validate(); err != nil {
 		return err
 	}
 
-	return s.walletRepo.Update(ctx, userId, walletId, input)
-}
+	if input.Currency != nil {
+		if err := s.ValidateCurrency(ctx, *input.Currency); err != nil {
+			return err
+		}
+	}
+
+	return s.transactor.WithinTransaction(ctx, func(txCtx context.Context) error {
+		before, err := s.walletRepo.GetById(txCtx, userId, walletId)
+		if err != nil {
+			return err
+		}
+		if err := checkVersion(before.Version, version); err != nil {
+			return err
+		}
+		if input.Currency != nil && *input.Currency != before.Currency {
+			hasHistory, err := s.walletRepo.HasHistory(txCtx, walletId)
+			if err != nil {
+				return err
+			}
+			if hasHistory {
+				return ErrWalletCurrencyLocked
+			}
+		}
 
-func (s *WalletService) Delete(ctx context.Context, userId, walletId int) error {
-	return s.walletRepo.Delete(ctx, userId, walletId)
+		if err := s.walletRepo.Update(txCtx, userId, walletId, input, version); err != nil {
+			return err
+		}
+		after, err := s.walletRepo.GetById(txCtx, userId, walletId)
+		if err != nil {
+			return err
+		}
+		return s.auditor.Record(txCtx, userId, models.AuditWallet, walletId, models.AuditUpdate, before, after)
+	})
 }
 
-func (s *WalletService) ValidateCurrency(ctx context.Context, currency string) error {
-	for _, valid := range s.validCurrencies {
-		if currency == valid {
-			return nil
+// Delete убирает кошелёк в корзину. Его операции скрываются вместе с ним, а ноги его переводов
+// в других кошельках уходят в корзину и снимаются с журнала
+func (s *WalletService) Delete(ctx context.Context, userId, walletId int, version *int) error {
+	return s.transactor.WithinTransaction(ctx, func(txCtx context.Context) error {
+		before, err := s.walletRepo.GetById(txCtx, userId, walletId)
+		if err != nil {
+			return err
+		}
+		if err := checkVersion(before.Version, version); err != nil {
+			return err
+		}
+		if err := s.walletRepo.Delete(txCtx, userId, walletId, version); err != nil {
+			return err
+		}
+		if err := s.auditor.Record(txCtx, userId, models.AuditWallet, walletId, models.AuditDelete, before, nil); err != nil {
+			return err
 		}
+
+		legs, err := s.movementRepo.TrashTransferLegs(txCtx, userId, walletId)
+		if err != nil {
+			return err
+		}
+		for _, leg := range legs {
+			if err := s.ledger.Unpost(txCtx, leg.ID); err != nil {
+				return fmt.Errorf("failed to unpost transfer leg while deleting wallet: %w", err)
+			}
+			if err := s.auditor.Record(txCtx, userId, models.AuditMovement, leg.ID, models.AuditDelete, leg, nil); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// ValidateCurrency пропускает только валюты, для которых есть курсы конвертации
+func (s *WalletService) ValidateCurrency(ctx context.Context, code string) error {
+	if currency.IsSupported(code) {
+		return nil
 	}
-	return fmt.Errorf("invalid currency: %s valid: %v", currency, s.validCurrencies)
+	return fmt.Errorf("invalid currency: %s valid: %v", code, currency.SupportedCurrencies)
 }
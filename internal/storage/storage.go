@@ -0,0 +1,33 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+)
+
+var ErrNotFound = errors.New("blob not found")
+
+// BlobStore хранит файлы по ключу. Ключ состоит из латинских букв, цифр, '-', '_' и '/'.
+type BlobStore interface {
+	Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// validKey не пускает в ключ ничего, что может выйти за пределы хранилища
+func validKey(key string) error {
+	if key == "" || key[0] == '/' || key[len(key)-1] == '/' {
+		return fmt.Errorf("invalid blob key %q", key)
+	}
+	prev := rune(0)
+	for _, r := range key {
+		ok := r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_' || r == '/'
+		if !ok || r == '/' && prev == '/' {
+			return fmt.Errorf("invalid blob key %q", key)
+		}
+		prev = r
+	}
+	return nil
+}
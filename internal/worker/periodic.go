@@ -0,0 +1,29 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// Run запускает job сразу и затем каждые interval, пока не отменён ctx.
+// Ошибки job логируются и не останавливают цикл.
+func Run(ctx context.Context, logger *slog.Logger, name string, interval time.Duration, job func(ctx context.Context) error) {
+	logger.Info("background job started", slog.String("job", name), slog.Duration("interval", interval))
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := job(ctx); err != nil && ctx.Err() == nil {
+			logger.Error("background job failed", slog.String("job", name), slog.String("error", err.Error()))
+		}
+
+		select {
+		case <-ctx.Done():
+			logger.Info("background job stopped", slog.String("job", name))
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,3 @@
+BEGIN;
+DROP INDEX IF EXISTS idx_movements_user_date_id;
+COMMIT;
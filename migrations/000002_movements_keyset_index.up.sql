@@ -0,0 +1,5 @@
+BEGIN;
+
+CREATE INDEX idx_movements_user_date_id ON movements(user_id, date, id);
+
+COMMIT;
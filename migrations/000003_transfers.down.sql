@@ -0,0 +1,7 @@
+BEGIN;
+DELETE FROM movements WHERE transfer_id IS NOT NULL;
+ALTER TABLE movements DROP CONSTRAINT movements_type_check;
+ALTER TABLE movements ADD CONSTRAINT movements_type_check CHECK (type IN ('income','expense','initial'));
+ALTER TABLE movements DROP COLUMN transfer_id;
+DROP TABLE IF EXISTS transfers CASCADE;
+COMMIT;
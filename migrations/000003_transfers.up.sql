@@ -0,0 +1,26 @@
+BEGIN;
+
+-- Transfers between wallets of one user
+CREATE TABLE transfers (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    from_wallet_id INT NOT NULL REFERENCES wallets(id) ON DELETE CASCADE,
+    to_wallet_id INT NOT NULL REFERENCES wallets(id) ON DELETE CASCADE,
+    amount BIGINT NOT NULL CHECK (amount > 0),
+    description TEXT NOT NULL DEFAULT '',
+    date TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CHECK (from_wallet_id <> to_wallet_id)
+);
+
+-- Each transfer is written down as two linked movements: transfer_out + transfer_in
+ALTER TABLE movements ADD COLUMN transfer_id INT REFERENCES transfers(id) ON DELETE CASCADE;
+ALTER TABLE movements DROP CONSTRAINT movements_type_check;
+ALTER TABLE movements ADD CONSTRAINT movements_type_check
+    CHECK (type IN ('income','expense','initial','transfer_in','transfer_out'));
+
+CREATE INDEX idx_transfers_user ON transfers(user_id);
+CREATE INDEX idx_movements_transfer ON movements(transfer_id);
+
+COMMIT;
@@ -0,0 +1,9 @@
+BEGIN;
+ALTER TABLE transfers DROP CONSTRAINT IF EXISTS transfers_rate_check;
+ALTER TABLE transfers DROP CONSTRAINT IF EXISTS transfers_to_amount_check;
+ALTER TABLE transfers DROP COLUMN rate;
+ALTER TABLE transfers DROP COLUMN to_currency;
+ALTER TABLE transfers DROP COLUMN from_currency;
+ALTER TABLE transfers DROP COLUMN to_amount;
+ALTER TABLE transfers RENAME COLUMN from_amount TO amount;
+COMMIT;
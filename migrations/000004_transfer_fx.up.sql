@@ -0,0 +1,23 @@
+BEGIN;
+
+-- Each leg of a transfer carries its own amount in its wallet's currency
+ALTER TABLE transfers RENAME COLUMN amount TO from_amount;
+ALTER TABLE transfers ADD COLUMN to_amount BIGINT;
+ALTER TABLE transfers ADD COLUMN from_currency VARCHAR(3);
+ALTER TABLE transfers ADD COLUMN to_currency VARCHAR(3);
+ALTER TABLE transfers ADD COLUMN rate NUMERIC(20,10) NOT NULL DEFAULT 1;
+
+UPDATE transfers t
+SET to_amount = t.from_amount,
+    from_currency = fw.currency,
+    to_currency = tw.currency
+FROM wallets fw, wallets tw
+WHERE fw.id = t.from_wallet_id AND tw.id = t.to_wallet_id;
+
+ALTER TABLE transfers ALTER COLUMN to_amount SET NOT NULL;
+ALTER TABLE transfers ALTER COLUMN from_currency SET NOT NULL;
+ALTER TABLE transfers ALTER COLUMN to_currency SET NOT NULL;
+ALTER TABLE transfers ADD CONSTRAINT transfers_to_amount_check CHECK (to_amount > 0);
+ALTER TABLE transfers ADD CONSTRAINT transfers_rate_check CHECK (rate > 0);
+
+COMMIT;
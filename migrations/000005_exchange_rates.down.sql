@@ -0,0 +1,3 @@
+BEGIN;
+DROP TABLE IF EXISTS exchange_rates CASCADE;
+COMMIT;
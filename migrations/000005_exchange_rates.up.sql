@@ -0,0 +1,15 @@
+BEGIN;
+
+-- Daily exchange rates: 1 base = rate quote
+CREATE TABLE exchange_rates (
+    id SERIAL PRIMARY KEY,
+    base VARCHAR(3) NOT NULL,
+    quote VARCHAR(3) NOT NULL,
+    date DATE NOT NULL,
+    rate NUMERIC(20,10) NOT NULL CHECK (rate > 0),
+    source VARCHAR(50) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(base, quote, date)
+);
+
+COMMIT;
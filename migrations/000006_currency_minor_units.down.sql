@@ -0,0 +1,10 @@
+BEGIN;
+UPDATE transfers SET to_amount = to_amount * 100 WHERE to_currency = 'JPY';
+UPDATE transfers SET from_amount = from_amount * 100 WHERE from_currency = 'JPY';
+
+UPDATE movements m SET amount = m.amount * 100
+FROM wallets w
+WHERE w.id = m.wallet_id AND w.currency = 'JPY';
+
+UPDATE wallets SET balance = balance * 100 WHERE currency = 'JPY';
+COMMIT;
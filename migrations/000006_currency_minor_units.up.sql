@@ -0,0 +1,21 @@
+BEGIN;
+-- JPY не имеет минимальных единиц: раньше суммы хранились умноженными на 100
+UPDATE transfers SET from_amount = GREATEST(ROUND(from_amount / 100.0), 1) WHERE from_currency = 'JPY';
+UPDATE transfers SET to_amount = GREATEST(ROUND(to_amount / 100.0), 1) WHERE to_currency = 'JPY';
+
+UPDATE movements m SET amount = ROUND(m.amount / 100.0)
+FROM wallets w
+WHERE w.id = m.wallet_id AND w.currency = 'JPY' AND m.transfer_id IS NULL;
+
+-- ноги переводов округляются так же, как сам перевод
+UPDATE movements m SET amount = CASE WHEN m.type = 'transfer_out' THEN t.from_amount ELSE t.to_amount END
+FROM wallets w, transfers t
+WHERE w.id = m.wallet_id AND w.currency = 'JPY' AND t.id = m.transfer_id;
+
+-- баланс пересчитывается из уже округлённых операций, иначе он разойдётся с их суммой
+UPDATE wallets w SET balance = COALESCE((
+    SELECT SUM(CASE WHEN m.type IN ('expense', 'transfer_out') THEN -m.amount ELSE m.amount END)
+    FROM movements m
+    WHERE m.wallet_id = w.id), 0)
+WHERE w.currency = 'JPY';
+COMMIT;
@@ -0,0 +1,18 @@
+BEGIN;
+
+-- Monthly spending limit per expense category, amount in minor units of currency
+CREATE TABLE budgets (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    category_id INT NOT NULL REFERENCES categories(id) ON DELETE CASCADE,
+    amount BIGINT NOT NULL CHECK (amount > 0),
+    currency VARCHAR(3) NOT NULL,
+    rollover BOOLEAN NOT NULL DEFAULT FALSE,
+    start_month DATE NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(user_id, category_id),
+    CHECK (start_month = date_trunc('month', start_month))
+);
+
+COMMIT;
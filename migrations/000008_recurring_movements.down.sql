@@ -0,0 +1,4 @@
+BEGIN;
+DROP TABLE IF EXISTS recurring_occurrences;
+DROP TABLE IF EXISTS recurring_movements;
+COMMIT;
@@ -0,0 +1,34 @@
+BEGIN;
+
+-- Templates for movements repeating on a schedule
+CREATE TABLE recurring_movements (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    wallet_id INT NOT NULL REFERENCES wallets(id) ON DELETE CASCADE,
+    type VARCHAR(10) NOT NULL CHECK (type IN ('income', 'expense')),
+    amount BIGINT NOT NULL CHECK (amount > 0),
+    category_id INT REFERENCES categories(id) ON DELETE SET NULL,
+    description TEXT NOT NULL DEFAULT '',
+    frequency VARCHAR(10) NOT NULL CHECK (frequency IN ('daily', 'weekly', 'monthly', 'yearly')),
+    interval_count INT NOT NULL DEFAULT 1 CHECK (interval_count > 0),
+    start_date TIMESTAMP NOT NULL,
+    end_date TIMESTAMP,
+    next_date TIMESTAMP NOT NULL,
+    paused BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_recurring_movements_due ON recurring_movements(next_date) WHERE NOT paused;
+
+-- One row per materialized or skipped occurrence; the primary key makes the scheduler idempotent
+CREATE TABLE recurring_occurrences (
+    recurring_id INT NOT NULL REFERENCES recurring_movements(id) ON DELETE CASCADE,
+    occurrence_date TIMESTAMP NOT NULL,
+    movement_id INT REFERENCES movements(id) ON DELETE SET NULL,
+    skipped BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (recurring_id, occurrence_date)
+);
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+DROP INDEX IF EXISTS idx_movements_wallet_external_id;
+
+ALTER TABLE movements DROP COLUMN IF EXISTS external_id;
+
+COMMIT;
@@ -0,0 +1,8 @@
+BEGIN;
+
+-- Bank transaction identifier (OFX FITID or a hash of the QIF entry) to skip re-imported entries
+ALTER TABLE movements ADD COLUMN external_id VARCHAR(255);
+
+CREATE UNIQUE INDEX idx_movements_wallet_external_id ON movements(wallet_id, external_id) WHERE external_id IS NOT NULL;
+
+COMMIT;
@@ -0,0 +1,15 @@
+BEGIN;
+
+-- Split lines of a movement across several categories; amounts sum up to the movement amount
+CREATE TABLE movement_splits (
+    id SERIAL PRIMARY KEY,
+    movement_id INT NOT NULL REFERENCES movements(id) ON DELETE CASCADE,
+    category_id INT NOT NULL REFERENCES categories(id),
+    amount BIGINT NOT NULL CHECK (amount > 0),
+    note VARCHAR(255) NOT NULL DEFAULT ''
+);
+
+CREATE INDEX idx_movement_splits_movement ON movement_splits(movement_id);
+CREATE INDEX idx_movement_splits_category ON movement_splits(category_id);
+
+COMMIT;
@@ -0,0 +1,22 @@
+BEGIN;
+
+-- User-owned free-form labels, many-to-many with movements
+CREATE TABLE tags (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(50) NOT NULL,
+    color VARCHAR(7),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(user_id, name)
+);
+
+CREATE TABLE movement_tags (
+    movement_id INT NOT NULL REFERENCES movements(id) ON DELETE CASCADE,
+    tag_id INT NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+    PRIMARY KEY (movement_id, tag_id)
+);
+
+CREATE INDEX idx_movement_tags_tag ON movement_tags(tag_id);
+
+COMMIT;
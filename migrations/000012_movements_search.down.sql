@@ -0,0 +1,7 @@
+BEGIN;
+
+DROP INDEX IF EXISTS idx_movements_search;
+
+ALTER TABLE movements DROP COLUMN IF EXISTS search_vector;
+
+COMMIT;
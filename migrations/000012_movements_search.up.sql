@@ -0,0 +1,9 @@
+BEGIN;
+
+-- Full-text search over descriptions; 'simple' config does no stemming, so Russian and English work alike
+ALTER TABLE movements ADD COLUMN search_vector tsvector
+    GENERATED ALWAYS AS (to_tsvector('simple', COALESCE(description, ''))) STORED;
+
+CREATE INDEX idx_movements_search ON movements USING GIN(search_vector);
+
+COMMIT;
@@ -0,0 +1,17 @@
+BEGIN;
+
+-- Files attached to movements; the content lives in the blob store under storage_key
+CREATE TABLE attachments (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    movement_id INT NOT NULL REFERENCES movements(id) ON DELETE CASCADE,
+    storage_key VARCHAR(255) NOT NULL UNIQUE,
+    filename VARCHAR(255) NOT NULL,
+    content_type VARCHAR(100) NOT NULL,
+    size BIGINT NOT NULL CHECK (size >= 0),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_attachments_movement ON attachments(movement_id);
+
+COMMIT;
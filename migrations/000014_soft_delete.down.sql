@@ -0,0 +1,22 @@
+BEGIN;
+
+DROP INDEX IF EXISTS idx_categories_deleted;
+DROP INDEX IF EXISTS idx_movements_deleted;
+DROP INDEX IF EXISTS idx_wallets_deleted;
+
+-- Trashed rows are dropped for good, otherwise the old unique constraint may not apply
+DELETE FROM movements WHERE deleted_at IS NOT NULL AND transfer_id IS NULL;
+DELETE FROM wallets WHERE deleted_at IS NOT NULL;
+DELETE FROM categories c WHERE deleted_at IS NOT NULL
+    AND NOT EXISTS (SELECT 1 FROM movements m WHERE m.category_id = c.id)
+    AND NOT EXISTS (SELECT 1 FROM movement_splits s WHERE s.category_id = c.id);
+UPDATE categories SET deleted_at = NULL;
+
+DROP INDEX IF EXISTS idx_categories_user_name_type;
+ALTER TABLE categories ADD CONSTRAINT categories_user_id_name_type_key UNIQUE (user_id, name, type);
+
+ALTER TABLE categories DROP COLUMN deleted_at;
+ALTER TABLE movements DROP COLUMN deleted_at;
+ALTER TABLE wallets DROP COLUMN deleted_at;
+
+COMMIT;
@@ -0,0 +1,16 @@
+BEGIN;
+
+-- Soft deletion: trashed rows stay until the purge job removes them after the retention period
+ALTER TABLE wallets ADD COLUMN deleted_at TIMESTAMP;
+ALTER TABLE movements ADD COLUMN deleted_at TIMESTAMP;
+ALTER TABLE categories ADD COLUMN deleted_at TIMESTAMP;
+
+-- A trashed category must not block creating a new one with the same name
+ALTER TABLE categories DROP CONSTRAINT categories_user_id_name_type_key;
+CREATE UNIQUE INDEX idx_categories_user_name_type ON categories(user_id, name, type) WHERE deleted_at IS NULL;
+
+CREATE INDEX idx_wallets_deleted ON wallets(deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX idx_movements_deleted ON movements(deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX idx_categories_deleted ON categories(deleted_at) WHERE deleted_at IS NOT NULL;
+
+COMMIT;
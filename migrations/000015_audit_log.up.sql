@@ -0,0 +1,30 @@
+BEGIN;
+
+-- Append-only journal of changes to wallets, movements and categories
+CREATE TABLE audit_log (
+    id BIGSERIAL PRIMARY KEY,
+    user_id INT NOT NULL,
+    entity VARCHAR(20) NOT NULL,
+    entity_id INT NOT NULL,
+    action VARCHAR(20) NOT NULL,
+    before JSONB,
+    after JSONB,
+    request_id VARCHAR(64),
+    ip VARCHAR(45),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_audit_log_entity ON audit_log(user_id, entity, entity_id, id);
+
+-- Entries can only be added; no FK to users so the history outlives the rows it describes
+CREATE FUNCTION audit_log_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_audit_log_append_only
+    BEFORE UPDATE OR DELETE ON audit_log
+    FOR EACH ROW EXECUTE FUNCTION audit_log_append_only();
+
+COMMIT;
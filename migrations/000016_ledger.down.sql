@@ -0,0 +1,16 @@
+BEGIN;
+
+ALTER TABLE wallets ADD COLUMN balance BIGINT NOT NULL DEFAULT 0;
+
+UPDATE wallets w
+SET balance = b.balance
+FROM wallet_balances b
+WHERE b.wallet_id = w.id;
+
+DROP VIEW IF EXISTS wallet_balances;
+DROP TABLE IF EXISTS postings;
+DROP FUNCTION IF EXISTS journal_entry_balanced();
+DROP TABLE IF EXISTS journal_entries;
+DROP TABLE IF EXISTS ledger_accounts;
+
+COMMIT;
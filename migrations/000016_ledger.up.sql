@@ -0,0 +1,108 @@
+BEGIN;
+
+-- Double-entry accounts: one per wallet, plus per-user income, expense, equity (opening balances)
+-- and transfer clearing accounts in each currency
+CREATE TABLE ledger_accounts (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    kind VARCHAR(10) NOT NULL CHECK (kind IN ('wallet','income','expense','equity','transfer')),
+    wallet_id INT REFERENCES wallets(id) ON DELETE CASCADE,
+    currency VARCHAR(3),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK ((kind = 'wallet') = (wallet_id IS NOT NULL)),
+    CHECK ((kind = 'wallet') = (currency IS NULL))
+);
+
+CREATE UNIQUE INDEX idx_ledger_accounts_wallet ON ledger_accounts(wallet_id) WHERE wallet_id IS NOT NULL;
+CREATE UNIQUE INDEX idx_ledger_accounts_user_kind ON ledger_accounts(user_id, kind, currency) WHERE wallet_id IS NULL;
+
+-- One entry per live movement; trashed movements have none
+CREATE TABLE journal_entries (
+    id BIGSERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    movement_id INT NOT NULL UNIQUE REFERENCES movements(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE postings (
+    id BIGSERIAL PRIMARY KEY,
+    entry_id BIGINT NOT NULL REFERENCES journal_entries(id) ON DELETE CASCADE,
+    account_id INT NOT NULL REFERENCES ledger_accounts(id),
+    amount BIGINT NOT NULL,
+    currency VARCHAR(3) NOT NULL
+);
+
+CREATE INDEX idx_postings_entry ON postings(entry_id);
+CREATE INDEX idx_postings_account ON postings(account_id);
+
+-- Postings of an entry must sum to zero in every currency. Checked at commit
+-- so that an entry can be written one posting at a time
+CREATE FUNCTION journal_entry_balanced() RETURNS trigger AS $$
+DECLARE
+    checked_entry BIGINT;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        checked_entry := OLD.entry_id;
+    ELSE
+        checked_entry := NEW.entry_id;
+    END IF;
+    IF EXISTS (SELECT 1 FROM postings WHERE entry_id = checked_entry GROUP BY currency HAVING SUM(amount) <> 0) THEN
+        RAISE EXCEPTION 'journal entry % does not balance', checked_entry;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE CONSTRAINT TRIGGER trg_postings_balanced
+    AFTER INSERT OR UPDATE OR DELETE ON postings
+    DEFERRABLE INITIALLY DEFERRED
+    FOR EACH ROW EXECUTE FUNCTION journal_entry_balanced();
+
+-- Backfill: every live movement is posted against the account matching its type
+INSERT INTO ledger_accounts (user_id, kind, wallet_id)
+SELECT user_id, 'wallet', id FROM wallets;
+
+INSERT INTO ledger_accounts (user_id, kind, currency)
+SELECT DISTINCT w.user_id,
+       CASE m.type WHEN 'income' THEN 'income' WHEN 'expense' THEN 'expense' WHEN 'initial' THEN 'equity' ELSE 'transfer' END,
+       w.currency
+FROM movements m
+JOIN wallets w ON w.id = m.wallet_id
+WHERE m.deleted_at IS NULL;
+
+INSERT INTO journal_entries (user_id, movement_id, created_at)
+SELECT w.user_id, m.id, m.created_at
+FROM movements m
+JOIN wallets w ON w.id = m.wallet_id
+WHERE m.deleted_at IS NULL;
+
+INSERT INTO postings (entry_id, account_id, amount, currency)
+SELECT e.id, a.id,
+       CASE WHEN m.type IN ('expense','transfer_out') THEN -m.amount ELSE m.amount END,
+       w.currency
+FROM journal_entries e
+JOIN movements m ON m.id = e.movement_id
+JOIN wallets w ON w.id = m.wallet_id
+JOIN ledger_accounts a ON a.wallet_id = w.id;
+
+INSERT INTO postings (entry_id, account_id, amount, currency)
+SELECT e.id, a.id,
+       CASE WHEN m.type IN ('expense','transfer_out') THEN m.amount ELSE -m.amount END,
+       w.currency
+FROM journal_entries e
+JOIN movements m ON m.id = e.movement_id
+JOIN wallets w ON w.id = m.wallet_id
+JOIN ledger_accounts a ON a.user_id = w.user_id AND a.wallet_id IS NULL AND a.currency = w.currency
+     AND a.kind = CASE m.type WHEN 'income' THEN 'income' WHEN 'expense' THEN 'expense' WHEN 'initial' THEN 'equity' ELSE 'transfer' END;
+
+-- Wallet balance is now the sum of postings on its account; the stored column is recomputed from movements
+CREATE VIEW wallet_balances AS
+SELECT a.wallet_id, SUM(p.amount)::BIGINT AS balance
+FROM ledger_accounts a
+JOIN postings p ON p.account_id = a.id
+WHERE a.wallet_id IS NOT NULL
+GROUP BY a.wallet_id;
+
+ALTER TABLE wallets DROP COLUMN balance;
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+ALTER TABLE categories DROP COLUMN IF EXISTS version;
+ALTER TABLE movements DROP COLUMN IF EXISTS version;
+ALTER TABLE wallets DROP COLUMN IF EXISTS version;
+
+COMMIT;
@@ -0,0 +1,9 @@
+BEGIN;
+
+-- Row versions for optimistic concurrency: bumped on every update, delete and restore,
+-- exposed as ETag and checked against If-Match
+ALTER TABLE wallets ADD COLUMN version INT NOT NULL DEFAULT 1;
+ALTER TABLE movements ADD COLUMN version INT NOT NULL DEFAULT 1;
+ALTER TABLE categories ADD COLUMN version INT NOT NULL DEFAULT 1;
+
+COMMIT;
@@ -0,0 +1,10 @@
+BEGIN;
+
+ALTER TABLE transfers DROP CONSTRAINT transfers_from_wallet_id_fkey;
+ALTER TABLE transfers DROP CONSTRAINT transfers_to_wallet_id_fkey;
+ALTER TABLE transfers ADD CONSTRAINT transfers_from_wallet_id_fkey
+    FOREIGN KEY (from_wallet_id) REFERENCES wallets(id) ON DELETE CASCADE;
+ALTER TABLE transfers ADD CONSTRAINT transfers_to_wallet_id_fkey
+    FOREIGN KEY (to_wallet_id) REFERENCES wallets(id) ON DELETE CASCADE;
+
+COMMIT;
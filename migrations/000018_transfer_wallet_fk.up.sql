@@ -0,0 +1,12 @@
+BEGIN;
+
+-- Deleting a wallet no longer cascades to transfers: the other wallet's leg would vanish
+-- together with its share of the balance. Trash purge removes such transfers explicitly
+ALTER TABLE transfers DROP CONSTRAINT transfers_from_wallet_id_fkey;
+ALTER TABLE transfers DROP CONSTRAINT transfers_to_wallet_id_fkey;
+ALTER TABLE transfers ADD CONSTRAINT transfers_from_wallet_id_fkey
+    FOREIGN KEY (from_wallet_id) REFERENCES wallets(id);
+ALTER TABLE transfers ADD CONSTRAINT transfers_to_wallet_id_fkey
+    FOREIGN KEY (to_wallet_id) REFERENCES wallets(id);
+
+COMMIT;